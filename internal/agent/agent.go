@@ -0,0 +1,345 @@
+// Package agent runs Pulse as a remote agent: it probes the targets a master
+// assigns to it and reports the results back, buffering them while the
+// master is unreachable.
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+	"github.com/wellsgz/pulse/internal/tlsutil"
+)
+
+const (
+	// defaultSyncInterval is how often the assignment is refreshed
+	defaultSyncInterval = time.Minute
+
+	// flushInterval is how often buffered results are sent
+	flushInterval = 2 * time.Second
+
+	// maxBatchSize is the largest number of results sent in one request
+	maxBatchSize = 500
+
+	// maxBackoff caps the delay between attempts while the master is unreachable
+	maxBackoff = 30 * time.Second
+
+	// requestTimeout bounds a single request to the master
+	requestTimeout = 30 * time.Second
+
+	// assignmentFile caches the last assignment in data_dir so the agent can
+	// start probing while the master is down
+	assignmentFile = "agent-assignment.json"
+)
+
+// errRejected marks a batch the master will never accept (e.g. malformed)
+var errRejected = errors.New("rejected by master")
+
+// Agent probes assigned targets and reports results to a master
+type Agent struct {
+	cfg     *config.Config
+	master  string
+	client  *http.Client
+	buffer  *buffer
+	dataDir string
+}
+
+// New creates an agent from the agent section of the configuration
+func New(cfg *config.Config) (*Agent, error) {
+	ac := cfg.Agent
+	if !ac.Enabled() {
+		return nil, fmt.Errorf("agent.master is not configured")
+	}
+
+	tlsCfg, err := tlsutil.ClientConfig(ac.CAFile, ac.CertFile, ac.KeyFile, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Agent{
+		cfg:    cfg,
+		master: strings.TrimSuffix(ac.Master, "/"),
+		client: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsCfg, Proxy: http.ProxyFromEnvironment},
+		},
+		buffer:  newBuffer(ac.BufferSize),
+		dataDir: cfg.Global.DataDir,
+	}, nil
+}
+
+// Run probes the assigned targets until ctx is cancelled. The assignment is
+// refreshed every sync_interval and the collector restarted when it changes.
+func (a *Agent) Run(ctx context.Context) error {
+	assignment, err := a.initialAssignment(ctx)
+	if err != nil {
+		return err
+	}
+
+	sendDone := make(chan struct{})
+	go func() {
+		defer close(sendDone)
+		a.sendLoop(ctx)
+	}()
+
+	syncInterval := a.cfg.Agent.SyncInterval
+	if syncInterval <= 0 {
+		syncInterval = defaultSyncInterval
+	}
+	ticker := time.NewTicker(syncInterval)
+	defer ticker.Stop()
+
+	coll, err := a.startCollector(assignment)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			coll.Stop()
+			<-sendDone
+			if buffered, _ := a.buffer.stats(); buffered > 0 {
+				log.Printf("[Agent] Stopping with %d unsent result(s)", buffered)
+			}
+			return nil
+
+		case <-ticker.C:
+			next, err := a.fetchAssignment(ctx)
+			if err != nil {
+				log.Printf("[Agent] Failed to refresh assignment: %v", err)
+				continue
+			}
+			if reflect.DeepEqual(next, assignment) {
+				continue
+			}
+
+			log.Printf("[Agent] Assignment changed, restarting probes")
+			a.cacheAssignment(next)
+			newColl, err := a.startCollector(next)
+			if err != nil {
+				log.Printf("[Agent] Keeping previous assignment: %v", err)
+				continue
+			}
+			coll.Stop()
+			coll, assignment = newColl, next
+		}
+	}
+}
+
+// initialAssignment fetches the assignment from the master, falling back to
+// the cached copy while the master is unreachable
+func (a *Agent) initialAssignment(ctx context.Context) (*Assignment, error) {
+	backoff := time.Second
+	for {
+		assignment, err := a.fetchAssignment(ctx)
+		if err == nil {
+			a.cacheAssignment(assignment)
+			return assignment, nil
+		}
+
+		if cached, cacheErr := a.loadCachedAssignment(); cacheErr == nil {
+			log.Printf("[Agent] Master unreachable (%v), using cached assignment", err)
+			return cached, nil
+		}
+
+		log.Printf("[Agent] Failed to fetch assignment: %v (retrying in %s)", err, backoff)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// startCollector starts probing an assignment; results go to the send buffer
+func (a *Agent) startCollector(assignment *Assignment) (*collector.Collector, error) {
+	cfg, err := assignment.config(a.dataDir)
+	if err != nil {
+		return nil, err
+	}
+
+	// No persistent storage: the master keeps the history
+	coll := collector.NewCollector(cfg, nil, storage.NewMemoryBuffer(0))
+	results := coll.Subscribe()
+	go func() {
+		for result := range results {
+			a.buffer.add(result)
+		}
+	}()
+	coll.Start()
+
+	log.Printf("[Agent] Probing %d target(s) for agent %s every %s", len(cfg.Targets), assignment.Agent, cfg.Global.Interval)
+	return coll, nil
+}
+
+// config builds a collector configuration from an assignment
+func (as *Assignment) config(dataDir string) (*config.Config, error) {
+	interval, err := time.ParseDuration(as.Interval)
+	if err != nil {
+		return nil, fmt.Errorf("invalid interval in assignment: %w", err)
+	}
+	timeout, err := time.ParseDuration(as.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timeout in assignment: %w", err)
+	}
+
+	return &config.Config{
+		Global: config.GlobalConfig{
+			Interval: interval,
+			Timeout:  timeout,
+			DataDir:  dataDir,
+			Pings:    as.Pings,
+		},
+		Targets: as.Targets,
+	}, nil
+}
+
+// sendLoop sends buffered results to the master until ctx is cancelled
+func (a *Agent) sendLoop(ctx context.Context) {
+	delay := flushInterval
+	unreachable := false
+	var droppedBefore int64 // Dropped count when the master became unreachable
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		for {
+			batch, seq := a.buffer.peek(maxBatchSize)
+			if len(batch) == 0 {
+				break
+			}
+
+			err := a.sendResults(ctx, batch)
+			if errors.Is(err, errRejected) {
+				log.Printf("[Agent] Dropping %d result(s): %v", len(batch), err)
+			} else if err != nil {
+				if !unreachable {
+					buffered, dropped := a.buffer.stats()
+					log.Printf("[Agent] Master unreachable, buffering results (%d buffered): %v", buffered, err)
+					unreachable, droppedBefore = true, dropped
+				}
+				delay = min(delay*2, maxBackoff)
+				break
+			}
+
+			a.buffer.remove(seq, len(batch))
+			delay = flushInterval
+			if unreachable {
+				buffered, dropped := a.buffer.stats()
+				log.Printf("[Agent] Master reachable again, sending buffered results (%d left, %d dropped while unreachable)", buffered, dropped-droppedBefore)
+				unreachable = false
+			}
+		}
+	}
+}
+
+// fetchAssignment requests this agent's assignment from the master
+func (a *Agent) fetchAssignment(ctx context.Context) (*Assignment, error) {
+	var assignment Assignment
+	if err := a.do(ctx, http.MethodGet, AssignmentPath, nil, &assignment); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
+
+// sendResults posts a batch of results to the master
+func (a *Agent) sendResults(ctx context.Context, batch []probe.ProbeResult) error {
+	var resp BatchResponse
+	return a.do(ctx, http.MethodPost, ResultsPath, ResultBatch{Results: batch}, &resp)
+}
+
+// do performs an authenticated JSON request against the master
+func (a *Agent) do(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, a.master+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+a.cfg.Agent.Token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		json.NewDecoder(io.LimitReader(resp.Body, 64*1024)).Decode(&apiErr)
+		err := fmt.Errorf("master returned %s: %s", resp.Status, apiErr.Message)
+		if resp.StatusCode == http.StatusBadRequest {
+			return fmt.Errorf("%w: %v", errRejected, err)
+		}
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// cacheAssignment stores the assignment in data_dir
+func (a *Agent) cacheAssignment(assignment *Assignment) {
+	data, err := json.MarshalIndent(assignment, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(a.dataDir, 0755); err != nil {
+		log.Printf("[Agent] Failed to cache assignment: %v", err)
+		return
+	}
+
+	path := filepath.Join(a.dataDir, assignmentFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("[Agent] Failed to cache assignment: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		log.Printf("[Agent] Failed to cache assignment: %v", err)
+	}
+}
+
+// loadCachedAssignment reads the assignment cached by a previous run
+func (a *Agent) loadCachedAssignment() (*Assignment, error) {
+	data, err := os.ReadFile(filepath.Join(a.dataDir, assignmentFile))
+	if err != nil {
+		return nil, err
+	}
+	var assignment Assignment
+	if err := json.Unmarshal(data, &assignment); err != nil {
+		return nil, err
+	}
+	return &assignment, nil
+}
@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"sync"
+
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// defaultBufferSize is the number of results kept while the master is unreachable
+const defaultBufferSize = 10000
+
+// buffer is a bounded FIFO of results waiting to be sent. When full, the
+// oldest results are dropped.
+type buffer struct {
+	results []probe.ProbeResult
+	first   uint64 // Sequence number of results[0]
+	size    int
+	dropped int64 // Results discarded because the buffer was full
+	mu      sync.Mutex
+}
+
+// newBuffer creates a buffer holding up to size results
+func newBuffer(size int) *buffer {
+	if size <= 0 {
+		size = defaultBufferSize
+	}
+	return &buffer{size: size}
+}
+
+// add appends a result, dropping the oldest one if the buffer is full
+func (b *buffer) add(result probe.ProbeResult) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.results) >= b.size {
+		b.results = b.results[1:]
+		b.first++
+		b.dropped++
+	}
+	b.results = append(b.results, result)
+}
+
+// peek returns up to n of the oldest results without removing them, and the
+// sequence number of the first one
+func (b *buffer) peek(n int) ([]probe.ProbeResult, uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if n > len(b.results) {
+		n = len(b.results)
+	}
+	batch := make([]probe.ProbeResult, n)
+	copy(batch, b.results)
+	return batch, b.first
+}
+
+// remove discards a sent batch of n results starting at sequence number seq.
+// Results of the batch already dropped for space are skipped.
+func (b *buffer) remove(seq uint64, n int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	end := seq + uint64(n)
+	if end <= b.first {
+		return
+	}
+	count := int(end - b.first)
+	if count > len(b.results) {
+		count = len(b.results)
+	}
+	b.results = append(b.results[:0:0], b.results[count:]...)
+	b.first += uint64(count)
+}
+
+// stats returns the number of buffered and dropped results
+func (b *buffer) stats() (buffered int, dropped int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.results), b.dropped
+}
@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"testing"
+
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+func result(target string) probe.ProbeResult {
+	return probe.ProbeResult{Target: target}
+}
+
+func targets(results []probe.ProbeResult) []string {
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = r.Target
+	}
+	return names
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestBufferDropsOldest(t *testing.T) {
+	b := newBuffer(3)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		b.add(result(name))
+	}
+
+	batch, _ := b.peek(10)
+	if got, want := targets(batch), []string{"c", "d", "e"}; !equal(got, want) {
+		t.Errorf("peek() = %v, want %v", got, want)
+	}
+	if buffered, dropped := b.stats(); buffered != 3 || dropped != 2 {
+		t.Errorf("stats() = %d, %d, want 3, 2", buffered, dropped)
+	}
+}
+
+func TestBufferRemoveAfterSend(t *testing.T) {
+	b := newBuffer(4)
+	for _, name := range []string{"a", "b", "c"} {
+		b.add(result(name))
+	}
+
+	batch, seq := b.peek(2)
+	if got, want := targets(batch), []string{"a", "b"}; !equal(got, want) {
+		t.Fatalf("peek() = %v, want %v", got, want)
+	}
+
+	// New results arrive while the batch is in flight; "a" is dropped for space
+	b.add(result("d"))
+	b.add(result("e"))
+
+	b.remove(seq, len(batch))
+	rest, _ := b.peek(10)
+	if got, want := targets(rest), []string{"c", "d", "e"}; !equal(got, want) {
+		t.Errorf("after remove = %v, want %v", got, want)
+	}
+
+	// Removing the same batch again is a no-op
+	b.remove(seq, len(batch))
+	rest, _ = b.peek(10)
+	if got, want := targets(rest), []string{"c", "d", "e"}; !equal(got, want) {
+		t.Errorf("after second remove = %v, want %v", got, want)
+	}
+}
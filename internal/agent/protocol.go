@@ -0,0 +1,32 @@
+package agent
+
+import (
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// API paths served by the master (relative to the master URL)
+const (
+	AssignmentPath = "/api/v1/agent/assignment"
+	ResultsPath    = "/api/v1/agent/results"
+)
+
+// Assignment is the probe configuration the master pushes to an agent
+type Assignment struct {
+	Agent    string          `json:"agent"`
+	Interval string          `json:"interval"` // Go duration, e.g. "10s"
+	Timeout  string          `json:"timeout"`
+	Pings    int             `json:"pings"`
+	Targets  []config.Target `json:"targets"`
+}
+
+// ResultBatch carries buffered probe results from an agent to the master
+type ResultBatch struct {
+	Results []probe.ProbeResult `json:"results"`
+}
+
+// BatchResponse reports how many results of a batch the master stored
+type BatchResponse struct {
+	Received int `json:"received"`
+	Accepted int `json:"accepted"`
+}
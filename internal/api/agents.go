@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/agent"
+	"github.com/wellsgz/pulse/internal/auth"
+)
+
+// GetAgents returns the state of every configured agent
+func (h *Handler) GetAgents(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	agents := h.collector.AgentStatuses()
+	c.JSON(http.StatusOK, gin.H{
+		"agents": agents,
+		"count":  len(agents),
+	})
+}
+
+// GetAgentAssignment returns the targets and probe settings for the calling
+// agent, identified by the name of its token
+func (h *Handler) GetAgentAssignment(c *gin.Context) {
+	name, ok := h.agentName(c)
+	if !ok {
+		return
+	}
+
+	targets, err := h.collector.AgentTargets(name)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, agent.Assignment{
+		Agent:    name,
+		Interval: h.config.Global.Interval.String(),
+		Timeout:  h.config.Global.Timeout.String(),
+		Pings:    h.config.Global.Pings,
+		Targets:  targets,
+	})
+}
+
+// PostAgentResults stores a batch of results reported by the calling agent
+func (h *Handler) PostAgentResults(c *gin.Context) {
+	name, ok := h.agentName(c)
+	if !ok {
+		return
+	}
+
+	var batch agent.ResultBatch
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid result batch: " + err.Error(),
+		})
+		return
+	}
+
+	accepted, err := h.collector.Ingest(name, batch.Results)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, agent.BatchResponse{
+		Received: len(batch.Results),
+		Accepted: accepted,
+	})
+}
+
+// agentName returns the agent name of the request token, writing an error
+// response if the collector is missing or the token is not a configured agent
+func (h *Handler) agentName(c *gin.Context) (string, bool) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return "", false
+	}
+
+	identity := c.MustGet(identityKey).(auth.Identity)
+	if !h.collector.IsAgent(identity.Name) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "Token " + identity.Name + " is not a configured agent (see master.agents)",
+		})
+		return "", false
+	}
+	return identity.Name, true
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/collector"
+)
+
+// GetAnomalies returns the targets currently deviating from their baseline
+func (h *Handler) GetAnomalies(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	anomalies := h.collector.Anomalies()
+	c.JSON(http.StatusOK, gin.H{
+		"anomalies": anomalies,
+		"count":     len(anomalies),
+	})
+}
+
+// GetTargetBaseline returns the latency and loss baseline a target has
+// learned, by hour of day
+func (h *Handler) GetTargetBaseline(c *gin.Context) {
+	name := c.Param("name")
+
+	found := false
+	for _, t := range h.targets() {
+		if t.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Target not found: " + name,
+		})
+		return
+	}
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	baseline := h.collector.Baseline(name)
+	if baseline == nil {
+		baseline = &collector.Baseline{Target: name, Day: collector.Profile{Hour: -1}, Hours: []collector.Profile{}}
+	}
+	c.JSON(http.StatusOK, baseline)
+}
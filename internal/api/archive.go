@@ -0,0 +1,188 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// maxImportSize limits the size of an uploaded history archive
+const maxImportSize = 256 << 20 // 256MB
+
+// ExportTargetHistory returns a target's complete history as a JSON or CSV archive.
+// The target does not need to be configured, so orphaned history can be exported too.
+func (h *Handler) ExportTargetHistory(c *gin.Context) {
+	name := c.Param("name")
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "csv" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "format must be json or csv",
+		})
+		return
+	}
+
+	archive, err := h.collector.ExportHistory(name)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Failed to export history: " + err.Error(),
+		})
+		return
+	}
+
+	filename := strings.ReplaceAll(name, `"`, "_") + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+
+	if format == "csv" {
+		var buf bytes.Buffer
+		if err := storage.WriteArchiveCSV(&buf, archive); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to encode archive: " + err.Error(),
+			})
+			return
+		}
+		c.Data(http.StatusOK, "text/csv; charset=utf-8", buf.Bytes())
+		return
+	}
+
+	c.JSON(http.StatusOK, archive)
+}
+
+// ImportTargetHistory imports a JSON or CSV archive into a target's history.
+// The mode query parameter selects create (default), replace or merge.
+func (h *Handler) ImportTargetHistory(c *gin.Context) {
+	name := c.Param("name")
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	mode := storage.ImportMode(c.DefaultQuery("mode", string(storage.ImportCreate)))
+	if mode != storage.ImportCreate && mode != storage.ImportReplace && mode != storage.ImportMerge {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "mode must be create, replace or merge",
+		})
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize))
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+			"error":   "Request Entity Too Large",
+			"message": fmt.Sprintf("Archive exceeds %d bytes", tooLarge.Limit),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Failed to read request body: " + err.Error(),
+		})
+		return
+	}
+
+	var archive *storage.Archive
+	if strings.HasPrefix(c.ContentType(), "text/csv") {
+		archive, err = storage.ReadArchiveCSV(bytes.NewReader(body), name)
+	} else {
+		archive = &storage.Archive{}
+		err = json.Unmarshal(body, archive)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid archive: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.collector.ImportHistory(name, archive, mode); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, storage.ErrTargetExists) {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{
+			"error":   http.StatusText(status),
+			"message": "Failed to import history: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "imported",
+		"target": name,
+		"mode":   mode,
+	})
+}
+
+// RenameRequest is the body of a target rename
+type RenameRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// RenameTarget renames a target at runtime. Its history keeps its storage id,
+// returned in the response; the change is not written to the config file.
+func (h *Handler) RenameTarget(c *gin.Context) {
+	name := c.Param("name")
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	var req RenameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid request body: " + err.Error(),
+		})
+		return
+	}
+
+	if err := h.collector.RenameTarget(name, req.Name); err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": "Failed to rename target: " + err.Error(),
+		})
+		return
+	}
+
+	for _, t := range h.collector.GetTargets() {
+		if t.Name == req.Name {
+			c.JSON(http.StatusOK, gin.H{
+				"status": "renamed",
+				"name":   t.Name,
+				"id":     t.StorageKey(),
+			})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "renamed", "name": req.Name})
+}
@@ -0,0 +1,30 @@
+package api
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dashboardPath is where the web dashboard is served
+const dashboardPath = "/ui/"
+
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// setupDashboard serves the embedded web dashboard and redirects the root to
+// it. The assets hold no data: the dashboard reads everything from /api/v1
+// with the user's token, so they are served without authentication.
+func setupDashboard(router *gin.Engine) {
+	assets, err := fs.Sub(dashboardFiles, "dashboard")
+	if err != nil {
+		panic(err) // The embedded directory always exists
+	}
+
+	router.StaticFS(dashboardPath, http.FS(assets))
+	router.GET("/", func(c *gin.Context) {
+		c.Redirect(http.StatusFound, dashboardPath)
+	})
+}
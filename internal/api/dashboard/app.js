@@ -0,0 +1,550 @@
+// Pulse web dashboard: target overview, SmokePing-style graphs and live
+// updates over the WebSocket API. No build step; served as is.
+'use strict';
+
+const API = '/api/v1';
+const TOKEN_KEY = 'pulse.token';
+const RANGES = { '1h': 3600e3, '1d': 86400e3, '1w': 7 * 86400e3 };
+const COLUMN_WIDTH = 3; // Graph pixels per time bucket
+const RECONNECT_DELAY = 3000;
+const GRAPH_PAD = { left: 64, right: 12, top: 12, bottom: 28 };
+
+// Median line colors by packet loss ratio, as in the legend
+const LOSS_COLORS = [
+  [0, '#10B981'],
+  [0.05, '#06B6D4'],
+  [0.10, '#7C3AED'],
+  [0.25, '#F59E0B'],
+  [0.50, '#F97316'],
+  [1, '#EF4444'],
+];
+
+const state = {
+  token: localStorage.getItem(TOKEN_KEY) || '',
+  targets: [],      // From /targets
+  stats: {},        // By target name, from stats_update
+  alerts: {},       // Firing alerts by target name
+  anomalies: {},    // Active anomalies by target name
+  selected: null,   // Target name shown in the detail view
+  range: '1h',      // Key of RANGES, or 'custom'
+  from: 0,          // Graph window (ms since epoch)
+  to: 0,
+  points: [],       // {t, v (ms or null), loss (0-1)} of the selected target
+  lastResult: null, // Timestamp of the newest live result, to resume after reconnects
+  ws: null,
+  redraw: false,
+};
+
+const $ = (id) => document.getElementById(id);
+
+// api performs an authenticated GET against the REST API
+async function api(path) {
+  const headers = state.token ? { Authorization: 'Bearer ' + state.token } : {};
+  const resp = await fetch(API + path, { headers });
+  if (resp.status === 401) {
+    showLogin(state.token ? 'The token was rejected.' : '');
+    throw new Error('unauthorized');
+  }
+  const body = await resp.json().catch(() => ({}));
+  if (!resp.ok) {
+    throw new Error(body.message || resp.statusText);
+  }
+  return body;
+}
+
+// --- Login ---
+
+function showLogin(message) {
+  $('login').hidden = false;
+  $('overview').hidden = true;
+  $('detail').hidden = true;
+  $('login-error').textContent = message || '';
+  $('token').focus();
+}
+
+$('login-form').addEventListener('submit', (e) => {
+  e.preventDefault();
+  state.token = $('token').value.trim();
+  localStorage.setItem(TOKEN_KEY, state.token);
+  $('login').hidden = true;
+  $('overview').hidden = false;
+  start();
+});
+
+$('signout').addEventListener('click', () => {
+  localStorage.removeItem(TOKEN_KEY);
+  state.token = '';
+  location.reload();
+});
+
+// --- Overview table ---
+
+async function loadTargets() {
+  state.targets = await api('/targets');
+  for (const t of state.targets) {
+    if (t.stats) {
+      state.stats[t.name] = t.stats;
+    }
+    if (t.anomaly) {
+      state.anomalies[t.name] = t.anomaly;
+    }
+  }
+  renderTable();
+}
+
+function fmtMs(v) {
+  if (v === undefined || v === null || v < 0) {
+    return '-';
+  }
+  return v < 10 ? v.toFixed(2) + ' ms' : v.toFixed(1) + ' ms';
+}
+
+function cell(row, text, className) {
+  const td = document.createElement('td');
+  td.textContent = text;
+  if (className) {
+    td.className = className;
+  }
+  row.appendChild(td);
+  return td;
+}
+
+function renderTable() {
+  const body = document.querySelector('#targets tbody');
+  body.replaceChildren();
+  for (const t of state.targets) {
+    const s = state.stats[t.name] || {};
+    const row = document.createElement('tr');
+    row.classList.toggle('selected', t.name === state.selected);
+    row.addEventListener('click', () => select(t.name));
+
+    cell(row, t.name);
+    cell(row, t.group || '', 'muted');
+    cell(row, t.port ? t.host + ':' + t.port : t.host, 'muted');
+    cell(row, s.sample_count ? fmtMs(s.last_ms) : '-', 'num');
+    cell(row, s.sample_count ? fmtMs(s.median_ms) : '-', 'num');
+    cell(row, s.sample_count ? fmtMs(s.avg_ms) : '-', 'num');
+    cell(row, s.sample_count ? fmtMs(s.p95_ms) : '-', 'num');
+    const loss = s.loss_pct || 0;
+    cell(row, s.sample_count ? loss.toFixed(1) + '%' : '-', 'num' + (loss >= 50 ? ' bad' : loss > 0 ? ' warn' : ''));
+
+    const alertCell = cell(row, '');
+    const alert = state.alerts[t.name];
+    if (alert) {
+      const badge = document.createElement('span');
+      badge.className = 'badge';
+      badge.textContent = 'firing';
+      badge.title = alert.reason || '';
+      alertCell.appendChild(badge);
+    }
+    const anomaly = state.anomalies[t.name];
+    if (anomaly) {
+      const badge = document.createElement('span');
+      badge.className = 'badge anomaly';
+      badge.textContent = anomaly.kind === 'loss' ? 'loss anomaly' : 'latency anomaly';
+      badge.title = 'Since ' + new Date(anomaly.since).toLocaleString() +
+        ', baseline ' + fmtMs(anomaly.baseline.median_ms) + ', ' + anomaly.baseline.loss_pct.toFixed(1) + '% loss';
+      alertCell.appendChild(badge);
+    }
+    body.appendChild(row);
+  }
+}
+
+// --- Detail view ---
+
+function select(name) {
+  state.selected = name;
+  history.replaceState(null, '', '#' + encodeURIComponent(name));
+  $('detail').hidden = false;
+  $('detail-name').textContent = name;
+  renderTable();
+  setRange(state.range === 'custom' ? '1h' : state.range);
+}
+
+function setRange(range) {
+  state.range = range;
+  for (const b of document.querySelectorAll('.ranges button[data-range]')) {
+    b.classList.toggle('active', b.dataset.range === range);
+  }
+  if (range !== 'custom') {
+    state.to = Date.now();
+    state.from = state.to - RANGES[range];
+  }
+  loadHistory();
+}
+
+for (const b of document.querySelectorAll('.ranges button[data-range]')) {
+  b.addEventListener('click', () => setRange(b.dataset.range));
+}
+
+$('custom').addEventListener('submit', (e) => {
+  e.preventDefault();
+  const from = new Date($('from').value).getTime();
+  const to = new Date($('to').value).getTime();
+  if (!(from < to)) {
+    $('error').textContent = 'The custom range must start before it ends.';
+    return;
+  }
+  $('error').textContent = '';
+  state.from = from;
+  state.to = to;
+  setRange('custom');
+});
+
+async function loadHistory() {
+  const name = state.selected;
+  const query = '?from=' + new Date(state.from).toISOString() + '&to=' + new Date(state.to).toISOString();
+  try {
+    const resp = await api('/targets/' + encodeURIComponent(name) + '/history' + query);
+    if (name !== state.selected) {
+      return; // Selection changed while loading
+    }
+    state.points = (resp.data_points || [])
+      .filter((p) => p.value !== null || p.loss !== null)
+      .map((p) => ({ t: Date.parse(p.timestamp), v: p.value, loss: p.loss === null ? 0 : p.loss }));
+    $('error').textContent = '';
+  } catch (err) {
+    state.points = [];
+    $('error').textContent = 'Failed to load history: ' + err.message;
+  }
+  scheduleDraw();
+}
+
+// addLive appends a live probe result to the graph when it shows the recent past
+function addLive(result) {
+  if (result.target !== state.selected || state.range === 'custom') {
+    return;
+  }
+  const t = Date.parse(result.timestamp);
+  state.to = Math.max(Date.now(), t);
+  state.from = state.to - RANGES[state.range];
+  state.points.push({ t, v: result.success ? result.latency_ms : null, loss: (result.loss_pct || 0) / 100 });
+  state.points = state.points.filter((p) => p.t >= state.from);
+  scheduleDraw();
+}
+
+// --- Graph ---
+
+function scheduleDraw() {
+  if (!state.redraw) {
+    state.redraw = true;
+    requestAnimationFrame(() => {
+      state.redraw = false;
+      draw();
+    });
+  }
+}
+
+function lossColor(loss) {
+  for (const [limit, color] of LOSS_COLORS) {
+    if (loss <= limit) {
+      return color;
+    }
+  }
+  return LOSS_COLORS[LOSS_COLORS.length - 1][1];
+}
+
+function quantile(sorted, q) {
+  const pos = (sorted.length - 1) * q;
+  const lo = Math.floor(pos);
+  const hi = Math.ceil(pos);
+  return sorted[lo] + (sorted[hi] - sorted[lo]) * (pos - lo);
+}
+
+// buckets groups the points into graph columns with their latency
+// distribution and loss ratio
+function buckets(count) {
+  const span = state.to - state.from;
+  const cols = Array.from({ length: count }, () => ({ values: [], loss: 0, n: 0 }));
+  for (const p of state.points) {
+    const i = Math.floor(((p.t - state.from) / span) * count);
+    if (i < 0 || i >= count) {
+      continue;
+    }
+    cols[i].n++;
+    cols[i].loss += p.loss;
+    if (p.v !== null) {
+      cols[i].values.push(p.v);
+    }
+  }
+  for (const c of cols) {
+    c.values.sort((a, b) => a - b);
+    c.loss = c.n ? c.loss / c.n : 0;
+  }
+  return cols;
+}
+
+function timeLabel(t, span) {
+  const d = new Date(t);
+  const hm = d.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' });
+  if (span <= RANGES['1d']) {
+    return hm;
+  }
+  return d.toLocaleDateString([], { month: 'short', day: 'numeric' }) + ' ' + hm;
+}
+
+function draw() {
+  const canvas = $('graph');
+  const dpr = window.devicePixelRatio || 1;
+  const width = canvas.clientWidth;
+  const height = canvas.clientHeight;
+  canvas.width = width * dpr;
+  canvas.height = height * dpr;
+  const ctx = canvas.getContext('2d');
+  ctx.scale(dpr, dpr);
+  ctx.clearRect(0, 0, width, height);
+
+  const pad = GRAPH_PAD;
+  const plotW = width - pad.left - pad.right;
+  const plotH = height - pad.top - pad.bottom;
+  const count = Math.max(1, Math.floor(plotW / COLUMN_WIDTH));
+  const cols = buckets(count);
+  state.columns = cols;
+
+  // Scale to the upper spread of the data so single spikes do not flatten it
+  let max = 0;
+  for (const c of cols) {
+    if (c.values.length) {
+      max = Math.max(max, quantile(c.values, 0.9));
+    }
+  }
+  max = Math.max(1, max * 1.15);
+  const y = (v) => pad.top + plotH - (Math.min(v, max) / max) * plotH;
+
+  // Grid and axes
+  ctx.font = '11px system-ui, sans-serif';
+  ctx.fillStyle = '#6B7280';
+  ctx.strokeStyle = 'rgba(107, 114, 128, 0.35)';
+  ctx.lineWidth = 1;
+  ctx.textAlign = 'right';
+  ctx.textBaseline = 'middle';
+  for (let i = 0; i <= 4; i++) {
+    const v = (max * i) / 4;
+    const yy = Math.round(y(v)) + 0.5;
+    ctx.beginPath();
+    ctx.moveTo(pad.left, yy);
+    ctx.lineTo(pad.left + plotW, yy);
+    ctx.stroke();
+    ctx.fillText(fmtMs(v), pad.left - 6, yy);
+  }
+  ctx.textAlign = 'center';
+  ctx.textBaseline = 'top';
+  const span = state.to - state.from;
+  for (let i = 0; i <= 5; i++) {
+    const x = pad.left + (plotW * i) / 5;
+    ctx.fillText(timeLabel(state.from + (span * i) / 5, span), x, pad.top + plotH + 8);
+  }
+
+  if (!state.points.length) {
+    ctx.textBaseline = 'middle';
+    ctx.fillText('No data in this range', pad.left + plotW / 2, pad.top + plotH / 2);
+    return;
+  }
+
+  // Smoke: the latency spread of each column, darker towards the median
+  const bands = [[0, 1, 0.12], [0.1, 0.9, 0.18], [0.25, 0.75, 0.28]];
+  cols.forEach((c, i) => {
+    if (!c.values.length) {
+      return;
+    }
+    const x = pad.left + i * COLUMN_WIDTH;
+    for (const [lo, hi, alpha] of bands) {
+      const top = y(quantile(c.values, hi));
+      const bottom = y(quantile(c.values, lo));
+      ctx.fillStyle = `rgba(249, 250, 251, ${alpha})`;
+      ctx.fillRect(x, top, COLUMN_WIDTH, Math.max(1, bottom - top));
+    }
+  });
+
+  // Median colored by loss; columns with only lost probes get a marker
+  cols.forEach((c, i) => {
+    if (!c.n) {
+      return;
+    }
+    const x = pad.left + i * COLUMN_WIDTH;
+    ctx.fillStyle = lossColor(c.loss);
+    if (c.values.length) {
+      ctx.fillRect(x, y(quantile(c.values, 0.5)) - 1, COLUMN_WIDTH, 2);
+    } else {
+      ctx.globalAlpha = 0.35;
+      ctx.fillRect(x, pad.top, COLUMN_WIDTH, plotH);
+      ctx.globalAlpha = 1;
+    }
+  });
+
+  renderSummary();
+}
+
+function renderSummary() {
+  const values = state.points.filter((p) => p.v !== null).map((p) => p.v).sort((a, b) => a - b);
+  const loss = state.points.reduce((sum, p) => sum + p.loss, 0) / state.points.length;
+  const parts = values.length
+    ? [
+      ['Median', fmtMs(quantile(values, 0.5))],
+      ['Avg', fmtMs(values.reduce((a, b) => a + b, 0) / values.length)],
+      ['Min', fmtMs(values[0])],
+      ['Max', fmtMs(values[values.length - 1])],
+      ['P95', fmtMs(quantile(values, 0.95))],
+    ]
+    : [];
+  parts.push(['Loss', (loss * 100).toFixed(2) + '%'], ['Samples', String(state.points.length)]);
+
+  const summary = $('summary');
+  summary.replaceChildren();
+  for (const [label, value] of parts) {
+    const span = document.createElement('span');
+    span.textContent = label + ': ' + value;
+    summary.appendChild(span);
+  }
+}
+
+$('graph').addEventListener('mousemove', (e) => {
+  const tip = $('tooltip');
+  const cols = state.columns || [];
+  const i = Math.floor((e.offsetX - GRAPH_PAD.left) / COLUMN_WIDTH);
+  const c = cols[i];
+  if (!c || !c.n) {
+    tip.hidden = true;
+    return;
+  }
+  const t = state.from + ((i + 0.5) / cols.length) * (state.to - state.from);
+  const lines = [new Date(t).toLocaleString()];
+  if (c.values.length) {
+    lines.push('median ' + fmtMs(quantile(c.values, 0.5)));
+    lines.push('min ' + fmtMs(c.values[0]) + '  max ' + fmtMs(c.values[c.values.length - 1]));
+  }
+  lines.push('loss ' + (c.loss * 100).toFixed(1) + '%');
+  tip.textContent = lines.join('\n');
+  tip.hidden = false;
+  tip.style.left = Math.min(e.offsetX + 12, e.target.clientWidth - tip.offsetWidth) + 'px';
+  tip.style.top = e.offsetY + 12 + 'px';
+});
+
+$('graph').addEventListener('mouseleave', () => {
+  $('tooltip').hidden = true;
+});
+
+window.addEventListener('resize', scheduleDraw);
+
+// --- Live updates ---
+
+function setConn(text, className) {
+  const el = $('conn');
+  el.textContent = text;
+  el.className = 'conn ' + (className || '');
+}
+
+function connect() {
+  const proto = location.protocol === 'https:' ? 'wss:' : 'ws:';
+  let url = proto + '//' + location.host + API + '/ws';
+  if (state.token) {
+    url += '?access_token=' + encodeURIComponent(state.token);
+  }
+
+  const ws = new WebSocket(url);
+  state.ws = ws;
+
+  ws.onopen = () => {
+    setConn('live', 'live');
+    const msg = {
+      type: 'subscribe',
+      targets: ['all'],
+      types: ['probe_result', 'stats_update', 'alert', 'anomaly', 'target_event'],
+    };
+    if (state.lastResult) {
+      msg.since = state.lastResult; // Fill the gap left by the disconnect
+    }
+    ws.send(JSON.stringify(msg));
+  };
+
+  ws.onmessage = (e) => {
+    const msg = JSON.parse(e.data);
+    switch (msg.type) {
+      case 'probe_result':
+        onResult(msg.data);
+        break;
+      case 'backfill':
+        msg.data.results.forEach(onResult);
+        break;
+      case 'stats_update':
+        Object.assign(state.stats, msg.data.stats);
+        renderTable();
+        break;
+      case 'alert':
+        if (msg.data.alert && msg.data.alert.firing) {
+          state.alerts[msg.data.target] = msg.data.alert;
+        } else {
+          delete state.alerts[msg.data.target];
+        }
+        renderTable();
+        break;
+      case 'anomaly':
+        if (msg.data.anomaly && msg.data.anomaly.active) {
+          state.anomalies[msg.data.target] = msg.data.anomaly;
+        } else {
+          delete state.anomalies[msg.data.target];
+        }
+        renderTable();
+        break;
+      case 'target_event':
+        onRename(msg.data.old_name, msg.data.target);
+        break;
+    }
+  };
+
+  ws.onclose = () => {
+    setConn('reconnecting', 'offline');
+    setTimeout(connect, RECONNECT_DELAY);
+  };
+}
+
+function onResult(result) {
+  if (!state.lastResult || Date.parse(result.timestamp) >= Date.parse(state.lastResult)) {
+    state.lastResult = result.timestamp;
+  }
+  addLive(result);
+}
+
+function onRename(oldName, newName) {
+  for (const t of state.targets) {
+    if (t.name === oldName) {
+      t.name = newName;
+    }
+  }
+  for (const m of [state.stats, state.alerts, state.anomalies]) {
+    if (oldName in m) {
+      m[newName] = m[oldName];
+      delete m[oldName];
+    }
+  }
+  if (state.selected === oldName) {
+    state.selected = newName;
+    $('detail-name').textContent = newName;
+    history.replaceState(null, '', '#' + encodeURIComponent(newName));
+  }
+  renderTable();
+}
+
+// --- Startup ---
+
+async function start() {
+  $('signout').hidden = !state.token;
+  try {
+    await loadTargets();
+  } catch (err) {
+    if (err.message !== 'unauthorized') {
+      $('error').textContent = 'Failed to load targets: ' + err.message;
+    }
+    return;
+  }
+
+  const wanted = decodeURIComponent(location.hash.slice(1));
+  if (wanted && state.targets.some((t) => t.name === wanted)) {
+    select(wanted);
+  }
+  if (!state.ws) {
+    connect();
+  }
+}
+
+start();
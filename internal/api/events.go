@@ -0,0 +1,105 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Limits on the number of incidents returned
+const (
+	defaultEventLimit = 100
+	maxEventLimit     = 1000
+)
+
+// EventQuery represents query parameters for the incident log
+type EventQuery struct {
+	Target  string `form:"target"`  // Only this target
+	Kind    string `form:"kind"`    // down, degraded, unreachable, path_changed, config_changed, daemon_restart or maintenance
+	Range   string `form:"range"`   // Only incidents overlapping e.g. the last 1d
+	From    string `form:"from"`    // RFC3339, overrides range
+	To      string `form:"to"`      // RFC3339, overrides range
+	Ongoing bool   `form:"ongoing"` // Only ongoing incidents
+	Limit   int    `form:"limit"`   // Default 100, at most 1000
+}
+
+// GetEvents returns recorded incidents, newest first: outages, degradations,
+// path and config changes and daemon restarts, with start, end and duration
+func (h *Handler) GetEvents(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	var query EventQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	q := storage.IncidentQuery{
+		Target:  query.Target,
+		Kind:    storage.IncidentKind(query.Kind),
+		Ongoing: query.Ongoing,
+		Limit:   query.Limit,
+	}
+	switch q.Kind {
+	case "", storage.IncidentDown, storage.IncidentDegraded, storage.IncidentUnreachable, storage.IncidentPathChanged,
+		storage.IncidentConfigChanged, storage.IncidentDaemonRestart, storage.IncidentMaintenance:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "kind must be down, degraded, unreachable, path_changed, config_changed, daemon_restart or maintenance",
+		})
+		return
+	}
+	if q.Limit < 0 || q.Limit > maxEventLimit {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "limit must be between 1 and 1000",
+		})
+		return
+	}
+	if q.Limit == 0 {
+		q.Limit = defaultEventLimit
+	}
+
+	if query.Range != "" {
+		span, err := parseRange(query.Range)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		q.To = time.Now()
+		q.From = q.To.Add(-span)
+	}
+	if query.From != "" || query.To != "" {
+		var errFrom, errTo error
+		q.From, errFrom = time.Parse(time.RFC3339, query.From)
+		q.To, errTo = time.Parse(time.RFC3339, query.To)
+		if errFrom != nil || errTo != nil || !q.To.After(q.From) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "from and to must both be RFC3339 timestamps with from < to",
+			})
+			return
+		}
+	}
+
+	events := h.collector.Incidents(q)
+	c.JSON(http.StatusOK, gin.H{
+		"events": events,
+		"count":  len(events),
+	})
+}
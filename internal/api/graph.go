@@ -0,0 +1,178 @@
+package api
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/graph"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// GraphQuery represents query parameters for graph images
+type GraphQuery struct {
+	Range  string `form:"range"`  // e.g. 1h, 1d, 1w, 90m, 30d (default: 1h)
+	From   string `form:"from"`   // RFC3339, overrides range
+	To     string `form:"to"`     // RFC3339, overrides range
+	Width  int    `form:"width"`  // Pixels (default: 800)
+	Height int    `form:"height"` // Pixels (default: 300)
+	Title  string `form:"title"`  // Default: target name and range
+}
+
+// GetTargetGraph renders a target's latency and loss graph as a PNG or SVG
+// image, depending on the requested file name (graph.png or graph.svg)
+func (h *Handler) GetTargetGraph(c *gin.Context) {
+	name := c.Param("name")
+
+	found := false
+	for _, t := range h.targets() {
+		if t.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Target not found: " + name,
+		})
+		return
+	}
+
+	var query GraphQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+	if query.Width < 0 || query.Width > graph.MaxWidth || query.Height < 0 || query.Height > graph.MaxHeight ||
+		(query.Width > 0 && query.Width < graph.MinWidth) || (query.Height > 0 && query.Height < graph.MinHeight) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": fmt.Sprintf("width must be %d-%d and height %d-%d", graph.MinWidth, graph.MaxWidth, graph.MinHeight, graph.MaxHeight),
+		})
+		return
+	}
+
+	to := time.Now()
+	rangeText := query.Range
+	if rangeText == "" {
+		rangeText = "1h"
+	}
+	span, err := parseRange(rangeText)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	from := to.Add(-span)
+	title := name + " - last " + rangeText
+
+	if query.From != "" || query.To != "" {
+		var errFrom, errTo error
+		from, errFrom = time.Parse(time.RFC3339, query.From)
+		to, errTo = time.Parse(time.RFC3339, query.To)
+		if errFrom != nil || errTo != nil || !to.After(from) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "from and to must both be RFC3339 timestamps with from < to",
+			})
+			return
+		}
+		if to.Sub(from) > maxGraphRange {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": fmt.Sprintf("from and to must be at most %v apart", maxGraphRange),
+			})
+			return
+		}
+		title = name + " - " + from.Format("2006-01-02 15:04") + " to " + to.Format("2006-01-02 15:04")
+	}
+	if query.Title != "" {
+		title = query.Title
+	}
+
+	format := graph.FormatPNG
+	contentType := "image/png"
+	if strings.HasSuffix(c.FullPath(), ".svg") {
+		format = graph.FormatSVG
+		contentType = "image/svg+xml"
+	}
+
+	var points []storage.DataPoint
+	if h.collector != nil {
+		p, err := h.collector.FetchHistory(name, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to fetch history: " + err.Error(),
+			})
+			return
+		}
+		points = p
+	}
+
+	var buf bytes.Buffer
+	err = graph.Render(&buf, format, points, graph.Options{
+		Title:  title,
+		From:   from,
+		To:     to,
+		Width:  query.Width,
+		Height: query.Height,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to render graph: " + err.Error(),
+		})
+		return
+	}
+
+	// Let chat and mail clients cache the image until the next probe
+	c.Header("Cache-Control", "private, max-age="+strconv.Itoa(int(h.config.Global.Interval.Seconds())))
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// maxGraphRange is the longest range a graph can cover
+const maxGraphRange = 10 * 365 * 24 * time.Hour
+
+// parseRange parses a graph range: a Go duration, or a number of days (d) or
+// weeks (w)
+func parseRange(s string) (time.Duration, error) {
+	unit := time.Duration(0)
+	switch {
+	case strings.HasSuffix(s, "d"):
+		unit = 24 * time.Hour
+	case strings.HasSuffix(s, "w"):
+		unit = 7 * 24 * time.Hour
+	}
+
+	var d time.Duration
+	if unit > 0 {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err == nil && n > int(maxGraphRange/unit) {
+			return 0, fmt.Errorf("invalid range %q: at most %d%c", s, maxGraphRange/unit, s[len(s)-1])
+		}
+		if err == nil {
+			d = time.Duration(n) * unit
+		}
+	} else {
+		d, _ = time.ParseDuration(s)
+	}
+
+	if d <= 0 {
+		return 0, fmt.Errorf("invalid range %q: use e.g. 1h, 1d, 1w or 30m", s)
+	}
+	if d > maxGraphRange {
+		return 0, fmt.Errorf("invalid range %q: at most %v", s, maxGraphRange)
+	}
+	return d, nil
+}
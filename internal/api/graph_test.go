@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/config"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{input: "30m", want: 30 * time.Minute},
+		{input: "1d", want: 24 * time.Hour},
+		{input: "2w", want: 14 * 24 * time.Hour},
+		{input: "3650d", want: maxGraphRange},
+		{input: "3651d", wantErr: true},
+		{input: "9223372036854775807d", wantErr: true},
+		{input: "1000000w", wantErr: true},
+		{input: "100000h", wantErr: true},
+		{input: "-1d", wantErr: true},
+		{input: "0h", wantErr: true},
+		{input: "xd", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseRange(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseRange(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseRange(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGraphFromTo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewHandler(&config.Config{Targets: []config.Target{{Name: "google", Host: "8.8.8.8", Probe: "icmp"}}})
+	r := gin.New()
+	r.GET("/api/targets/:name/graph.svg", h.GetTargetGraph)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"range", "range=1d", http.StatusOK},
+		{"from to", "from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z", http.StatusOK},
+		{"from after to", "from=2024-01-02T00:00:00Z&to=2024-01-01T00:00:00Z", http.StatusBadRequest},
+		{"from to beyond the maximum", "from=0001-01-01T00:00:00Z&to=9999-12-31T00:00:00Z", http.StatusBadRequest},
+		{"range beyond the maximum", "range=9999w", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/targets/google/graph.svg?"+tt.query, nil))
+			if w.Code != tt.want {
+				t.Errorf("GET graph.svg?%s = %d, want %d: %s", tt.query, w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
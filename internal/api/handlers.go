@@ -31,6 +31,14 @@ func (h *Handler) SetCollector(c *collector.Collector) {
 	h.collector = c
 }
 
+// targets returns the current targets, including runtime renames
+func (h *Handler) targets() []config.Target {
+	if h.collector != nil {
+		return h.collector.GetTargets()
+	}
+	return h.config.Targets
+}
+
 // StatusResponse represents the response for the status endpoint
 type StatusResponse struct {
 	Status      string  `json:"status"`
@@ -38,35 +46,97 @@ type StatusResponse struct {
 	UptimeSecs  float64 `json:"uptime_secs"`
 	TargetCount int     `json:"target_count"`
 	Version     string  `json:"version"`
+
+	Storage   *StorageStatus           `json:"storage,omitempty"`   // Data directory totals
+	Migration *storage.MigrationStatus `json:"migration,omitempty"` // Storage schema migration progress
+
+	ActiveAlerts    int                     `json:"active_alerts"`    // Targets whose alert is firing
+	ActiveAnomalies int                     `json:"active_anomalies"` // Targets deviating from their baseline
+	InMaintenance   int                     `json:"in_maintenance"`   // Targets whose alerts are suppressed
+	Unreachable     int                     `json:"unreachable"`      // Targets failing because a parent is down
+	Agents          []collector.AgentStatus `json:"agents,omitempty"` // Remote agents (master mode)
+}
+
+// StorageStatus summarizes the data directory in the status response
+type StorageStatus struct {
+	TotalBytes  int64 `json:"total_bytes"`
+	FileCount   int   `json:"file_count"`
+	OrphanCount int   `json:"orphan_count"`
 }
 
 // GetStatus returns the current system status
 func (h *Handler) GetStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.status())
+}
+
+// status builds the status response, also pushed to WebSocket clients
+func (h *Handler) status() StatusResponse {
 	uptime := time.Since(h.startTime)
 
 	response := StatusResponse{
 		Status:      "ok",
 		Uptime:      uptime.Round(time.Second).String(),
 		UptimeSecs:  uptime.Seconds(),
-		TargetCount: len(h.config.Targets),
+		TargetCount: len(h.targets()),
 		Version:     "0.1.0",
 	}
+	if h.collector != nil {
+		response.Migration = h.collector.MigrationStatus()
+		if usage, err := h.collector.StorageUsage(); err == nil {
+			response.Storage = &StorageStatus{
+				TotalBytes: usage.TotalBytes,
+				FileCount:  usage.FileCount,
+			}
+			for _, f := range usage.Files {
+				if f.Orphan {
+					response.Storage.OrphanCount++
+				}
+			}
+		}
+		response.ActiveAlerts = len(h.collector.Alerts())
+		response.ActiveAnomalies = len(h.collector.Anomalies())
+		response.InMaintenance = len(h.collector.MaintenanceActive())
+		response.Unreachable = len(h.collector.Unreachable())
+		response.Agents = h.collector.AgentStatuses()
+	}
 
-	c.JSON(http.StatusOK, response)
+	return response
 }
 
 // TargetResponse represents a monitoring target in API responses
 type TargetResponse struct {
-	Name      string         `json:"name"`
-	Host      string         `json:"host"`
-	Port      int            `json:"port,omitempty"`
-	ProbeType string         `json:"probe_type"`
-	Stats     *storage.Stats `json:"stats,omitempty"`
+	ID        string             `json:"id"`
+	Name      string             `json:"name"`
+	Host      string             `json:"host"`
+	Port      int                `json:"port,omitempty"`
+	ProbeType string             `json:"probe_type"`
+	Group     string             `json:"group,omitempty"`
+	Tags      []string           `json:"tags,omitempty"`
+	DependsOn string             `json:"depends_on,omitempty"`
+	Members   []string           `json:"members,omitempty"` // Composite targets
+	Mode      string             `json:"mode,omitempty"`
+	Quorum    int                `json:"quorum,omitempty"`
+	Agent     string             `json:"agent,omitempty"` // Set on series reported by an agent
+	Stats     *storage.Stats     `json:"stats,omitempty"`
+	Anomaly   *collector.Anomaly `json:"anomaly,omitempty"` // Active deviation from the baseline
+
+	Maintenance *collector.Maintenance `json:"maintenance,omitempty"` // Alerts suppressed until its end
+	Dependency  *collector.Dependency  `json:"dependency,omitempty"`  // State of the target it depends on
+}
+
+// compositeMode returns the mode of a composite target, or "" for probed
+// targets
+func compositeMode(t config.Target) string {
+	if !t.IsComposite() {
+		return ""
+	}
+	return t.CompositeMode()
 }
 
 // GetTargets returns the list of all monitoring targets
 func (h *Handler) GetTargets(c *gin.Context) {
-	targets := make([]TargetResponse, len(h.config.Targets))
+	configured := h.targets()
+	targets := make([]TargetResponse, len(configured))
 
 	// Get stats if collector is available
 	var allStats map[string]*storage.Stats
@@ -74,16 +144,29 @@ func (h *Handler) GetTargets(c *gin.Context) {
 		allStats = h.collector.GetAllStats()
 	}
 
-	for i, t := range h.config.Targets {
+	for i, t := range configured {
 		targets[i] = TargetResponse{
+			ID:        t.StorageKey(),
 			Name:      t.Name,
 			Host:      t.Host,
 			Port:      t.Port,
 			ProbeType: t.Probe,
+			Group:     t.Group,
+			Tags:      t.Tags,
+			DependsOn: t.DependsOn,
+			Members:   t.Members,
+			Mode:      compositeMode(t),
+			Quorum:    t.Quorum,
+			Agent:     t.Agent,
 		}
 		if allStats != nil {
 			targets[i].Stats = allStats[t.Name]
 		}
+		if h.collector != nil {
+			targets[i].Anomaly = h.collector.Anomaly(t.Name)
+			targets[i].Maintenance = h.collector.Maintenance(t.Name)
+			targets[i].Dependency = h.collector.Dependency(t.Name)
+		}
 	}
 
 	c.JSON(http.StatusOK, targets)
@@ -93,16 +176,27 @@ func (h *Handler) GetTargets(c *gin.Context) {
 func (h *Handler) GetTarget(c *gin.Context) {
 	name := c.Param("name")
 
-	for _, t := range h.config.Targets {
+	for _, t := range h.targets() {
 		if t.Name == name {
 			response := TargetResponse{
+				ID:        t.StorageKey(),
 				Name:      t.Name,
 				Host:      t.Host,
 				Port:      t.Port,
 				ProbeType: t.Probe,
+				Group:     t.Group,
+				Tags:      t.Tags,
+				DependsOn: t.DependsOn,
+				Members:   t.Members,
+				Mode:      compositeMode(t),
+				Quorum:    t.Quorum,
+				Agent:     t.Agent,
 			}
 			if h.collector != nil {
 				response.Stats = h.collector.GetStats(name)
+				response.Anomaly = h.collector.Anomaly(name)
+				response.Maintenance = h.collector.Maintenance(name)
+				response.Dependency = h.collector.Dependency(name)
 			}
 			c.JSON(http.StatusOK, response)
 			return
@@ -121,7 +215,7 @@ func (h *Handler) GetTargetStats(c *gin.Context) {
 
 	// Verify target exists
 	found := false
-	for _, t := range h.config.Targets {
+	for _, t := range h.targets() {
 		if t.Name == name {
 			found = true
 			break
@@ -174,7 +268,7 @@ func (h *Handler) GetTargetHistory(c *gin.Context) {
 
 	// Verify target exists
 	found := false
-	for _, t := range h.config.Targets {
+	for _, t := range h.targets() {
 		if t.Name == name {
 			found = true
 			break
@@ -259,8 +353,10 @@ func (h *Handler) GetConfig(c *gin.Context) {
 	// Return a sanitized version of the config
 	response := gin.H{
 		"server": gin.H{
-			"address":    h.config.Server.Address,
-			"enable_tui": h.config.Server.EnableTUI,
+			"address":      h.config.Server.Address,
+			"enable_tui":   h.config.Server.EnableTUI,
+			"dashboard":    h.config.Server.Dashboard,
+			"auth_enabled": len(h.config.Server.Auth.Tokens) > 0 || h.config.Server.Auth.TokenFile != "",
 		},
 		"global": gin.H{
 			"interval": h.config.Global.Interval.String(),
@@ -272,7 +368,14 @@ func (h *Handler) GetConfig(c *gin.Context) {
 			"aggregation": h.config.Storage.Aggregation,
 			"xff":         h.config.Storage.XFF,
 		},
-		"target_count": len(h.config.Targets),
+		"reports": gin.H{
+			"schedule":             h.config.Reports.Schedule,
+			"formats":              h.config.Reports.Formats,
+			"latency_threshold_ms": h.config.Reports.LatencyThreshold,
+			"loss_threshold_pct":   h.config.Reports.LossThreshold,
+			"slo":                  h.config.Reports.SLO,
+		},
+		"target_count": len(h.targets()),
 	}
 
 	c.JSON(http.StatusOK, response)
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/auth"
+	"github.com/wellsgz/pulse/internal/collector"
+)
+
+// SilenceRequest creates an ad-hoc silence. Exactly one of target, group and
+// tag is required, and either end or duration.
+type SilenceRequest struct {
+	Target   string `json:"target"`
+	Group    string `json:"group"`
+	Tag      string `json:"tag"`
+	Start    string `json:"start"`    // RFC3339, default now
+	End      string `json:"end"`      // RFC3339
+	Duration string `json:"duration"` // From start, e.g. 2h or 1d
+	Comment  string `json:"comment"`
+}
+
+// GetMaintenance returns the targets currently in maintenance, the
+// configured maintenance windows and the ad-hoc silences
+func (h *Handler) GetMaintenance(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"active":   h.collector.MaintenanceActive(),
+		"windows":  h.collector.MaintenanceWindows(),
+		"silences": h.collector.Silences(),
+	})
+}
+
+// GetSilences returns the ad-hoc silences that have not expired
+func (h *Handler) GetSilences(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	silences := h.collector.Silences()
+	c.JSON(http.StatusOK, gin.H{
+		"silences": silences,
+		"count":    len(silences),
+	})
+}
+
+// CreateSilence suppresses the alerts of a target, group or tag for a while
+func (h *Handler) CreateSilence(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	var req SilenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid silence: " + err.Error(),
+		})
+		return
+	}
+
+	silence, err := parseSilence(req, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if identity, ok := c.Get(identityKey); ok {
+		silence.CreatedBy = identity.(auth.Identity).Name
+	}
+
+	silence, err = h.collector.AddSilence(silence)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusCreated, silence)
+}
+
+// DeleteSilence removes an ad-hoc silence, ending the maintenance it caused
+func (h *Handler) DeleteSilence(c *gin.Context) {
+	id := c.Param("id")
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	if err := h.collector.RemoveSilence(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "id": id})
+}
+
+// parseSilence converts a silence request, resolving a duration to an end
+func parseSilence(req SilenceRequest, now time.Time) (collector.Silence, error) {
+	s := collector.Silence{
+		Target:  req.Target,
+		Group:   req.Group,
+		Tag:     req.Tag,
+		Comment: req.Comment,
+	}
+
+	start := now
+	if req.Start != "" {
+		t, err := time.Parse(time.RFC3339, req.Start)
+		if err != nil {
+			return s, errors.New("start must be an RFC3339 timestamp")
+		}
+		s.Start, start = t, t
+	}
+
+	switch {
+	case req.End != "" && req.Duration != "":
+		return s, errors.New("end and duration are mutually exclusive")
+	case req.End != "":
+		t, err := time.Parse(time.RFC3339, req.End)
+		if err != nil {
+			return s, errors.New("end must be an RFC3339 timestamp")
+		}
+		s.End = t
+	case req.Duration != "":
+		d, err := parseRange(req.Duration)
+		if err != nil {
+			return s, errors.New("invalid duration " + req.Duration + ": use e.g. 30m, 2h or 1d")
+		}
+		s.End = start.Add(d)
+	default:
+		return s, errors.New("end or duration is required")
+	}
+	return s, nil
+}
@@ -2,18 +2,29 @@ package api
 
 import (
 	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/wellsgz/pulse/internal/auth"
 )
 
-// CORS returns a middleware that handles Cross-Origin Resource Sharing
-func CORS() gin.HandlerFunc {
+// CORS returns a middleware that handles Cross-Origin Resource Sharing.
+// Only origins in allowed ("*" = any) receive CORS headers.
+func CORS(allowed []string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization")
-		c.Header("Access-Control-Max-Age", "86400")
+		origin := c.GetHeader("Origin")
+		if origin != "" && auth.OriginAllowed(origin, "", allowed) {
+			c.Header("Access-Control-Allow-Origin", origin)
+			c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
+			c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-API-Key")
+			c.Header("Access-Control-Max-Age", "86400")
+		}
+		c.Header("Vary", "Origin")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -24,6 +35,115 @@ func CORS() gin.HandlerFunc {
 	}
 }
 
+// Auth returns a middleware that requires a valid API token. GET and HEAD
+// requests need the read role; all other methods need the admin role.
+// Tokens are accepted as "Authorization: Bearer <token>" or "X-API-Key", and
+// as the access_token query parameter for WebSocket upgrades, event streams
+// and graph images (browsers cannot set headers there, and embedded images
+// need a plain URL).
+func Auth(a *auth.Authenticator) gin.HandlerFunc {
+	return requireRole(a, func(c *gin.Context) auth.Role {
+		if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+			return auth.RoleRead
+		}
+		return auth.RoleAdmin
+	})
+}
+
+// ReadOnly returns a middleware that rejects every request but GET and HEAD.
+// It guards the API when no tokens are configured, so that targets, history
+// and silences cannot be changed without an admin token.
+func ReadOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "The API is read-only until an admin token is configured",
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireJSON returns a middleware that rejects request bodies, other than on
+// GET and HEAD, not sent as application/json. Browsers send forms and
+// text/plain cross-site without a CORS preflight; a JSON body always needs one.
+func RequireJSON() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		method := c.Request.Method
+		if method != http.MethodGet && method != http.MethodHead && c.Request.ContentLength != 0 {
+			mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+			if err != nil || mediaType != "application/json" {
+				c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{
+					"error":   "Unsupported Media Type",
+					"message": "Request bodies must be sent as Content-Type: application/json",
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// AgentAuth returns a middleware that requires a token with the agent role
+// (or admin). Without configured tokens every request is rejected.
+func AgentAuth(a *auth.Authenticator) gin.HandlerFunc {
+	return requireRole(a, func(*gin.Context) auth.Role { return auth.RoleAgent })
+}
+
+// requireRole returns a middleware that authenticates the request token and
+// checks it against the role returned by required
+func requireRole(a *auth.Authenticator, required func(c *gin.Context) auth.Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := a.Authenticate(requestToken(c))
+		if !ok {
+			c.Header("WWW-Authenticate", `Bearer realm="pulse"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error":   "Unauthorized",
+				"message": "A valid API token is required",
+			})
+			return
+		}
+
+		required := required(c)
+		if !identity.Role.Allows(required) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": "This endpoint requires the " + string(required) + " role",
+			})
+			return
+		}
+
+		c.Set(identityKey, identity)
+		c.Next()
+	}
+}
+
+// identityKey is the gin context key for the authenticated auth.Identity
+const identityKey = "identity"
+
+// requestToken extracts the API token from a request
+func requestToken(c *gin.Context) string {
+	if h := c.GetHeader("Authorization"); h != "" {
+		if token, ok := strings.CutPrefix(h, "Bearer "); ok {
+			return strings.TrimSpace(token)
+		}
+	}
+	if key := c.GetHeader("X-API-Key"); key != "" {
+		return key
+	}
+	if websocket.IsWebSocketUpgrade(c.Request) || strings.Contains(c.GetHeader("Accept"), "text/event-stream") || isGraphImage(c.Request.URL.Path) {
+		return c.Query("access_token")
+	}
+	return ""
+}
+
+// isGraphImage reports whether a request path is a graph image
+func isGraphImage(path string) bool {
+	return strings.HasSuffix(path, "/graph.png") || strings.HasSuffix(path, "/graph.svg")
+}
+
 // RequestLogger returns a middleware that logs HTTP requests
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -39,7 +159,7 @@ func RequestLogger() gin.HandlerFunc {
 		method := c.Request.Method
 
 		if query != "" {
-			path = path + "?" + query
+			path = path + "?" + redactQuery(query)
 		}
 
 		log.Printf("[API] %3d | %13v | %15s | %-7s %s",
@@ -52,6 +172,20 @@ func RequestLogger() gin.HandlerFunc {
 	}
 }
 
+// redactQuery masks the value of access_token in a raw query string, so
+// tokens passed in WebSocket, event stream and graph image URLs stay out of
+// the log. The other parameters are kept as sent.
+func redactQuery(raw string) string {
+	params := strings.Split(raw, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && name == "access_token" {
+			params[i] = key + "=REDACTED"
+		}
+	}
+	return strings.Join(params, "&")
+}
+
 // ErrorHandler returns a middleware that handles panics and returns proper error responses
 func ErrorHandler() gin.HandlerFunc {
 	return func(c *gin.Context) {
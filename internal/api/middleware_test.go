@@ -0,0 +1,148 @@
+package api
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/auth"
+	"github.com/wellsgz/pulse/internal/config"
+)
+
+func TestAuthQueryToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a, err := auth.New(config.AuthConfig{Tokens: []config.TokenConfig{
+		{Name: "dashboard", Token: "read-token-0123456789", Role: "read"},
+	}})
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+
+	var logged bytes.Buffer
+	log.SetOutput(&logged)
+	defer log.SetOutput(os.Stderr)
+
+	r := gin.New()
+	r.Use(RequestLogger(), Auth(a))
+	r.GET("/api/targets/:name/graph.png", func(c *gin.Context) { c.Status(http.StatusOK) })
+	r.GET("/api/targets", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+	}{
+		{"graph image with query token", "/api/targets/web/graph.png?range=1d&access_token=read-token-0123456789", http.StatusOK},
+		{"graph image with escaped key", "/api/targets/web/graph.png?access%5Ftoken=read-token-0123456789&range=1d", http.StatusOK},
+		{"wrong query token", "/api/targets/web/graph.png?access_token=wrong-token-0123456789", http.StatusUnauthorized},
+		{"query token on a plain request", "/api/targets?access_token=read-token-0123456789", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logged.Reset()
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			line := logged.String()
+			if !strings.Contains(line, "[API]") {
+				t.Fatalf("request was not logged: %q", line)
+			}
+			if strings.Contains(line, "token-0123456789") {
+				t.Errorf("logged line contains the token: %q", line)
+			}
+			if !strings.Contains(line, "=REDACTED") {
+				t.Errorf("logged line = %q, want the token redacted", line)
+			}
+		})
+	}
+}
+
+func TestRedactQuery(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{"range=1d", "range=1d"},
+		{"access_token=secret", "access_token=REDACTED"},
+		{"range=1d&access_token=secret&width=800", "range=1d&access_token=REDACTED&width=800"},
+		{"access_token=a&access_token=b", "access_token=REDACTED&access_token=REDACTED"},
+		{"my_access_token=kept", "my_access_token=kept"},
+	}
+	for _, tt := range tests {
+		if got := redactQuery(tt.raw); got != tt.want {
+			t.Errorf("redactQuery(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestRoutesWithoutTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	a, err := auth.New(config.AuthConfig{})
+	if err != nil {
+		t.Fatalf("auth.New() error = %v", err)
+	}
+	r := gin.New()
+	SetupRoutes(r, NewHandler(&config.Config{}), nil, a)
+
+	tests := []struct {
+		method     string
+		url        string
+		wantStatus int
+	}{
+		{http.MethodGet, "/api/v1/config", http.StatusOK},
+		{http.MethodPost, "/api/v1/targets/web/rename", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/targets/web/import", http.StatusForbidden},
+		{http.MethodDelete, "/api/v1/storage/orphans/old", http.StatusForbidden},
+		{http.MethodPost, "/api/v1/silences", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.url, strings.NewReader(`{}`)))
+		if w.Code != tt.wantStatus {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.url, w.Code, tt.wantStatus)
+		}
+	}
+}
+
+func TestRequireJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequireJSON())
+	r.POST("/silences", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		wantStatus  int
+	}{
+		{"json", `{"target":"web"}`, "application/json", http.StatusOK},
+		{"json with charset", `{"target":"web"}`, "application/json; charset=utf-8", http.StatusOK},
+		{"no body", "", "", http.StatusOK},
+		{"text", `{"target":"web"}`, "text/plain", http.StatusUnsupportedMediaType},
+		{"form", "target=web", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"missing", `{"target":"web"}`, "", http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/silences", strings.NewReader(tt.body))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/report"
+)
+
+// ReportQuery represents query parameters for on-demand reports
+type ReportQuery struct {
+	Month  string `form:"month"`  // YYYY-MM (default: current month)
+	From   string `form:"from"`   // RFC3339, overrides month
+	To     string `form:"to"`     // RFC3339, overrides month
+	Target string `form:"target"` // Restrict to a single target
+	Group  string `form:"group"`  // Restrict to a single group
+	Format string `form:"format"` // json (default), csv, html
+}
+
+// GetReport generates an SLA / availability report on demand
+func (h *Handler) GetReport(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	var query ReportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	period := report.MonthPeriod(time.Now())
+	if query.Month != "" {
+		p, err := report.ParseMonth(query.Month)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		period = p
+	}
+	if query.From != "" || query.To != "" {
+		from, errFrom := time.Parse(time.RFC3339, query.From)
+		to, errTo := time.Parse(time.RFC3339, query.To)
+		if errFrom != nil || errTo != nil || !to.After(from) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "from and to must both be RFC3339 timestamps with from < to",
+			})
+			return
+		}
+		period = report.CustomPeriod(from, to)
+	}
+
+	format := query.Format
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "format must be one of: json, csv, html",
+		})
+		return
+	}
+
+	r, err := h.collector.Reports().Generate(period, report.Filter{
+		Target: query.Target,
+		Group:  query.Group,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := report.Render(&buf, r, format); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": "Failed to render report: " + err.Error(),
+		})
+		return
+	}
+
+	if format == "csv" {
+		c.Header("Content-Disposition", `attachment; filename="sla-`+r.Label+`.csv"`)
+	}
+	c.Data(http.StatusOK, report.ContentType(format), buf.Bytes())
+}
+
+// ListReportFiles returns the reports written by the scheduler
+func (h *Handler) ListReportFiles(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusOK, []report.FileInfo{})
+		return
+	}
+
+	files, err := h.collector.Reports().ListFiles()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, files)
+}
+
+// GetReportFile serves a report file written by the scheduler
+func (h *Handler) GetReportFile(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Report not found",
+		})
+		return
+	}
+
+	path, err := h.collector.Reports().FilePath(c.Param("file"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.File(path)
+}
@@ -2,12 +2,21 @@ package api
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/auth"
 )
 
-// SetupRoutes configures all API routes on the given router
-func SetupRoutes(router *gin.Engine, handler *Handler, hub *Hub) {
+// SetupRoutes configures all API routes on the given router.
+// When authenticator has tokens, every /api/v1 route requires one; without
+// tokens the API is read-only.
+func SetupRoutes(router *gin.Engine, handler *Handler, hub *Hub, authenticator *auth.Authenticator) {
 	// API v1 group
 	v1 := router.Group("/api/v1")
+	if authenticator.Enabled() {
+		v1.Use(Auth(authenticator))
+	} else {
+		v1.Use(ReadOnly())
+	}
+	v1.Use(RequireJSON())
 	{
 		// System endpoints
 		v1.GET("/status", handler.GetStatus)
@@ -18,13 +27,48 @@ func SetupRoutes(router *gin.Engine, handler *Handler, hub *Hub) {
 		v1.GET("/targets/:name", handler.GetTarget)
 		v1.GET("/targets/:name/stats", handler.GetTargetStats)
 		v1.GET("/targets/:name/history", handler.GetTargetHistory)
+		v1.GET("/targets/:name/baseline", handler.GetTargetBaseline)
+		v1.GET("/targets/:name/shifts", handler.GetTargetShifts)
+		v1.GET("/targets/:name/graph.png", handler.GetTargetGraph)
+		v1.GET("/targets/:name/graph.svg", handler.GetTargetGraph)
+		v1.GET("/targets/:name/export", handler.ExportTargetHistory)
+		v1.POST("/targets/:name/import", handler.ImportTargetHistory)
+		v1.POST("/targets/:name/rename", handler.RenameTarget)
+
+		// Report endpoints
+		v1.GET("/reports", handler.GetReport)
+		v1.GET("/reports/files", handler.ListReportFiles)
+		v1.GET("/reports/files/:file", handler.GetReportFile)
+		v1.GET("/storage", handler.GetStorageUsage)
+		v1.POST("/storage/orphans/:id/archive", handler.ArchiveOrphan)
+		v1.DELETE("/storage/orphans/:id", handler.DeleteOrphan)
+		v1.GET("/agents", handler.GetAgents)
+		v1.GET("/anomalies", handler.GetAnomalies)
+		v1.GET("/events", handler.GetEvents)
+		v1.GET("/maintenance", handler.GetMaintenance)
+		v1.GET("/silences", handler.GetSilences)
+		v1.POST("/silences", handler.CreateSilence)
+		v1.DELETE("/silences/:id", handler.DeleteSilence)
 
-		// WebSocket endpoint
+		// WebSocket and Server-Sent Events endpoints
 		if hub != nil {
-			v1.GET("/ws", ServeWebSocket(hub))
+			v1.GET("/ws", ServeWebSocket(hub, handler.config.Server.AllowedOrigins))
+			v1.GET("/stream", ServeStream(hub))
 		}
 	}
 
+	// Agent endpoints always require a token with the agent role
+	agents := router.Group("/api/v1/agent", AgentAuth(authenticator), RequireJSON())
+	{
+		agents.GET("/assignment", handler.GetAgentAssignment)
+		agents.POST("/results", handler.PostAgentResults)
+	}
+
+	// Web dashboard
+	if handler.config.Server.Dashboard {
+		setupDashboard(router)
+	}
+
 	// Health check endpoint (outside versioned API)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{"status": "healthy"})
@@ -8,7 +8,9 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/auth"
 	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/tlsutil"
 )
 
 // Server represents the API server
@@ -30,16 +32,29 @@ func NewServer(cfg *config.Config) *Server {
 	// Apply middleware
 	router.Use(ErrorHandler())
 	router.Use(RequestLogger())
-	router.Use(CORS())
+	router.Use(CORS(cfg.Server.AllowedOrigins))
+
+	// Load API tokens. If the token file cannot be read, fail closed with a
+	// token list nobody can match rather than serving without authentication.
+	authenticator, err := auth.New(cfg.Server.Auth)
+	if err != nil {
+		log.Printf("[API] Failed to load API tokens, rejecting all API requests: %v", err)
+		authenticator = auth.Deny()
+	} else if authenticator.Enabled() {
+		log.Printf("[API] Token authentication enabled")
+	} else {
+		log.Printf("[API] No API tokens configured, the API is read-only")
+	}
 
 	// Create handler
 	handler := NewHandler(cfg)
 
 	// Create WebSocket hub
 	hub := NewHub()
+	hub.status = handler.status
 
 	// Setup routes (including WebSocket)
-	SetupRoutes(router, handler, hub)
+	SetupRoutes(router, handler, hub, authenticator)
 
 	return &Server{
 		config:  cfg,
@@ -59,8 +74,28 @@ func (s *Server) Start(address string) error {
 		IdleTimeout:  60 * time.Second,
 	}
 
-	log.Printf("[API] Starting server on %s", address)
-	if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+	if !s.config.Server.TLS.Enabled() {
+		log.Printf("[API] Starting server on %s", address)
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return fmt.Errorf("server error: %w", err)
+		}
+		return nil
+	}
+
+	tlsCfg := s.config.Server.TLS
+	reloader, err := tlsutil.NewReloader(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile, tlsCfg.ReloadInterval)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+	s.httpServer.TLSConfig = reloader.ServerConfig()
+
+	mode := "TLS"
+	if reloader.MutualTLS() {
+		mode = "mutual TLS"
+	}
+	log.Printf("[API] Starting server on %s (%s)", address, mode)
+	// Certificates come from TLSConfig, so no file names are passed here
+	if err := s.httpServer.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
 	}
 	return nil
@@ -0,0 +1,110 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/collector"
+)
+
+// ShiftQuery represents query parameters for latency shifts
+type ShiftQuery struct {
+	Range string `form:"range"` // e.g. 1d, 1w: search this much history instead of listing recorded shifts
+	From  string `form:"from"`  // RFC3339, search from/to instead
+	To    string `form:"to"`    // RFC3339
+}
+
+// GetTargetShifts returns the lasting steps in a target's median latency:
+// those recorded by the periodic search, or those found in a given range of
+// history
+func (h *Handler) GetTargetShifts(c *gin.Context) {
+	name := c.Param("name")
+
+	found := false
+	for _, t := range h.targets() {
+		if t.Name == name {
+			found = true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Not Found",
+			"message": "Target not found: " + name,
+		})
+		return
+	}
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	var query ShiftQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "Invalid query parameters: " + err.Error(),
+		})
+		return
+	}
+
+	var shifts []collector.LatencyShift
+	switch {
+	case query.From != "" || query.To != "":
+		from, errFrom := time.Parse(time.RFC3339, query.From)
+		to, errTo := time.Parse(time.RFC3339, query.To)
+		if errFrom != nil || errTo != nil || !to.After(from) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "from and to must both be RFC3339 timestamps with from < to",
+			})
+			return
+		}
+		s, err := h.collector.FindLatencyShifts(name, from, to)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to search history: " + err.Error(),
+			})
+			return
+		}
+		shifts = s
+
+	case query.Range != "":
+		span, err := parseRange(query.Range)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		now := time.Now()
+		s, err := h.collector.FindLatencyShifts(name, now.Add(-span), now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": "Failed to search history: " + err.Error(),
+			})
+			return
+		}
+		shifts = s
+
+	default:
+		shifts = h.collector.LatencyShifts(name)
+	}
+
+	if shifts == nil {
+		shifts = []collector.LatencyShift{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"target": name,
+		"shifts": shifts,
+		"count":  len(shifts),
+	})
+}
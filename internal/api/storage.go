@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStorageUsage returns disk usage per data file, including orphaned files
+// that no configured target refers to. With ?orphans=true only orphans are listed.
+func (h *Handler) GetStorageUsage(c *gin.Context) {
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	usage, err := h.collector.StorageUsage()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if c.Query("orphans") == "true" {
+		files := usage.Files[:0]
+		for _, f := range usage.Files {
+			if f.Orphan {
+				files = append(files, f)
+			}
+		}
+		usage.Files = files
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+// ArchiveOrphan moves an orphaned data file to data_dir/archive
+func (h *Handler) ArchiveOrphan(c *gin.Context) {
+	h.removeOrphan(c, true)
+}
+
+// DeleteOrphan permanently deletes an orphaned data file
+func (h *Handler) DeleteOrphan(c *gin.Context) {
+	h.removeOrphan(c, false)
+}
+
+// removeOrphan archives or deletes the orphaned data file named by the id parameter
+func (h *Handler) removeOrphan(c *gin.Context, archive bool) {
+	id := c.Param("id")
+
+	if h.collector == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"error":   "Service Unavailable",
+			"message": "Collector not available",
+		})
+		return
+	}
+
+	dest, err := h.collector.RemoveOrphan(id, archive)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if archive {
+		c.JSON(http.StatusOK, gin.H{"status": "archived", "id": id, "path": dest})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "deleted", "id": id})
+}
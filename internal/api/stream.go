@@ -0,0 +1,170 @@
+package api
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// streamKeepAlive is how often an idle event stream sends a comment so that
+// proxies do not close it
+const streamKeepAlive = 15 * time.Second
+
+// defaultStreamTypes are streamed when the request does not pick types
+var defaultStreamTypes = []string{MsgProbeResult, MsgStatsUpdate}
+
+// ServeStream handles Server-Sent Events requests. The query selects what is
+// streamed, as a WebSocket subscribe does:
+//
+//	targets: comma-separated target names (default: all)
+//	types:   comma-separated message types (default: probe_result,stats_update)
+//	rtts:    "true" to include individual RTTs in probe results
+//
+// Probe results carry their sequence number as event ID. A Last-Event-ID
+// header (or last_event_id parameter) replays the results after it before
+// live ones.
+func ServeStream(hub *Hub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rtts := c.Query("rtts") == "true"
+		msg := ClientMessage{
+			Type:    "subscribe",
+			Targets: queryList(c, "targets"),
+			Types:   queryList(c, "types"),
+			RTTs:    &rtts,
+		}
+		if len(msg.Targets) == 0 {
+			msg.Targets = []string{"all"}
+		}
+		if len(msg.Types) == 0 {
+			msg.Types = defaultStreamTypes
+		}
+		if unknown := unknownTypes(msg.Types); len(unknown) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "Unknown message types: " + strings.Join(unknown, ", "),
+			})
+			return
+		}
+
+		lastID := c.GetHeader("Last-Event-ID")
+		if lastID == "" {
+			lastID = c.Query("last_event_id")
+		}
+		if lastID != "" {
+			after, err := strconv.ParseUint(lastID, 10, 64)
+			if err != nil || after == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   "Bad Request",
+					"message": "Last-Event-ID must be an event ID sent by this stream",
+				})
+				return
+			}
+			msg.After = after
+		}
+
+		// The stream outlives the server's write timeout
+		if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+			log.Printf("[API] Stream write deadline not cleared: %v", err)
+		}
+
+		client := &Client{
+			hub:     hub,
+			send:    make(chan ServerMessage, 256),
+			targets: make(map[string]bool),
+		}
+		hub.register <- client
+		defer func() {
+			select {
+			case hub.unregister <- client:
+			case <-hub.done:
+			}
+		}()
+
+		select {
+		case hub.subscribe <- hub.newSubscription(client, msg):
+		case <-hub.done:
+			return
+		}
+
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no") // Disable nginx response buffering
+		c.Status(http.StatusOK)
+		sse.Event{}.WriteContentType(c.Writer)
+		c.Writer.Flush()
+
+		keepAlive := time.NewTicker(streamKeepAlive)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+
+			case message, ok := <-client.send:
+				if !ok {
+					return // Hub stopped or the client fell behind
+				}
+				if err := writeStreamEvent(c.Writer, message); err != nil {
+					return
+				}
+				c.Writer.Flush()
+
+			case <-keepAlive.C:
+				if _, err := io.WriteString(c.Writer, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// writeStreamEvent writes a hub message as Server-Sent Events. A backfill is
+// written as the probe_result events it replays.
+func writeStreamEvent(w io.Writer, message ServerMessage) error {
+	switch data := message.Data.(type) {
+	case BackfillData:
+		for _, result := range data.Results {
+			if err := writeResultEvent(w, result); err != nil {
+				return err
+			}
+		}
+		return nil
+	case probe.ProbeResult:
+		return writeResultEvent(w, data)
+	}
+	return sse.Encode(w, sse.Event{Event: message.Type, Data: message.Data})
+}
+
+// writeResultEvent writes a probe result, identified by its sequence number.
+// Results replayed from persistent storage have none and leave the client's
+// last event ID as it was.
+func writeResultEvent(w io.Writer, result probe.ProbeResult) error {
+	event := sse.Event{Event: MsgProbeResult, Data: result}
+	if result.Seq > 0 {
+		event.Id = strconv.FormatUint(result.Seq, 10)
+	}
+	return sse.Encode(w, event)
+}
+
+// queryList returns a query parameter given as a comma-separated list,
+// repeated, or both
+func queryList(c *gin.Context, key string) []string {
+	var list []string
+	for _, value := range c.QueryArray(key) {
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
+		}
+	}
+	return list
+}
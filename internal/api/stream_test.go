@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+func TestStreamEventIDs(t *testing.T) {
+	var buf strings.Builder
+	backfill := BackfillData{Results: []probe.ProbeResult{
+		{Target: "gw"},          // Replayed from RRD storage
+		{Target: "gw", Seq: 7},  // Buffered
+		{Target: "dns", Seq: 9}, // Buffered
+	}}
+	if err := writeStreamEvent(&buf, ServerMessage{Type: MsgBackfill, Data: backfill}); err != nil {
+		t.Fatalf("writeStreamEvent() error = %v", err)
+	}
+
+	var ids []string
+	for _, event := range strings.Split(strings.TrimSpace(buf.String()), "\n\n") {
+		id := ""
+		for _, line := range strings.Split(event, "\n") {
+			if v, ok := strings.CutPrefix(line, "id:"); ok {
+				id = v
+			}
+		}
+		ids = append(ids, id)
+	}
+	if got, want := strings.Join(ids, ","), ",7,9"; got != want {
+		t.Errorf("event IDs = %q, want %q", got, want)
+	}
+}
+
+func TestStreamLastEventID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/stream", ServeStream(NewHub()))
+
+	tests := []struct {
+		name   string
+		lastID string
+	}{
+		{"timestamp", "2024-01-15T10:30:00.123456789Z"},
+		{"zero", "0"},
+		{"negative", "-5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+			req.Header.Set("Last-Event-ID", tt.lastID)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
@@ -3,14 +3,20 @@ package api
 import (
 	"encoding/json"
 	"log"
+	"math"
 	"net/http"
+	"slices"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
+	"github.com/wellsgz/pulse/internal/auth"
 	"github.com/wellsgz/pulse/internal/collector"
 	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
 )
 
 const (
@@ -24,29 +30,80 @@ const (
 	pingPeriod = (pongWait * 9) / 10
 
 	// Maximum message size allowed from peer
-	maxMessageSize = 512
+	maxMessageSize = 4096
+
+	// Oldest results a subscribe may replay with "since" or "after"
+	maxReplayWindow = 24 * time.Hour
 )
 
-var upgrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true // Allow all origins for development
-	},
+// newUpgrader creates a WebSocket upgrader that accepts same-origin requests,
+// non-browser clients and the configured allowed origins
+func newUpgrader(allowedOrigins []string) *websocket.Upgrader {
+	return &websocket.Upgrader{
+		ReadBufferSize:  1024,
+		WriteBufferSize: 1024,
+		CheckOrigin: func(r *http.Request) bool {
+			return auth.OriginAllowed(r.Header.Get("Origin"), r.Host, allowedOrigins)
+		},
+	}
 }
 
+// Server message types
+const (
+	MsgProbeResult  = "probe_result"  // Every probe result
+	MsgStatsUpdate  = "stats_update"  // Stats snapshot of all targets, every probe interval
+	MsgTargetEvent  = "target_event"  // Target renamed
+	MsgAlert        = "alert"         // Alert fired or resolved, or target unreachable through its parent
+	MsgAnomaly      = "anomaly"       // Anomaly started or ended
+	MsgLatencyShift = "latency_shift" // Lasting latency step found
+	MsgMaintenance  = "maintenance"   // Target entered or left maintenance
+	MsgDaemonStatus = "daemon_status" // Status as in /status, every probe interval
+	MsgBackfill     = "backfill"      // Results replayed on subscribe
+	MsgError        = "error"
+)
+
+// pushTypes are the message types a client can subscribe to
+var pushTypes = []string{MsgProbeResult, MsgStatsUpdate, MsgTargetEvent, MsgAlert, MsgAnomaly, MsgLatencyShift, MsgMaintenance, MsgDaemonStatus}
+
 // ClientMessage represents a message from client to server
 type ClientMessage struct {
-	Type    string   `json:"type"`    // "subscribe" or "unsubscribe"
-	Targets []string `json:"targets"` // Target names or ["all"]
+	Type    string   `json:"type"`            // "subscribe" or "unsubscribe"
+	Targets []string `json:"targets"`         // Target names or ["all"]
+	Types   []string `json:"types,omitempty"` // Message types (default: ["probe_result"])
+	RTTs    *bool    `json:"rtts,omitempty"`  // Subscribe: include individual RTTs in probe results (nil = unchanged)
+
+	// Subscribe: replay recent results before live ones
+	After    uint64    `json:"after,omitempty"`    // Results after this sequence number (the last one received)
+	Since    time.Time `json:"since,omitempty"`    // Results after this time
+	Backfill int       `json:"backfill,omitempty"` // The last N results per target
 }
 
 // ServerMessage represents a message from server to client
 type ServerMessage struct {
-	Type string      `json:"type"` // "probe_result", "stats_update", "error"
+	Type string      `json:"type"` // One of the Msg* types
 	Data interface{} `json:"data"`
 }
 
+// StatsSnapshot is the data of a stats_update message
+type StatsSnapshot struct {
+	Timestamp time.Time                 `json:"timestamp"`
+	Stats     map[string]*storage.Stats `json:"stats"` // By target name
+}
+
+// BackfillData is the data of a backfill message
+type BackfillData struct {
+	Results []probe.ProbeResult `json:"results"` // In sequence order
+}
+
+// subscription is a subscribe message to be applied by the hub, with the
+// backfill it asked for already gathered
+type subscription struct {
+	client   *Client
+	msg      ClientMessage
+	backfill BackfillData
+	replayed map[string]uint64 // Sequence number backfilled up to, per target
+}
+
 // Hub maintains the set of active clients and broadcasts messages
 type Hub struct {
 	// Registered clients
@@ -61,11 +118,19 @@ type Hub struct {
 	// Unregister requests from clients
 	unregister chan *Client
 
+	// Subscribe requests, applied between broadcasts so replayed and live
+	// results are never interleaved
+	subscribe chan subscription
+
 	// Collector for subscribing to probe results
 	collector *collector.Collector
 
 	// Subscription to collector events
 	collectorSub <-chan probe.ProbeResult
+	eventSub     <-chan collector.Event
+
+	// Builds the daemon_status payload
+	status func() StatusResponse
 
 	// Shutdown signal
 	done chan struct{}
@@ -80,6 +145,7 @@ func NewHub() *Hub {
 		broadcast:  make(chan ServerMessage, 256),
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
+		subscribe:  make(chan subscription),
 		done:       make(chan struct{}),
 	}
 }
@@ -88,6 +154,7 @@ func NewHub() *Hub {
 func (h *Hub) SetCollector(c *collector.Collector) {
 	h.collector = c
 	h.collectorSub = c.Subscribe()
+	h.eventSub = c.SubscribeEvents()
 }
 
 // Run starts the hub's main loop
@@ -96,6 +163,12 @@ func (h *Hub) Run() {
 	if h.collectorSub != nil {
 		go h.listenCollector()
 	}
+	if h.eventSub != nil {
+		go h.listenEvents()
+	}
+	if h.collector != nil {
+		go h.pushLoop(h.collector.Interval())
+	}
 
 	for {
 		select {
@@ -110,6 +183,9 @@ func (h *Hub) Run() {
 			log.Println("[WebSocket] Hub stopped")
 			return
 
+		case sub := <-h.subscribe:
+			h.applySubscription(sub)
+
 		case client := <-h.register:
 			h.mu.Lock()
 			h.clients[client] = true
@@ -128,17 +204,14 @@ func (h *Hub) Run() {
 		case message := <-h.broadcast:
 			h.mu.RLock()
 			for client := range h.clients {
-				// Check if client is subscribed to this target
-				if message.Type == "probe_result" {
-					if result, ok := message.Data.(probe.ProbeResult); ok {
-						if !client.isSubscribed(result.Target) {
-							continue
-						}
-					}
+				// Check if client is subscribed to this type and target
+				msg, ok := client.filter(message)
+				if !ok {
+					continue
 				}
 
 				select {
-				case client.send <- message:
+				case client.send <- msg:
 				default:
 					// Client buffer full, close connection
 					h.mu.RUnlock()
@@ -163,22 +236,269 @@ func (h *Hub) Stop() {
 func (h *Hub) listenCollector() {
 	for result := range h.collectorSub {
 		h.broadcast <- ServerMessage{
-			Type: "probe_result",
+			Type: MsgProbeResult,
 			Data: result,
 		}
 	}
 }
 
-// Client represents a WebSocket client
+// listenEvents forwards target, alert, anomaly, latency shift and maintenance
+// events from the collector
+func (h *Hub) listenEvents() {
+	for event := range h.eventSub {
+		msgType := MsgAlert
+		switch event.Type {
+		case collector.EventTargetRenamed:
+			msgType = MsgTargetEvent
+		case collector.EventAnomalyStarted, collector.EventAnomalyEnded:
+			msgType = MsgAnomaly
+		case collector.EventLatencyShift:
+			msgType = MsgLatencyShift
+		case collector.EventMaintenanceStarted, collector.EventMaintenanceEnded:
+			msgType = MsgMaintenance
+		}
+		h.broadcast <- ServerMessage{Type: msgType, Data: event}
+	}
+}
+
+// pushLoop broadcasts stats snapshots and daemon status every interval
+func (h *Hub) pushLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-h.done:
+			return
+		case <-ticker.C:
+			h.mu.RLock()
+			idle := len(h.clients) == 0
+			h.mu.RUnlock()
+			if idle {
+				continue
+			}
+			for _, msg := range h.snapshot([]string{MsgStatsUpdate, MsgDaemonStatus}) {
+				h.broadcast <- msg
+			}
+		}
+	}
+}
+
+// newSubscription prepares a subscribe message for the hub, gathering the
+// backfill it asks for. Runs on the client's goroutine, as the fetch may hit
+// persistent storage and would stall the hub.
+func (h *Hub) newSubscription(client *Client, msg ClientMessage) subscription {
+	sub := subscription{client: client, msg: msg}
+	if msg.After == 0 && msg.Since.IsZero() && msg.Backfill <= 0 {
+		return sub
+	}
+	if !client.wantsProbeResults() && !slices.Contains(msg.Types, MsgProbeResult) {
+		return sub
+	}
+	sub.backfill, sub.replayed = h.backfill(msg)
+	return sub
+}
+
+// applySubscription subscribes a client, then sends it the gathered backfill
+// and the current state of newly subscribed types. Runs on the hub goroutine.
+func (h *Hub) applySubscription(sub subscription) {
+	client := sub.client
+	h.mu.RLock()
+	registered := h.clients[client]
+	h.mu.RUnlock()
+	if !registered {
+		// Dropped while the backfill was gathered; its send channel is closed
+		return
+	}
+
+	added := client.subscribe(sub.msg.Targets, sub.msg.Types, sub.msg.RTTs)
+
+	var msgs []ServerMessage
+	if sub.replayed != nil && client.wantsProbeResults() {
+		// Add the results recorded while the backfill was gathered. The hub
+		// may have broadcast them already; later ones are skipped when live.
+		for name, seq := range sub.replayed {
+			for _, p := range h.collector.BufferedResults(name, seq) {
+				sub.backfill.Results = append(sub.backfill.Results, replayedResult(name, p))
+				sub.replayed[name] = p.Seq
+			}
+		}
+		client.markReplayed(sub.replayed)
+		if len(sub.backfill.Results) > 0 {
+			sortBySeq(sub.backfill.Results)
+			msgs = append(msgs, ServerMessage{Type: MsgBackfill, Data: sub.backfill})
+		}
+	}
+	for _, snapshot := range h.snapshot(added) {
+		if m, ok := client.filter(snapshot); ok {
+			msgs = append(msgs, m)
+		}
+	}
+
+	for _, m := range msgs {
+		select {
+		case client.send <- m:
+		default:
+			log.Printf("[WebSocket] Client buffer full, dropping %s message", m.Type)
+		}
+	}
+}
+
+// backfill collects the results a subscribe asked to replay, and the sequence
+// number each target is replayed up to, so live results already replayed are
+// skipped
+func (h *Hub) backfill(msg ClientMessage) (BackfillData, map[string]uint64) {
+	data := BackfillData{Results: []probe.ProbeResult{}}
+	if h.collector == nil {
+		return data, nil
+	}
+
+	// Every result up to here is buffered, so read below whatever the filter
+	// leaves out
+	gathered := h.collector.LastSeq()
+
+	since := msg.Since
+	oldest := time.Now().Add(-maxReplayWindow)
+	if (since.IsZero() && msg.After > 0) || (!since.IsZero() && since.Before(oldest)) {
+		since = oldest
+	}
+
+	targets := msg.Targets
+	if slices.Contains(targets, "all") {
+		targets = targets[:0:0]
+		for _, t := range h.collector.GetTargets() {
+			targets = append(targets, t.Name)
+		}
+	}
+
+	replayed := make(map[string]uint64)
+	for _, name := range targets {
+		points, err := h.collector.RecentResults(name, since, msg.After, msg.Backfill)
+		if err != nil {
+			log.Printf("[WebSocket] Backfill for %s incomplete: %v", name, err)
+		}
+		replayed[name] = gathered
+		for _, p := range points {
+			data.Results = append(data.Results, replayedResult(name, p))
+			replayed[name] = max(replayed[name], p.Seq)
+		}
+	}
+	sortBySeq(data.Results)
+	return data, replayed
+}
+
+// sortBySeq orders replayed results as they were recorded. Results read from
+// persistent storage have no sequence number and are older than any buffered
+// result, so they come first, by time.
+func sortBySeq(results []probe.ProbeResult) {
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Seq != results[j].Seq {
+			return results[i].Seq < results[j].Seq
+		}
+		return results[i].Timestamp.Before(results[j].Timestamp)
+	})
+}
+
+// replayedResult turns a buffered or stored data point back into a probe
+// result. Only the median latency and loss are known.
+func replayedResult(target string, p storage.DataPoint) probe.ProbeResult {
+	result := probe.ProbeResult{
+		Target:    target,
+		Timestamp: p.Timestamp,
+		LatencyMs: p.Value,
+		Success:   !math.IsNaN(p.Value),
+		LossPct:   p.Loss * 100,
+		Seq:       p.Seq,
+	}
+	if !result.Success {
+		result.LatencyMs = -1
+		result.LossPct = 100
+	}
+	return result
+}
+
+// snapshot returns the current state for the given message types: a stats
+// snapshot, the daemon status, the alerts currently firing and the active
+// anomalies. Types that
+// only carry changes are ignored.
+func (h *Hub) snapshot(types []string) []ServerMessage {
+	var msgs []ServerMessage
+	for _, t := range types {
+		switch t {
+		case MsgStatsUpdate:
+			if h.collector != nil {
+				msgs = append(msgs, ServerMessage{Type: MsgStatsUpdate, Data: StatsSnapshot{
+					Timestamp: time.Now(),
+					Stats:     h.collector.GetAllStats(),
+				}})
+			}
+		case MsgDaemonStatus:
+			if h.status != nil {
+				msgs = append(msgs, ServerMessage{Type: MsgDaemonStatus, Data: h.status()})
+			}
+		case MsgAlert:
+			if h.collector != nil {
+				for _, alert := range h.collector.Alerts() {
+					msgs = append(msgs, ServerMessage{Type: MsgAlert, Data: collector.Event{
+						Type:      collector.EventAlertFiring,
+						Target:    alert.Target,
+						Timestamp: alert.Since,
+						Alert:     &alert,
+					}})
+				}
+				for _, alert := range h.collector.Unreachable() {
+					msgs = append(msgs, ServerMessage{Type: MsgAlert, Data: collector.Event{
+						Type:      collector.EventTargetUnreachable,
+						Target:    alert.Target,
+						Timestamp: alert.Since,
+						Alert:     &alert,
+					}})
+				}
+			}
+		case MsgAnomaly:
+			if h.collector != nil {
+				for _, anomaly := range h.collector.Anomalies() {
+					msgs = append(msgs, ServerMessage{Type: MsgAnomaly, Data: collector.Event{
+						Type:      collector.EventAnomalyStarted,
+						Target:    anomaly.Target,
+						Timestamp: anomaly.Since,
+						Anomaly:   &anomaly,
+					}})
+				}
+			}
+		case MsgMaintenance:
+			if h.collector != nil {
+				for _, m := range h.collector.MaintenanceActive() {
+					msgs = append(msgs, ServerMessage{Type: MsgMaintenance, Data: collector.Event{
+						Type:        collector.EventMaintenanceStarted,
+						Target:      m.Target,
+						Timestamp:   m.Start,
+						Maintenance: &m,
+					}})
+				}
+			}
+		}
+	}
+	return msgs
+}
+
+// Client represents a WebSocket or Server-Sent Events client
 type Client struct {
 	hub  *Hub
-	conn *websocket.Conn
+	conn *websocket.Conn // nil for event streams
 	send chan ServerMessage
 
 	// Subscribed targets (empty = subscribed to all)
 	targets map[string]bool
 	allTargets bool
-	mu      sync.RWMutex
+	types   map[string]bool // Subscribed message types (nil = probe_result only)
+	rtts    bool            // Include individual RTTs in probe results
+
+	// Sequence number replayed up to per target; older live results are
+	// duplicates
+	replayed map[string]uint64
+
+	mu sync.RWMutex
 }
 
 // isSubscribed checks if client is subscribed to a target
@@ -192,25 +512,136 @@ func (c *Client) isSubscribed(target string) bool {
 	return c.targets[target]
 }
 
-// subscribe adds targets to subscription
-func (c *Client) subscribe(targets []string) {
+// wantsType reports whether the client subscribed to a message type.
+// Must be called with c.mu held
+func (c *Client) wantsType(msgType string) bool {
+	if c.types == nil {
+		return msgType == MsgProbeResult
+	}
+	return c.types[msgType]
+}
+
+// wantsProbeResults reports whether the client subscribed to probe results
+func (c *Client) wantsProbeResults() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.wantsType(MsgProbeResult)
+}
+
+// markReplayed records the sequence number each target is replayed up to
+func (c *Client) markReplayed(replayed map[string]uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.replayed == nil {
+		c.replayed = make(map[string]uint64)
+	}
+	for target, seq := range replayed {
+		c.replayed[target] = seq
+	}
+}
+
+// filter returns the message as this client should receive it, or false if
+// the client is not subscribed to its type or target
+func (c *Client) filter(message ServerMessage) (ServerMessage, bool) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if message.Type == MsgError {
+		return message, true
+	}
+	if !c.wantsType(message.Type) {
+		return message, false
+	}
+
+	switch data := message.Data.(type) {
+	case probe.ProbeResult:
+		if !c.allTargets && !c.targets[data.Target] {
+			return message, false
+		}
+		if last, ok := c.replayed[data.Target]; ok {
+			if data.Seq <= last {
+				return message, false // Already sent in the backfill
+			}
+			delete(c.replayed, data.Target)
+		}
+		if !c.rtts {
+			data.RTTsMs = nil
+			message.Data = data
+		}
+
+	case StatsSnapshot:
+		if c.allTargets {
+			return message, true
+		}
+		stats := make(map[string]*storage.Stats)
+		for name, s := range data.Stats {
+			if c.targets[name] {
+				stats[name] = s
+			}
+		}
+		if len(stats) == 0 {
+			return message, false
+		}
+		data.Stats = stats
+		message.Data = data
+
+	case collector.Event:
+		if data.Type == collector.EventTargetRenamed && c.targets[data.OldName] {
+			// Follow the target to its new name
+			delete(c.targets, data.OldName)
+			c.targets[data.Target] = true
+		}
+		if !c.allTargets && !c.targets[data.Target] {
+			return message, false
+		}
+	}
+	return message, true
+}
+
+// subscribe adds targets and message types to the subscription, and turns
+// RTTs on or off if given. It returns the types that were newly added.
+func (c *Client) subscribe(targets, types []string, rtts *bool) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if rtts != nil {
+		c.rtts = *rtts
+	}
+
+	var added []string
+	if len(types) > 0 && c.types == nil {
+		c.types = map[string]bool{}
+	}
+	for _, t := range types {
+		if !c.types[t] {
+			c.types[t] = true
+			added = append(added, t)
+		}
+	}
+
 	for _, t := range targets {
 		if t == "all" {
 			c.allTargets = true
-			return
+			return added
 		}
 		c.targets[t] = true
 	}
+	return added
 }
 
-// unsubscribe removes targets from subscription
-func (c *Client) unsubscribe(targets []string) {
+// unsubscribe removes targets and message types from subscription
+func (c *Client) unsubscribe(targets, types []string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if len(types) > 0 && c.types == nil {
+		c.types = map[string]bool{MsgProbeResult: true}
+	}
+	for _, t := range types {
+		delete(c.types, t)
+	}
+
 	for _, t := range targets {
 		if t == "all" {
 			c.allTargets = false
@@ -251,20 +682,40 @@ func (c *Client) readPump() {
 			continue
 		}
 
+		if unknown := unknownTypes(msg.Types); len(unknown) > 0 {
+			c.sendError("Unknown message types: " + strings.Join(unknown, ", "))
+			continue
+		}
+
 		// Handle message
 		switch msg.Type {
 		case "subscribe":
-			c.subscribe(msg.Targets)
-			log.Printf("[WebSocket] Client subscribed to: %v", msg.Targets)
+			select {
+			case c.hub.subscribe <- c.hub.newSubscription(c, msg):
+			case <-c.hub.done:
+				return
+			}
+			log.Printf("[WebSocket] Client subscribed to: %v %v", msg.Targets, msg.Types)
 		case "unsubscribe":
-			c.unsubscribe(msg.Targets)
-			log.Printf("[WebSocket] Client unsubscribed from: %v", msg.Targets)
+			c.unsubscribe(msg.Targets, msg.Types)
+			log.Printf("[WebSocket] Client unsubscribed from: %v %v", msg.Targets, msg.Types)
 		default:
 			c.sendError("Unknown message type: " + msg.Type)
 		}
 	}
 }
 
+// unknownTypes returns the types that cannot be subscribed to
+func unknownTypes(types []string) []string {
+	var unknown []string
+	for _, t := range types {
+		if !slices.Contains(pushTypes, t) {
+			unknown = append(unknown, t)
+		}
+	}
+	return unknown
+}
+
 // writePump pumps messages from the hub to the WebSocket connection
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
@@ -306,13 +757,14 @@ func (c *Client) writePump() {
 // sendError sends an error message to the client
 func (c *Client) sendError(msg string) {
 	select {
-	case c.send <- ServerMessage{Type: "error", Data: msg}:
+	case c.send <- ServerMessage{Type: MsgError, Data: msg}:
 	default:
 	}
 }
 
 // ServeWebSocket handles WebSocket requests from clients
-func ServeWebSocket(hub *Hub) gin.HandlerFunc {
+func ServeWebSocket(hub *Hub, allowedOrigins []string) gin.HandlerFunc {
+	upgrader := newUpgrader(allowedOrigins)
 	return func(c *gin.Context) {
 		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 		if err != nil {
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestApplySubscriptionSkipsDroppedClient(t *testing.T) {
+	h := NewHub()
+	h.status = func() StatusResponse { return StatusResponse{} }
+	client := &Client{hub: h, send: make(chan ServerMessage, 1), targets: make(map[string]bool)}
+	close(client.send)
+
+	// The client was dropped, and its channel closed, before the hub got to
+	// its subscribe; sending the snapshot would panic
+	h.applySubscription(subscription{
+		client: client,
+		msg:    ClientMessage{Type: "subscribe", Targets: []string{"all"}, Types: []string{MsgDaemonStatus}},
+	})
+}
+
+func TestSubscribeRTTs(t *testing.T) {
+	on, off := true, false
+	client := &Client{targets: make(map[string]bool)}
+
+	tests := []struct {
+		name string
+		rtts *bool
+		want bool
+	}{
+		{"turned on", &on, true},
+		{"left unchanged", nil, true},
+		{"turned off", &off, false},
+		{"still off", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client.subscribe([]string{"all"}, nil, tt.rtts)
+			if client.rtts != tt.want {
+				t.Errorf("rtts = %v, want %v", client.rtts, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackfillResumesAfterSeq(t *testing.T) {
+	mem := storage.NewMemoryBuffer(10)
+	h := NewHub()
+	h.collector = collector.NewCollector(&config.Config{}, nil, mem)
+
+	// The slow target's probe started first but finished last
+	start := time.Now().Add(-time.Minute)
+	mem.Write("fast", 1, start.Add(time.Second), 5)
+	mem.Write("slow", 2, start, 50)
+	mem.Write("fast", 3, start.Add(11*time.Second), 5)
+
+	client := &Client{hub: h, send: make(chan ServerMessage, 4), targets: make(map[string]bool)}
+	h.clients[client] = true
+
+	// The client last received the fast target's first result
+	sub := h.newSubscription(client, ClientMessage{
+		Type:    "subscribe",
+		Targets: []string{"fast", "slow"},
+		After:   1,
+	})
+	// Recorded while the backfill was gathered
+	mem.Write("slow", 4, start.Add(10*time.Second), 50)
+	h.applySubscription(sub)
+
+	msg := <-client.send
+	data, ok := msg.Data.(BackfillData)
+	if msg.Type != MsgBackfill || !ok {
+		t.Fatalf("got %s message, want backfill", msg.Type)
+	}
+	var seqs []uint64
+	for _, r := range data.Results {
+		seqs = append(seqs, r.Seq)
+	}
+	if want := []uint64{2, 3, 4}; !slices.Equal(seqs, want) {
+		t.Errorf("backfilled sequence numbers = %v, want %v", seqs, want)
+	}
+
+	tests := []struct {
+		target string
+		seq    uint64
+		want   bool
+	}{
+		{"slow", 4, false}, // Already backfilled
+		{"fast", 3, false},
+		{"slow", 5, true},
+		{"fast", 6, true},
+	}
+	for _, tt := range tests {
+		live := ServerMessage{Type: MsgProbeResult, Data: probe.ProbeResult{Target: tt.target, Seq: tt.seq}}
+		if _, ok := client.filter(live); ok != tt.want {
+			t.Errorf("live result %s/%d sent = %v, want %v", tt.target, tt.seq, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/wellsgz/pulse/internal/config"
+)
+
+// Role is the access level granted by a token
+type Role string
+
+const (
+	RoleRead  Role = "read"  // Read-only access to status, targets, history and reports
+	RoleAdmin Role = "admin" // Full access, including endpoints that modify state
+	RoleAgent Role = "agent" // Only the agent endpoints: fetch assignments and report results
+)
+
+// Allows reports whether the role satisfies the required role
+func (r Role) Allows(required Role) bool {
+	return r == RoleAdmin || r == required
+}
+
+// Identity is the caller authenticated by a token
+type Identity struct {
+	Name string
+	Role Role
+}
+
+// tokenEntry stores a token hash so lookups compare fixed-length values
+type tokenEntry struct {
+	hash     [sha256.Size]byte
+	identity Identity
+}
+
+// Authenticator validates API tokens
+type Authenticator struct {
+	tokens []tokenEntry
+}
+
+// New creates an authenticator from the inline tokens and the token file in cfg.
+// Authentication is disabled when neither is configured.
+func New(cfg config.AuthConfig) (*Authenticator, error) {
+	tokens := append([]config.TokenConfig(nil), cfg.Tokens...)
+
+	if cfg.TokenFile != "" {
+		f, err := os.Open(cfg.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open token file: %w", err)
+		}
+		defer f.Close()
+
+		fileTokens, err := ParseTokenFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("token file %s: %w", cfg.TokenFile, err)
+		}
+		tokens = append(tokens, fileTokens...)
+	}
+
+	a := &Authenticator{}
+	for _, t := range tokens {
+		a.tokens = append(a.tokens, tokenEntry{
+			hash:     sha256.Sum256([]byte(t.Token)),
+			identity: Identity{Name: t.Name, Role: Role(t.Role)},
+		})
+	}
+	return a, nil
+}
+
+// Deny returns an authenticator that is enabled but accepts no token. It is
+// used when configured tokens cannot be loaded, so the API fails closed.
+func Deny() *Authenticator {
+	var unmatchable [sha256.Size]byte
+	if _, err := rand.Read(unmatchable[:]); err != nil {
+		panic(err)
+	}
+	return &Authenticator{tokens: []tokenEntry{{hash: unmatchable}}}
+}
+
+// Enabled reports whether any tokens are configured
+func (a *Authenticator) Enabled() bool {
+	return a != nil && len(a.tokens) > 0
+}
+
+// Authenticate returns the identity for a token
+func (a *Authenticator) Authenticate(token string) (Identity, bool) {
+	if a == nil || token == "" {
+		return Identity{}, false
+	}
+
+	hash := sha256.Sum256([]byte(token))
+	var found Identity
+	ok := false
+	// Check every entry so timing does not reveal the matching position
+	for _, t := range a.tokens {
+		if subtle.ConstantTimeCompare(hash[:], t.hash[:]) == 1 {
+			found, ok = t.identity, true
+		}
+	}
+	return found, ok
+}
+
+// ParseTokenFile reads tokens from a file with one "<role> <token> [name]" entry
+// per line. Blank lines and lines starting with # are ignored.
+func ParseTokenFile(r io.Reader) ([]config.TokenConfig, error) {
+	var tokens []config.TokenConfig
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("line %d: expected \"<role> <token> [name]\"", line)
+		}
+		t := config.TokenConfig{Role: fields[0], Token: fields[1]}
+		if len(fields) == 3 {
+			t.Name = fields[2]
+		}
+		if err := t.Validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		tokens = append(tokens, t)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// OriginAllowed reports whether a browser Origin may access the API. Requests
+// without an Origin (non-browser clients) and same-origin requests are always
+// allowed; other origins must be listed ("*" allows any origin).
+func OriginAllowed(origin, host string, allowed []string) bool {
+	if origin == "" {
+		return true
+	}
+	if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, host) {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wellsgz/pulse/internal/config"
+)
+
+func TestAuthenticate(t *testing.T) {
+	a, err := New(config.AuthConfig{Tokens: []config.TokenConfig{
+		{Name: "grafana", Token: "read-token-0123456789", Role: "read"},
+		{Name: "ops", Token: "admin-token-0123456789", Role: "admin"},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if !a.Enabled() {
+		t.Fatal("Enabled() = false, want true")
+	}
+
+	tests := []struct {
+		token    string
+		wantOK   bool
+		wantRole Role
+	}{
+		{"read-token-0123456789", true, RoleRead},
+		{"admin-token-0123456789", true, RoleAdmin},
+		{"wrong-token-0123456789", false, ""},
+		{"", false, ""},
+	}
+	for _, tt := range tests {
+		id, ok := a.Authenticate(tt.token)
+		if ok != tt.wantOK || id.Role != tt.wantRole {
+			t.Errorf("Authenticate(%q) = %v/%v, want %v/%v", tt.token, id.Role, ok, tt.wantRole, tt.wantOK)
+		}
+	}
+
+	if _, ok := Deny().Authenticate("read-token-0123456789"); ok {
+		t.Error("Deny().Authenticate() accepted a token")
+	}
+	if empty, _ := New(config.AuthConfig{}); empty.Enabled() {
+		t.Error("Enabled() = true without tokens")
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	if !RoleAdmin.Allows(RoleRead) || !RoleAdmin.Allows(RoleAdmin) {
+		t.Error("admin should allow read and admin")
+	}
+	if !RoleRead.Allows(RoleRead) || RoleRead.Allows(RoleAdmin) {
+		t.Error("read should allow only read")
+	}
+}
+
+func TestParseTokenFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"valid", "# comment\n\nread read-token-0123456789 grafana\nadmin admin-token-0123456789\n", 2, false},
+		{"bad role", "owner token-0123456789abcdef\n", 0, true},
+		{"short token", "read short\n", 0, true},
+		{"missing token", "read\n", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := ParseTokenFile(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseTokenFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(tokens) != tt.want {
+				t.Errorf("ParseTokenFile() returned %d tokens, want %d", len(tokens), tt.want)
+			}
+		})
+	}
+}
+
+func TestOriginAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		host    string
+		allowed []string
+		want    bool
+	}{
+		{"no origin", "", "pulse:8080", nil, true},
+		{"same origin", "http://pulse:8080", "pulse:8080", nil, true},
+		{"cross origin denied", "http://evil.example", "pulse:8080", nil, false},
+		{"cross origin listed", "https://grafana.example", "pulse:8080", []string{"https://grafana.example/"}, true},
+		{"wildcard", "http://evil.example", "pulse:8080", []string{"*"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OriginAllowed(tt.origin, tt.host, tt.allowed); got != tt.want {
+				t.Errorf("OriginAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
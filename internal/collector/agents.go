@@ -0,0 +1,164 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// agentOfflineIntervals is how many probe intervals may pass without results
+// before an agent is reported offline
+const agentOfflineIntervals = 3
+
+// agentClockSkew is how far ahead of the master's clock an agent's results
+// may be. Later results are rejected, as they would block every result up
+// to their timestamp.
+const agentClockSkew = 30 * time.Second
+
+// agentState tracks results received from one agent
+type agentState struct {
+	lastSeen time.Time
+	results  int64
+	last     map[string]time.Time // Series name -> newest stored result
+}
+
+// AgentStatus reports the state of a remote agent
+type AgentStatus struct {
+	Name     string     `json:"name"`
+	Online   bool       `json:"online"`
+	LastSeen *time.Time `json:"last_seen,omitempty"`
+	Results  int64      `json:"results"` // Results accepted since startup
+	Targets  int        `json:"targets"` // Targets assigned to the agent
+}
+
+// IsAgent reports whether name is a configured agent
+func (c *Collector) IsAgent(name string) bool {
+	c.agentsMu.Lock()
+	defer c.agentsMu.Unlock()
+	_, ok := c.agents[name]
+	return ok
+}
+
+// AgentTargets returns the targets an agent is assigned to probe, under their
+// original names
+func (c *Collector) AgentTargets(agent string) ([]config.Target, error) {
+	if !c.IsAgent(agent) {
+		return nil, fmt.Errorf("unknown agent: %s", agent)
+	}
+
+	c.targetsMu.RLock()
+	defer c.targetsMu.RUnlock()
+
+	var targets []config.Target
+	for _, t := range c.config.Targets {
+		if t.ProbedBy(agent) {
+			t.Agents = nil
+			targets = append(targets, t)
+		}
+	}
+	return targets, nil
+}
+
+// Ingest stores results reported by an agent under the agent's series of each
+// target. Results for unassigned targets, results not newer than the last
+// one stored (e.g. a batch resent after a lost response) and results from
+// the future are skipped. It returns the number of results accepted.
+func (c *Collector) Ingest(agent string, results []probe.ProbeResult) (int, error) {
+	// Apply in time order so a batch that mixes targets is stored correctly
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Timestamp.Before(results[j].Timestamp)
+	})
+
+	keys := make(map[string]string) // Series name -> storage key
+	c.targetsMu.RLock()
+	for _, t := range c.agentSeries {
+		if t.Agent == agent {
+			keys[t.Name] = t.StorageKey()
+		}
+	}
+	c.targetsMu.RUnlock()
+
+	now := time.Now()
+	accepted, future := c.acceptAgentResults(agent, results, keys, now)
+	if accepted == nil {
+		return 0, fmt.Errorf("unknown agent: %s", agent)
+	}
+
+	for _, result := range accepted {
+		c.record(keys[result.Target], result)
+	}
+
+	if future > 0 {
+		log.Printf("[Collector] Rejected %d result(s) from agent %s timestamped more than %v ahead; check its clock", future, agent, agentClockSkew)
+	}
+	if skipped := len(results) - len(accepted) - future; skipped > 0 {
+		log.Printf("[Collector] Skipped %d result(s) from agent %s (unassigned target or already stored)", skipped, agent)
+	}
+	return len(accepted), nil
+}
+
+// acceptAgentResults filters a batch down to the results to store, renamed to
+// the agent's series, and advances the agent's newest stored result. It
+// returns nil for an unknown agent, and the number of results from the future.
+func (c *Collector) acceptAgentResults(agent string, results []probe.ProbeResult, keys map[string]string, now time.Time) ([]probe.ProbeResult, int) {
+	c.agentsMu.Lock()
+	defer c.agentsMu.Unlock()
+
+	state, ok := c.agents[agent]
+	if !ok {
+		return nil, 0
+	}
+	state.lastSeen = now
+
+	accepted := make([]probe.ProbeResult, 0, len(results))
+	future := 0
+	for _, result := range results {
+		result.Target += config.AgentSeparator + agent
+		if _, assigned := keys[result.Target]; !assigned || !result.Timestamp.After(state.last[result.Target]) {
+			continue
+		}
+		if result.Timestamp.After(now.Add(agentClockSkew)) {
+			future++
+			continue
+		}
+		state.last[result.Target] = result.Timestamp
+		accepted = append(accepted, result)
+	}
+	state.results += int64(len(accepted))
+	return accepted, future
+}
+
+// AgentStatuses returns the state of every configured agent, sorted by name
+func (c *Collector) AgentStatuses() []AgentStatus {
+	c.targetsMu.RLock()
+	assigned := make(map[string]int)
+	for _, t := range c.agentSeries {
+		assigned[t.Agent]++
+	}
+	c.targetsMu.RUnlock()
+
+	c.agentsMu.Lock()
+	defer c.agentsMu.Unlock()
+
+	offlineAfter := agentOfflineIntervals * c.config.Global.Interval
+	statuses := make([]AgentStatus, 0, len(c.agents))
+	for name, state := range c.agents {
+		status := AgentStatus{
+			Name:    name,
+			Results: state.results,
+			Targets: assigned[name],
+		}
+		if !state.lastSeen.IsZero() {
+			lastSeen := state.lastSeen
+			status.LastSeen = &lastSeen
+			status.Online = time.Since(lastSeen) < offlineAfter
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Name < statuses[j].Name })
+	return statuses
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestIngest(t *testing.T) {
+	cfg := &config.Config{
+		Master: config.MasterConfig{Agents: []string{"tokyo"}},
+		Targets: []config.Target{
+			{Name: "google", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"tokyo"}},
+			{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+		},
+	}
+	c := NewCollector(cfg, nil, storage.NewMemoryBuffer(10))
+	now := time.Now()
+	result := func(target string, at time.Time) probe.ProbeResult {
+		return probe.ProbeResult{Target: target, Timestamp: at, Success: true, LatencyMs: 8}
+	}
+
+	tests := []struct {
+		name    string
+		results []probe.ProbeResult
+		want    int
+	}{
+		{
+			name:    "new results",
+			results: []probe.ProbeResult{result("google", now.Add(-2*time.Second)), result("google", now.Add(-time.Second))},
+			want:    2,
+		},
+		{
+			name:    "resent batch",
+			results: []probe.ProbeResult{result("google", now.Add(-time.Second))},
+			want:    0,
+		},
+		{
+			name:    "unassigned target",
+			results: []probe.ProbeResult{result("cloudflare", now)},
+			want:    0,
+		},
+		{
+			name:    "within the clock skew",
+			results: []probe.ProbeResult{result("google", now.Add(agentClockSkew/2))},
+			want:    1,
+		},
+		{
+			name:    "from the future",
+			results: []probe.ProbeResult{result("google", now.Add(24*time.Hour))},
+			want:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.Ingest("tokyo", tt.results)
+			if err != nil {
+				t.Fatalf("Ingest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Ingest() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// A rejected future result does not hold back later results
+	if got, _ := c.Ingest("tokyo", []probe.ProbeResult{result("google", now.Add(agentClockSkew*3/4))}); got != 1 {
+		t.Errorf("Ingest() after a future result = %d, want 1", got)
+	}
+	if _, err := c.Ingest("osaka", nil); err == nil {
+		t.Error("Ingest() from an unknown agent succeeded")
+	}
+}
@@ -0,0 +1,262 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// Defaults for zero alert settings
+const (
+	defaultAlertLossThreshold = 100
+	defaultAlertFailures      = 3
+	defaultAlertRecoveries    = 3
+)
+
+// Alert is the alert state of a target
+type Alert struct {
+	Target    string    `json:"target"`
+	Firing    bool      `json:"firing"`
+	Since     time.Time `json:"since"`            // Start of the current state
+	Reason    string    `json:"reason,omitempty"` // Why the probe that fired the alert failed
+	LossPct   float64   `json:"loss_pct"`         // Of the probe that caused the transition
+	LatencyMs float64   `json:"latency_ms"`       // Of the probe that caused the transition
+
+	// Parent is the down target the alert is attributed to while the target
+	// is unreachable because of it (see Target.DependsOn)
+	Parent string `json:"parent,omitempty"`
+}
+
+// alertState tracks consecutive failing or passing probes of one target
+type alertState struct {
+	alert       Alert
+	streak      int       // Consecutive probes disagreeing with the current state
+	streakStart time.Time // Timestamp of the first of them
+	suppressed  bool      // Fired during maintenance and not published
+	failing     bool      // The last probe failed
+}
+
+// alertTracker evaluates probe results against the alert thresholds
+type alertTracker struct {
+	lossThreshold    float64
+	latencyThreshold float64
+	failures         int
+	recoveries       int
+
+	states  map[string]*alertState // By target name
+	parents map[string]string      // depends_on by target name
+	mu      sync.Mutex
+}
+
+// newAlertTracker creates a tracker, filling in defaults for zero settings
+func newAlertTracker(cfg config.AlertConfig) *alertTracker {
+	t := &alertTracker{
+		lossThreshold:    cfg.LossThreshold,
+		latencyThreshold: cfg.LatencyThreshold,
+		failures:         cfg.Failures,
+		recoveries:       cfg.Recoveries,
+		states:           make(map[string]*alertState),
+		parents:          make(map[string]string),
+	}
+	if t.lossThreshold <= 0 {
+		t.lossThreshold = defaultAlertLossThreshold
+	}
+	if t.failures <= 0 {
+		t.failures = defaultAlertFailures
+	}
+	if t.recoveries <= 0 {
+		t.recoveries = defaultAlertRecoveries
+	}
+	return t
+}
+
+// failure returns why a probe result counts as failing, or "" if it passes
+func (t *alertTracker) failure(r probe.ProbeResult) string {
+	switch {
+	case !r.Success:
+		if r.Error != "" {
+			return "unreachable: " + r.Error
+		}
+		return "unreachable"
+	case r.LossPct >= t.lossThreshold:
+		return fmt.Sprintf("loss %.0f%% >= %.0f%%", r.LossPct, t.lossThreshold)
+	case t.latencyThreshold > 0 && r.LatencyMs > t.latencyThreshold:
+		return fmt.Sprintf("latency %.1fms > %.1fms", r.LatencyMs, t.latencyThreshold)
+	}
+	return ""
+}
+
+// observe records a probe result and returns the new alert state when the
+// result makes the alert fire or resolve, or nil otherwise
+func (t *alertTracker) observe(r probe.ProbeResult) *Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[r.Target]
+	if !ok {
+		state = &alertState{alert: Alert{Target: r.Target, Since: r.Timestamp}}
+		t.states[r.Target] = state
+	}
+
+	reason := t.failure(r)
+	failing := reason != ""
+	state.failing = failing
+	if failing != state.alert.Firing {
+		if state.streak == 0 {
+			state.streakStart = r.Timestamp
+		}
+		state.streak++
+	} else {
+		state.streak = 0
+		return nil
+	}
+
+	needed := t.failures
+	if state.alert.Firing {
+		needed = t.recoveries
+	}
+	if state.streak < needed {
+		return nil
+	}
+
+	state.alert = Alert{
+		Target:    r.Target,
+		Firing:    failing,
+		Since:     state.streakStart,
+		Reason:    reason,
+		LossPct:   r.LossPct,
+		LatencyMs: r.LatencyMs,
+		Parent:    state.alert.Parent, // Until notifyAlert decides
+	}
+	state.streak = 0
+
+	alert := state.alert
+	return &alert
+}
+
+// firing returns the alerts currently firing, sorted by target. Alerts
+// suppressed by maintenance or attributed to a down parent are left out.
+func (t *alertTracker) firing() []Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	alerts := make([]Alert, 0)
+	for _, state := range t.states {
+		if state.alert.Firing && !state.suppressed && state.alert.Parent == "" {
+			alerts = append(alerts, state.alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		return alerts[i].Target < alerts[j].Target
+	})
+	return alerts
+}
+
+// suppress marks a target's alert as fired during maintenance, or clears the
+// mark. It returns whether the alert was marked before.
+func (t *alertTracker) suppress(name string, suppressed bool) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[name]
+	if !ok {
+		return false
+	}
+	was := state.suppressed
+	state.suppressed = suppressed
+	return was
+}
+
+// unsuppress clears the mark of a target's alert and returns the alert if it
+// fired during maintenance and is still firing
+func (t *alertTracker) unsuppress(name string) *Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[name]
+	if !ok || !state.suppressed {
+		return nil
+	}
+	state.suppressed = false
+	if !state.alert.Firing {
+		return nil
+	}
+	alert := state.alert
+	return &alert
+}
+
+// rename moves a target's alert state and dependencies to its new name
+func (t *alertTracker) rename(oldName, newName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, ok := t.states[oldName]; ok {
+		state.alert.Target = newName
+		t.states[newName] = state
+		delete(t.states, oldName)
+	}
+	for _, state := range t.states {
+		if state.alert.Parent == oldName {
+			state.alert.Parent = newName
+		}
+	}
+
+	if parent, ok := t.parents[oldName]; ok {
+		t.parents[newName] = parent
+		delete(t.parents, oldName)
+	}
+	for child, parent := range t.parents {
+		if parent == oldName {
+			t.parents[child] = newName
+		}
+	}
+}
+
+// Alerts returns the alerts currently firing, sorted by target. Alerts of
+// targets in maintenance or unreachable because of a down parent are left out.
+func (c *Collector) Alerts() []Alert {
+	return c.alerts.firing()
+}
+
+// notifyAlert publishes an alert transition. Alerts firing while the target
+// is in maintenance are held back, and so is their resolution. Alerts firing
+// while a parent is down are published as the target being unreachable.
+func (c *Collector) notifyAlert(alert *Alert, at time.Time) {
+	if alert.Firing {
+		if m := c.maintenance.covering(alert.Target, at); m != nil {
+			c.alerts.suppress(alert.Target, true)
+			log.Printf("[Collector] Alert for %s suppressed by maintenance (%s): %s", alert.Target, m.describe(), alert.Reason)
+			return
+		}
+		if parent := c.alerts.attribute(alert.Target); parent != "" {
+			alert.Parent = parent
+			log.Printf("[Collector] %s unreachable because %s is down: %s", alert.Target, parent, alert.Reason)
+			c.publish(Event{Type: EventTargetUnreachable, Target: alert.Target, Timestamp: at, Alert: alert})
+			return
+		}
+		log.Printf("[Collector] Alert firing for %s: %s", alert.Target, alert.Reason)
+	} else {
+		if c.alerts.suppress(alert.Target, false) {
+			log.Printf("[Collector] Suppressed alert for %s resolved", alert.Target)
+			return
+		}
+		if parent := c.alerts.detach(alert.Target); parent != "" {
+			alert.Parent = parent
+			log.Printf("[Collector] %s reachable again", alert.Target)
+			c.publish(Event{Type: EventTargetReachable, Target: alert.Target, Timestamp: at, Alert: alert})
+			return
+		}
+		log.Printf("[Collector] Alert resolved for %s", alert.Target)
+	}
+
+	eventType := EventAlertResolved
+	if alert.Firing {
+		eventType = EventAlertFiring
+	}
+	c.publish(Event{Type: eventType, Target: alert.Target, Timestamp: at, Alert: alert})
+}
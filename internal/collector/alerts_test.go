@@ -0,0 +1,101 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+func TestAlertTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ok := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 10}
+	lossy := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 10, LossPct: 60}
+	down := probe.ProbeResult{Target: "A", Success: false, LatencyMs: -1, LossPct: 100, Error: "timeout"}
+	slow := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 250}
+
+	tests := []struct {
+		name    string
+		cfg     config.AlertConfig
+		results []probe.ProbeResult
+		want    []string // Transition after each result: "", "firing" or "resolved"
+	}{
+		{
+			name:    "fires after consecutive failures",
+			cfg:     config.AlertConfig{Failures: 2, Recoveries: 2},
+			results: []probe.ProbeResult{down, down, down},
+			want:    []string{"", "firing", ""},
+		},
+		{
+			name:    "failure streak broken by success",
+			cfg:     config.AlertConfig{Failures: 2, Recoveries: 2},
+			results: []probe.ProbeResult{down, ok, down, ok},
+			want:    []string{"", "", "", ""},
+		},
+		{
+			name:    "resolves after consecutive recoveries",
+			cfg:     config.AlertConfig{Failures: 1, Recoveries: 2},
+			results: []probe.ProbeResult{down, ok, down, ok, ok},
+			want:    []string{"firing", "", "", "", "resolved"},
+		},
+		{
+			name:    "partial loss passes by default",
+			cfg:     config.AlertConfig{Failures: 1},
+			results: []probe.ProbeResult{lossy},
+			want:    []string{""},
+		},
+		{
+			name:    "loss threshold",
+			cfg:     config.AlertConfig{Failures: 1, LossThreshold: 50},
+			results: []probe.ProbeResult{lossy},
+			want:    []string{"firing"},
+		},
+		{
+			name:    "latency threshold",
+			cfg:     config.AlertConfig{Failures: 1, LatencyThreshold: 100},
+			results: []probe.ProbeResult{ok, slow},
+			want:    []string{"", "firing"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newAlertTracker(tt.cfg)
+			for i, r := range tt.results {
+				r.Timestamp = start.Add(time.Duration(i) * 10 * time.Second)
+				got := ""
+				if alert := tracker.observe(r); alert != nil {
+					got = "resolved"
+					if alert.Firing {
+						got = "firing"
+					}
+				}
+				if got != tt.want[i] {
+					t.Errorf("result %d: transition = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAlertTrackerSince(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := newAlertTracker(config.AlertConfig{Failures: 3})
+
+	var alert *Alert
+	for i := 0; i < 3; i++ {
+		alert = tracker.observe(probe.ProbeResult{Target: "A", Timestamp: start.Add(time.Duration(i) * time.Minute)})
+	}
+	if alert == nil || !alert.Firing {
+		t.Fatalf("alert did not fire: %+v", alert)
+	}
+	if !alert.Since.Equal(start) {
+		t.Errorf("Since = %v, want first failure at %v", alert.Since, start)
+	}
+
+	tracker.rename("A", "B")
+	if firing := tracker.firing(); len(firing) != 1 || firing[0].Target != "B" {
+		t.Errorf("firing() after rename = %+v", firing)
+	}
+}
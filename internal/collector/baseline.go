@@ -0,0 +1,494 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Defaults for zero anomaly settings
+const (
+	defaultAnomalySensitivity  = 5
+	defaultAnomalyMinDeviation = 20
+	defaultAnomalyLossMargin   = 10
+	defaultAnomalyConsecutive  = 3
+	defaultAnomalyWindow       = 7 * 24 * time.Hour
+	defaultAnomalyMinSamples   = 30
+)
+
+const (
+	// madScale makes the median absolute deviation comparable to a standard
+	// deviation, so the sensitivity reads like a z-score
+	madScale = 1.4826
+
+	// minMADMs keeps perfectly stable targets from flagging every wobble
+	minMADMs = 0.01
+
+	// dayProfileEvery is how many new minutes the whole-day profile waits for
+	// before it is recomputed
+	dayProfileEvery = 60
+)
+
+// AnomalyKind describes how a target deviates from its baseline
+type AnomalyKind string
+
+// Anomaly kinds
+const (
+	AnomalyLatencyHigh AnomalyKind = "latency_high"
+	AnomalyLatencyLow  AnomalyKind = "latency_low"
+	AnomalyLoss        AnomalyKind = "loss"
+)
+
+// Anomaly is the anomaly state of a target
+type Anomaly struct {
+	Target    string      `json:"target"`
+	Active    bool        `json:"active"`
+	Kind      AnomalyKind `json:"kind,omitempty"` // Of the probe that started the anomaly
+	Since     time.Time   `json:"since"`          // Start of the current state
+	LatencyMs float64     `json:"latency_ms"`     // Of the probe that caused the transition
+	LossPct   float64     `json:"loss_pct"`       // Of the probe that caused the transition
+	Score     float64     `json:"score"`          // Latency deviation in scaled MADs
+	Baseline  Profile     `json:"baseline"`       // Profile the probe was compared with
+}
+
+// Profile is a target's normal latency and loss in one hour of the day
+type Profile struct {
+	Hour     int     `json:"hour"`      // Local hour of day, -1 for the whole day
+	MedianMs float64 `json:"median_ms"` // Median of per-minute median latencies
+	MADMs    float64 `json:"mad_ms"`    // Median absolute deviation from it
+	LossPct  float64 `json:"loss_pct"`  // Average loss
+	Samples  int     `json:"samples"`   // Minutes of data
+}
+
+// Baseline is what a target has learned about its normal behavior
+type Baseline struct {
+	Target  string    `json:"target"`
+	Day     Profile   `json:"day"`     // Whole day, used while an hour has too little data
+	Hours   []Profile `json:"hours"`   // By local hour of day
+	Anomaly *Anomaly  `json:"anomaly"` // Active anomaly, if any
+}
+
+// minuteSamples is a ring of per-minute values, oldest first once full
+type minuteSamples struct {
+	values []float64
+	next   int
+}
+
+// add appends a value, replacing the oldest one when the ring is full
+func (m *minuteSamples) add(v float64, capacity int) {
+	if len(m.values) < capacity {
+		m.values = append(m.values, v)
+		return
+	}
+	m.values[m.next] = v
+	m.next = (m.next + 1) % capacity
+}
+
+// baselineHour holds the minutes learned for one hour of the day
+type baselineHour struct {
+	latency minuteSamples // Median latency of minutes with a reply
+	loss    minuteSamples // Loss ratio of every minute
+	profile Profile
+}
+
+// targetBaseline is the baseline and anomaly state of one target
+type targetBaseline struct {
+	hours      [24]baselineHour
+	day        Profile
+	sinceDay   int // Minutes added since day was computed
+	minute     time.Time
+	minuteLat  []float64 // Latencies of the current minute
+	minuteLoss []float64 // Loss ratios of the current minute
+
+	anomaly     Anomaly
+	streak      int       // Consecutive probes disagreeing with the current state
+	streakStart time.Time // Timestamp of the first of them
+}
+
+// anomalyTracker learns a baseline per target and flags deviations from it
+type anomalyTracker struct {
+	sensitivity  float64
+	minDeviation float64 // Percent of the median
+	lossMargin   float64 // Percentage points
+	consecutive  int
+	window       time.Duration
+	minSamples   int
+	capacity     int // Minutes kept per hour of day
+
+	targets map[string]*targetBaseline
+	mu      sync.Mutex
+}
+
+// newAnomalyTracker creates a tracker, filling in defaults for zero settings
+func newAnomalyTracker(cfg config.AnomalyConfig) *anomalyTracker {
+	t := &anomalyTracker{
+		sensitivity:  cfg.Sensitivity,
+		minDeviation: cfg.MinDeviation,
+		lossMargin:   cfg.LossMargin,
+		consecutive:  cfg.Consecutive,
+		window:       cfg.Window,
+		minSamples:   cfg.MinSamples,
+		targets:      make(map[string]*targetBaseline),
+	}
+	if t.sensitivity <= 0 {
+		t.sensitivity = defaultAnomalySensitivity
+	}
+	if t.minDeviation <= 0 {
+		t.minDeviation = defaultAnomalyMinDeviation
+	}
+	if t.lossMargin <= 0 {
+		t.lossMargin = defaultAnomalyLossMargin
+	}
+	if t.consecutive <= 0 {
+		t.consecutive = defaultAnomalyConsecutive
+	}
+	if t.window <= 0 {
+		t.window = defaultAnomalyWindow
+	}
+	if t.minSamples <= 0 {
+		t.minSamples = defaultAnomalyMinSamples
+	}
+	// Each hour of the day gets 60 minutes per day of the window
+	t.capacity = max(60, int(t.window.Minutes()/24))
+	return t
+}
+
+// target returns a target's state, creating it on first use
+func (t *anomalyTracker) target(name string) *targetBaseline {
+	tb, ok := t.targets[name]
+	if !ok {
+		tb = &targetBaseline{day: Profile{Hour: -1}}
+		for h := range tb.hours {
+			tb.hours[h].profile.Hour = h
+		}
+		t.targets[name] = tb
+	}
+	return tb
+}
+
+// seed learns from stored history, oldest first. Points may be aggregated
+// (loss as a ratio); each is counted as one minute.
+func (t *anomalyTracker) seed(name string, points []storage.DataPoint) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tb := t.target(name)
+	touched := make(map[int]bool)
+	for _, p := range points {
+		if math.IsNaN(p.Loss) {
+			continue // No data
+		}
+		h := &tb.hours[p.Timestamp.Local().Hour()]
+		if !math.IsNaN(p.Value) {
+			h.latency.add(p.Value, t.capacity)
+		}
+		h.loss.add(p.Loss, t.capacity)
+		touched[p.Timestamp.Local().Hour()] = true
+	}
+	for hour := range touched {
+		tb.hours[hour].profile = hourProfile(hour, &tb.hours[hour])
+	}
+	tb.day = dayProfile(tb)
+	tb.sinceDay = 0
+}
+
+// observe compares a probe result with the target's baseline, then learns
+// from it. It returns the new anomaly state when the result makes an anomaly
+// start or end, or nil otherwise.
+func (t *anomalyTracker) observe(r probe.ProbeResult) *Anomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tb := t.target(r.Target)
+	defer t.learn(tb, r)
+
+	if tb.anomaly.Target == "" {
+		tb.anomaly = Anomaly{Target: r.Target, Since: r.Timestamp}
+	}
+
+	profile, ok := t.profile(tb, r.Timestamp)
+	if !ok {
+		tb.streak = 0 // Still learning
+		return nil
+	}
+
+	kind, score := t.classify(r, profile)
+	if (kind != "") == tb.anomaly.Active {
+		tb.streak = 0
+		return nil
+	}
+	if tb.streak == 0 {
+		tb.streakStart = r.Timestamp
+	}
+	tb.streak++
+	if tb.streak < t.consecutive {
+		return nil
+	}
+
+	tb.anomaly = Anomaly{
+		Target:    r.Target,
+		Active:    kind != "",
+		Kind:      kind,
+		Since:     tb.streakStart,
+		LatencyMs: r.LatencyMs,
+		LossPct:   r.LossPct,
+		Score:     score,
+		Baseline:  profile,
+	}
+	tb.streak = 0
+
+	anomaly := tb.anomaly
+	return &anomaly
+}
+
+// profile returns the baseline for a time: its hour of day if that has
+// enough data, else the whole day
+func (t *anomalyTracker) profile(tb *targetBaseline, ts time.Time) (Profile, bool) {
+	if p := tb.hours[ts.Local().Hour()].profile; p.Samples >= t.minSamples {
+		return p, true
+	}
+	if tb.day.Samples >= t.minSamples {
+		return tb.day, true
+	}
+	return Profile{}, false
+}
+
+// classify returns how a probe result deviates from a profile ("" if it does
+// not) and its latency score
+func (t *anomalyTracker) classify(r probe.ProbeResult, p Profile) (AnomalyKind, float64) {
+	lossPct := r.LossPct
+	if !r.Success {
+		lossPct = 100
+	}
+	if lossPct-p.LossPct >= t.lossMargin {
+		return AnomalyLoss, 0
+	}
+	if !r.Success || p.MedianMs == 0 {
+		return "", 0 // No latency, or none learned yet
+	}
+
+	deviation := r.LatencyMs - p.MedianMs
+	score := deviation / (madScale * math.Max(p.MADMs, minMADMs))
+	if math.Abs(score) < t.sensitivity || math.Abs(deviation) < p.MedianMs*t.minDeviation/100 {
+		return "", score
+	}
+	if deviation > 0 {
+		return AnomalyLatencyHigh, score
+	}
+	return AnomalyLatencyLow, score
+}
+
+// learn adds a probe result to the current minute, and the previous minute
+// to the baseline once a new one starts
+func (t *anomalyTracker) learn(tb *targetBaseline, r probe.ProbeResult) {
+	minute := r.Timestamp.Truncate(time.Minute)
+	if !minute.Equal(tb.minute) {
+		t.flushMinute(tb)
+		tb.minute = minute
+	}
+
+	lossPct := r.LossPct
+	if !r.Success {
+		lossPct = 100
+	} else {
+		tb.minuteLat = append(tb.minuteLat, r.LatencyMs)
+	}
+	tb.minuteLoss = append(tb.minuteLoss, lossPct/100)
+}
+
+// flushMinute moves the current minute into its hour of day
+func (t *anomalyTracker) flushMinute(tb *targetBaseline) {
+	if len(tb.minuteLoss) == 0 {
+		return
+	}
+
+	hour := tb.minute.Local().Hour()
+	h := &tb.hours[hour]
+	if len(tb.minuteLat) > 0 {
+		sort.Float64s(tb.minuteLat)
+		h.latency.add(median(tb.minuteLat), t.capacity)
+	}
+	h.loss.add(mean(tb.minuteLoss), t.capacity)
+	h.profile = hourProfile(hour, h)
+
+	tb.sinceDay++
+	if tb.sinceDay >= dayProfileEvery || tb.day.Samples < t.minSamples {
+		tb.day = dayProfile(tb)
+		tb.sinceDay = 0
+	}
+
+	tb.minuteLat = tb.minuteLat[:0]
+	tb.minuteLoss = tb.minuteLoss[:0]
+}
+
+// hourProfile computes the profile of one hour of the day
+func hourProfile(hour int, h *baselineHour) Profile {
+	p := robustProfile(h.latency.values, h.loss.values)
+	p.Hour = hour
+	return p
+}
+
+// dayProfile computes the profile of all hours together
+func dayProfile(tb *targetBaseline) Profile {
+	var latency, loss []float64
+	for i := range tb.hours {
+		latency = append(latency, tb.hours[i].latency.values...)
+		loss = append(loss, tb.hours[i].loss.values...)
+	}
+	p := robustProfile(latency, loss)
+	p.Hour = -1
+	return p
+}
+
+// robustProfile computes median, MAD and average loss
+func robustProfile(latency, loss []float64) Profile {
+	p := Profile{Samples: len(loss)}
+	if len(loss) > 0 {
+		p.LossPct = mean(loss) * 100
+	}
+	if len(latency) == 0 {
+		return p
+	}
+
+	sorted := append([]float64(nil), latency...)
+	sort.Float64s(sorted)
+	p.MedianMs = median(sorted)
+
+	deviations := make([]float64, len(sorted))
+	for i, v := range sorted {
+		deviations[i] = math.Abs(v - p.MedianMs)
+	}
+	sort.Float64s(deviations)
+	p.MADMs = median(deviations)
+	return p
+}
+
+// median returns the median of sorted values
+func median(sorted []float64) float64 {
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
+
+// mean returns the average of values
+func mean(values []float64) float64 {
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// active returns the active anomalies, sorted by target
+func (t *anomalyTracker) active() []Anomaly {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	anomalies := make([]Anomaly, 0)
+	for _, tb := range t.targets {
+		if tb.anomaly.Active {
+			anomalies = append(anomalies, tb.anomaly)
+		}
+	}
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].Target < anomalies[j].Target
+	})
+	return anomalies
+}
+
+// baseline returns what a target has learned, or nil if nothing yet
+func (t *anomalyTracker) baseline(name string) *Baseline {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	tb, ok := t.targets[name]
+	if !ok {
+		return nil
+	}
+	b := &Baseline{Target: name, Day: tb.day, Hours: make([]Profile, len(tb.hours))}
+	for i := range tb.hours {
+		b.Hours[i] = tb.hours[i].profile
+	}
+	if tb.anomaly.Active {
+		anomaly := tb.anomaly
+		b.Anomaly = &anomaly
+	}
+	return b
+}
+
+// rename moves a target's baseline to its new name
+func (t *anomalyTracker) rename(oldName, newName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tb, ok := t.targets[oldName]; ok {
+		tb.anomaly.Target = newName
+		t.targets[newName] = tb
+		delete(t.targets, oldName)
+	}
+}
+
+// describe summarizes an anomaly for logs
+func (a *Anomaly) describe() string {
+	switch a.Kind {
+	case AnomalyLoss:
+		return fmt.Sprintf("loss %.0f%% (baseline %.1f%%)", a.LossPct, a.Baseline.LossPct)
+	case AnomalyLatencyHigh, AnomalyLatencyLow:
+		return fmt.Sprintf("latency %.2fms (baseline %.2fms ± %.2fms, score %.1f)", a.LatencyMs, a.Baseline.MedianMs, a.Baseline.MADMs, a.Score)
+	}
+	return "back to baseline"
+}
+
+// Anomalies returns the active anomalies, sorted by target
+func (c *Collector) Anomalies() []Anomaly {
+	return c.anomalies.active()
+}
+
+// Anomaly returns a target's active anomaly, or nil
+func (c *Collector) Anomaly(targetName string) *Anomaly {
+	if b := c.anomalies.baseline(targetName); b != nil {
+		return b.Anomaly
+	}
+	return nil
+}
+
+// Baseline returns the baseline a target has learned, or nil if it has no
+// data yet
+func (c *Collector) Baseline(targetName string) *Baseline {
+	return c.anomalies.baseline(targetName)
+}
+
+// seedBaselines learns every target's baseline from stored history
+func (c *Collector) seedBaselines() {
+	if c.storage == nil {
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-c.anomalies.window)
+	seeded := 0
+	for _, t := range c.GetTargets() {
+		if c.ctx.Err() != nil {
+			return
+		}
+		points, err := c.FetchHistory(t.Name, from, to)
+		if err != nil {
+			log.Printf("[Collector] Failed to load baseline history for %s: %v", t.Name, err)
+			continue
+		}
+		if len(points) > 0 {
+			c.anomalies.seed(t.Name, points)
+			seeded++
+		}
+	}
+	log.Printf("[Collector] Learned baselines for %d target(s) from history", seeded)
+}
@@ -0,0 +1,163 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// stableHistory returns a day of one-minute points with latency alternating
+// around 20ms and no loss
+func stableHistory(end time.Time) []storage.DataPoint {
+	var points []storage.DataPoint
+	for i := 24 * 60; i > 0; i-- {
+		points = append(points, storage.DataPoint{
+			Timestamp: end.Add(-time.Duration(i) * time.Minute),
+			Value:     20 + float64(i%3-1),
+			Loss:      0,
+		})
+	}
+	return points
+}
+
+func TestAnomalyTracker(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	normal := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 20.5}
+	slow := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 80}
+	fast := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 5}
+	wobble := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 23} // Two MADs, but only 15% off
+	lossy := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 20, LossPct: 40}
+	down := probe.ProbeResult{Target: "A", Success: false, LatencyMs: -1, LossPct: 100}
+
+	tests := []struct {
+		name    string
+		cfg     config.AnomalyConfig
+		seed    bool
+		results []probe.ProbeResult
+		want    []string // Transition after each result: "" or the anomaly kind, "ended"
+	}{
+		{
+			name:    "learning without history",
+			cfg:     config.AnomalyConfig{Consecutive: 1},
+			results: []probe.ProbeResult{slow, slow, slow},
+			want:    []string{"", "", ""},
+		},
+		{
+			name:    "deviation under minimum",
+			cfg:     config.AnomalyConfig{Consecutive: 1, Sensitivity: 1},
+			seed:    true,
+			results: []probe.ProbeResult{normal, wobble},
+			want:    []string{"", ""},
+		},
+		{
+			name:    "high latency after consecutive probes",
+			cfg:     config.AnomalyConfig{Consecutive: 2},
+			seed:    true,
+			results: []probe.ProbeResult{slow, normal, slow, slow, slow},
+			want:    []string{"", "", "", "latency_high", ""},
+		},
+		{
+			name:    "low latency",
+			cfg:     config.AnomalyConfig{Consecutive: 1},
+			seed:    true,
+			results: []probe.ProbeResult{fast},
+			want:    []string{"latency_low"},
+		},
+		{
+			name:    "loss above margin",
+			cfg:     config.AnomalyConfig{Consecutive: 1, LossMargin: 30},
+			seed:    true,
+			results: []probe.ProbeResult{lossy, down},
+			want:    []string{"loss", ""},
+		},
+		{
+			name:    "ends after consecutive normal probes",
+			cfg:     config.AnomalyConfig{Consecutive: 2},
+			seed:    true,
+			results: []probe.ProbeResult{down, down, normal, down, normal, normal},
+			want:    []string{"", "loss", "", "", "", "ended"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker := newAnomalyTracker(tt.cfg)
+			if tt.seed {
+				tracker.seed("A", stableHistory(start))
+			}
+			for i, r := range tt.results {
+				r.Timestamp = start.Add(time.Duration(i) * 10 * time.Second)
+				got := ""
+				if anomaly := tracker.observe(r); anomaly != nil {
+					got = "ended"
+					if anomaly.Active {
+						got = string(anomaly.Kind)
+					}
+				}
+				if got != tt.want[i] {
+					t.Errorf("result %d: transition = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnomalyTrackerLearns(t *testing.T) {
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	tracker := newAnomalyTracker(config.AnomalyConfig{Consecutive: 1, MinSamples: 5})
+
+	// Six minutes of probes at 10ms make the hour's baseline
+	for i := 0; i < 36; i++ {
+		r := probe.ProbeResult{Target: "A", Success: true, LatencyMs: 10, Timestamp: start.Add(time.Duration(i) * 10 * time.Second)}
+		if anomaly := tracker.observe(r); anomaly != nil {
+			t.Fatalf("result %d: unexpected anomaly while learning: %+v", i, anomaly)
+		}
+	}
+
+	b := tracker.baseline("A")
+	if b == nil {
+		t.Fatal("no baseline")
+	}
+	hour := b.Hours[start.Hour()]
+	if hour.Samples != 5 || hour.MedianMs != 10 || hour.LossPct != 0 {
+		t.Errorf("hour profile = %+v, want 5 samples at 10ms without loss", hour)
+	}
+
+	anomaly := tracker.observe(probe.ProbeResult{Target: "A", Success: true, LatencyMs: 50, Timestamp: start.Add(6 * time.Minute)})
+	if anomaly == nil || anomaly.Kind != AnomalyLatencyHigh || anomaly.Baseline.Hour != start.Hour() {
+		t.Fatalf("anomaly = %+v, want latency_high against the hour profile", anomaly)
+	}
+	if anomaly.Score < 1000 {
+		t.Errorf("score = %v, want a large score against a flat baseline", anomaly.Score)
+	}
+
+	tracker.rename("A", "B")
+	if active := tracker.active(); len(active) != 1 || active[0].Target != "B" {
+		t.Errorf("active() after rename = %+v", active)
+	}
+}
+
+func TestRobustProfile(t *testing.T) {
+	tests := []struct {
+		name            string
+		latency, loss   []float64
+		median, mad, lp float64
+	}{
+		{"empty", nil, nil, 0, 0, 0},
+		{"odd", []float64{10, 12, 11, 50, 9}, []float64{0, 0, 0, 0, 0.5}, 11, 1, 10},
+		{"even", []float64{10, 20}, []float64{0, 1}, 15, 5, 50},
+		{"loss only", nil, []float64{1, 1}, 0, 0, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := robustProfile(tt.latency, tt.loss)
+			if p.MedianMs != tt.median || p.MADMs != tt.mad || p.LossPct != tt.lp || p.Samples != len(tt.loss) {
+				t.Errorf("profile = %+v, want median %v, MAD %v, loss %v", p, tt.median, tt.mad, tt.lp)
+			}
+		})
+	}
+}
@@ -2,26 +2,62 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log"
+	"math"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/wellsgz/pulse/internal/config"
 	"github.com/wellsgz/pulse/internal/logging"
+	"github.com/wellsgz/pulse/internal/paths"
 	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/report"
 	"github.com/wellsgz/pulse/internal/storage"
 )
 
+// Errors returned by target and history operations
+var (
+	ErrStorageUnavailable = errors.New("persistent storage not available")
+	ErrTargetNotFound     = errors.New("target not found")
+	ErrTargetExists       = errors.New("target already exists")
+	ErrAgentManaged       = errors.New("target is managed by agents")
+)
+
 // Collector manages probes and broadcasts results
 type Collector struct {
-	config  *config.Config
-	probes  map[string]probe.Probe
-	storage storage.Storage
-	memory  *storage.MemoryBuffer
+	config    *config.Config
+	probes    map[string]probe.Probe
+	storage   storage.Storage
+	memory    *storage.MemoryBuffer
+	reports   *report.Generator
+	alerts    *alertTracker
+	anomalies *anomalyTracker
+	shifts    *shiftTracker
+	incidents *storage.IncidentLog
+
+	maintenance *maintenanceTracker
+
+	// Held for reading during a probe cycle and for writing while renaming a
+	// target, so results are never stored under a stale name
+	targetsMu sync.RWMutex
+
+	// Series reported by remote agents (master mode)
+	agentSeries []config.Target
+	agents      map[string]*agentState
+	agentsMu    sync.Mutex
 
 	// Event broadcasting
-	subscribers map[chan probe.ProbeResult]struct{}
-	subMu       sync.RWMutex
+	subscribers      map[chan probe.ProbeResult]struct{}
+	eventSubscribers map[chan Event]struct{}
+	subMu            sync.RWMutex
+
+	// Held while a result is numbered, buffered and broadcast, so results
+	// reach subscribers in sequence order
+	seqMu   sync.Mutex
+	lastSeq uint64
 
 	// Lifecycle
 	ctx    context.Context
@@ -34,14 +70,26 @@ func NewCollector(cfg *config.Config, store storage.Storage, mem *storage.Memory
 	ctx, cancel := context.WithCancel(context.Background())
 
 	c := &Collector{
-		config:      cfg,
-		probes:      make(map[string]probe.Probe),
-		storage:     store,
-		memory:      mem,
-		subscribers: make(map[chan probe.ProbeResult]struct{}),
-		ctx:         ctx,
-		cancel:      cancel,
+		config:           cfg,
+		probes:           make(map[string]probe.Probe),
+		storage:          store,
+		memory:           mem,
+		alerts:           newAlertTracker(cfg.Alerts),
+		anomalies:        newAnomalyTracker(cfg.Anomaly),
+		shifts:           newShiftTracker(cfg.Shifts),
+		incidents:        openIncidentLog(cfg, store != nil),
+		maintenance:      newMaintenanceTracker(cfg, append(slices.Clone(cfg.Targets), cfg.AgentSeries()...), store != nil),
+		subscribers:      make(map[chan probe.ProbeResult]struct{}),
+		eventSubscribers: make(map[chan Event]struct{}),
+		agentSeries:      cfg.AgentSeries(),
+		agents:           make(map[string]*agentState),
+		ctx:              ctx,
+		cancel:           cancel,
 	}
+	for _, agent := range cfg.Master.Agents {
+		c.agents[agent] = &agentState{last: make(map[string]time.Time)}
+	}
+	c.alerts.setParents(append(slices.Clone(cfg.Targets), c.agentSeries...))
 
 	// Create probes for each target
 	for _, target := range cfg.Targets {
@@ -51,6 +99,9 @@ func NewCollector(cfg *config.Config, store storage.Storage, mem *storage.Memory
 			p = probe.NewICMPProbe(target.Name, target.Host, cfg.Global.Timeout, cfg.Global.Pings)
 		case "tcp":
 			p = probe.NewTCPProbe(target.Name, target.Host, target.Port, cfg.Global.Timeout, cfg.Global.Pings)
+		case config.ProbeComposite:
+			log.Printf("[Collector] Composite target %s: %s", target.Name, target.Endpoint())
+			continue
 		default:
 			log.Printf("[Collector] Unknown probe type %q for target %q, skipping", target.Probe, target.Name)
 			continue
@@ -59,12 +110,15 @@ func NewCollector(cfg *config.Config, store storage.Storage, mem *storage.Memory
 		log.Printf("[Collector] Created %s probe for %s (%s) with %d pings/interval", target.Probe, target.Name, target.Host, cfg.Global.Pings)
 	}
 
+	c.reports = report.NewGenerator(cfg, c)
+
 	return c
 }
 
 // Start begins collecting probe data
 func (c *Collector) Start() {
 	log.Printf("[Collector] Starting collection with interval %s", c.config.Global.Interval)
+	c.recordStartup()
 
 	// Short delay to allow ICMP socket infrastructure to initialize
 	// This prevents the first probe from failing due to socket contention
@@ -90,6 +144,67 @@ func (c *Collector) Start() {
 			}
 		}
 	}()
+
+	// Upgrade data files written with a different step or retention
+	if m, ok := c.storage.(storage.Migrator); ok {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			m.Migrate(c.ctx)
+		}()
+	}
+
+	// Learn baselines from history while new results come in
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		c.seedBaselines()
+	}()
+
+	// Search recent history for latency steps
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(c.shifts.interval)
+		defer ticker.Stop()
+
+		c.checkShifts()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkShifts()
+			}
+		}
+	}()
+
+	// Suppress alerts of targets in maintenance windows and silences
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		ticker := time.NewTicker(maintenanceInterval)
+		defer ticker.Stop()
+
+		c.checkMaintenance()
+		for {
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				c.checkMaintenance()
+			}
+		}
+	}()
+
+	// Start scheduled report generation (no-op without a schedule)
+	if c.reports.Schedule() != "" {
+		c.wg.Add(1)
+		go func() {
+			defer c.wg.Done()
+			c.reports.Run(c.ctx)
+		}()
+	}
 }
 
 // Stop stops the collector and waits for goroutines to finish
@@ -103,6 +218,10 @@ func (c *Collector) Stop() {
 		close(ch)
 		delete(c.subscribers, ch)
 	}
+	for ch := range c.eventSubscribers {
+		close(ch)
+		delete(c.eventSubscribers, ch)
+	}
 	c.subMu.Unlock()
 
 	log.Println("[Collector] Stopped")
@@ -134,6 +253,11 @@ func (c *Collector) Unsubscribe(ch <-chan probe.ProbeResult) {
 	}
 }
 
+// Interval returns the probe interval
+func (c *Collector) Interval() time.Duration {
+	return c.config.Global.Interval
+}
+
 // GetStats returns current statistics for a target
 func (c *Collector) GetStats(targetName string) *storage.Stats {
 	return c.memory.GetStats(targetName)
@@ -149,9 +273,180 @@ func (c *Collector) GetHistory(targetName string, count int) []float64 {
 	return c.memory.GetHistory(targetName, count)
 }
 
-// GetTargets returns all target configurations
+// GetTargets returns all target configurations, followed by the series
+// reported by agents
 func (c *Collector) GetTargets() []config.Target {
-	return c.config.Targets
+	c.targetsMu.RLock()
+	defer c.targetsMu.RUnlock()
+	return c.allTargetsLocked()
+}
+
+// allTargetsLocked returns a copy of the configured targets and agent series.
+// Must be called with targetsMu held
+func (c *Collector) allTargetsLocked() []config.Target {
+	targets := make([]config.Target, 0, len(c.config.Targets)+len(c.agentSeries))
+	targets = append(targets, c.config.Targets...)
+	return append(targets, c.agentSeries...)
+}
+
+// storageKey returns the storage id for a target name. Unknown names (e.g.
+// orphaned history) are mapped the same way as targets without an explicit id.
+func (c *Collector) storageKey(targetName string) string {
+	c.targetsMu.RLock()
+	defer c.targetsMu.RUnlock()
+	return c.storageKeyLocked(targetName)
+}
+
+// storageKeyLocked is storageKey for callers already holding targetsMu
+func (c *Collector) storageKeyLocked(targetName string) string {
+	for _, t := range c.allTargetsLocked() {
+		if t.Name == targetName {
+			return t.StorageKey()
+		}
+	}
+	return config.Target{Name: targetName}.StorageKey()
+}
+
+// RenameTarget changes a target's name at runtime. The target keeps its
+// storage id, pinned as an explicit id if it was derived from the old name, so
+// its history stays in place: the config file is not modified, and still maps
+// the old name to the same file after a restart.
+func (c *Collector) RenameTarget(oldName, newName string) error {
+	if newName == "" {
+		return fmt.Errorf("new name is required")
+	}
+
+	c.targetsMu.Lock()
+	defer c.targetsMu.Unlock()
+
+	for _, t := range c.agentSeries {
+		switch t.Name {
+		case oldName:
+			return fmt.Errorf("%w: %q is reported by agent %s and cannot be renamed", ErrAgentManaged, oldName, t.Agent)
+		case newName:
+			return fmt.Errorf("%w: %s", ErrTargetExists, newName)
+		}
+	}
+
+	idx := -1
+	for i, t := range c.config.Targets {
+		switch t.Name {
+		case oldName:
+			idx = i
+		case newName:
+			return fmt.Errorf("%w: %s", ErrTargetExists, newName)
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("%w: %s", ErrTargetNotFound, oldName)
+	}
+
+	target := c.config.Targets[idx]
+	if len(target.Agents) > 0 {
+		return fmt.Errorf("%w: rename %q in the config file instead", ErrAgentManaged, oldName)
+	}
+	target.ID = target.StorageKey()
+	target.Name = newName
+
+	c.config.Targets[idx] = target
+	for i, t := range c.config.Targets {
+		// Composites and dependent targets follow the renamed target
+		if j := slices.Index(t.Members, oldName); j >= 0 {
+			members := slices.Clone(t.Members)
+			members[j] = newName
+			c.config.Targets[i].Members = members
+		}
+		if t.DependsOn == oldName {
+			c.config.Targets[i].DependsOn = newName
+		}
+	}
+	if p, exists := c.probes[oldName]; exists {
+		delete(c.probes, oldName)
+		c.probes[newName] = p
+	}
+	c.memory.Rename(oldName, newName)
+	c.alerts.rename(oldName, newName)
+	c.anomalies.rename(oldName, newName)
+	c.shifts.rename(oldName, newName)
+	c.maintenance.rename(oldName, newName)
+
+	log.Printf("[Collector] Renamed target %s to %s (storage id %s)", oldName, newName, target.ID)
+	c.publish(Event{Type: EventTargetRenamed, Target: newName, OldName: oldName, Timestamp: time.Now()})
+	return nil
+}
+
+// MigrationStatus returns the storage schema migration progress, or nil if the
+// storage backend does not migrate files
+func (c *Collector) MigrationStatus() *storage.MigrationStatus {
+	m, ok := c.storage.(storage.Migrator)
+	if !ok {
+		return nil
+	}
+	status := m.MigrationStatus()
+	return &status
+}
+
+// StorageUsage returns disk usage per data file, marking files that do not
+// belong to any configured target as orphans
+func (c *Collector) StorageUsage() (*storage.Usage, error) {
+	if c.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+	usage, err := c.storage.Usage()
+	if err != nil {
+		return nil, err
+	}
+
+	c.targetsMu.RLock()
+	owners := make(map[string]string, len(c.config.Targets)) // storage id -> target name
+	for _, t := range c.allTargetsLocked() {
+		owners[t.StorageKey()] = t.Name
+	}
+	c.targetsMu.RUnlock()
+
+	for i := range usage.Files {
+		f := &usage.Files[i]
+		f.Target = owners[f.ID]
+		f.Orphan = f.Target == ""
+	}
+	return usage, nil
+}
+
+// RemoveOrphan deletes or archives a data file that is not linked to any
+// configured target. It returns the archive path when archiving.
+func (c *Collector) RemoveOrphan(id string, archive bool) (string, error) {
+	if c.storage == nil {
+		return "", ErrStorageUnavailable
+	}
+
+	if id != paths.SanitizeFilename(id) {
+		return "", fmt.Errorf("invalid storage id: %s", id)
+	}
+
+	c.targetsMu.RLock()
+	defer c.targetsMu.RUnlock()
+
+	for _, t := range c.allTargetsLocked() {
+		if t.StorageKey() == id {
+			return "", fmt.Errorf("%s is the history of configured target %q", id, t.Name)
+		}
+	}
+
+	dest, err := c.storage.Remove(id, archive)
+	if err != nil {
+		return "", err
+	}
+	if archive {
+		log.Printf("[Collector] Archived orphaned history %s to %s", id, dest)
+	} else {
+		log.Printf("[Collector] Deleted orphaned history %s", id)
+	}
+	return dest, nil
+}
+
+// Reports returns the SLA report generator
+func (c *Collector) Reports() *report.Generator {
+	return c.reports
 }
 
 // FetchHistory retrieves historical data from persistent storage
@@ -159,45 +454,200 @@ func (c *Collector) FetchHistory(targetName string, from, to time.Time) ([]stora
 	if c.storage == nil {
 		return []storage.DataPoint{}, nil
 	}
-	return c.storage.Fetch(targetName, from, to)
+	return c.storage.Fetch(c.storageKey(targetName), from, to)
+}
+
+// RecentResults returns a target's results newer than since and recorded after
+// the result numbered after, oldest first.
+// They come from the memory buffer, preceded by persistent history for any
+// part of the window the buffer no longer holds. With count > 0 only the
+// newest count buffered results are returned.
+func (c *Collector) RecentResults(targetName string, since time.Time, after uint64, count int) ([]storage.DataPoint, error) {
+	points := c.memory.Samples(targetName, since, after, count)
+	if count > 0 || c.storage == nil {
+		return points, nil
+	}
+	if after > 0 {
+		if c.memory.Holds(targetName, after) {
+			return points, nil
+		}
+		// Sequence numbers are recording times, and a result recorded after
+		// another started at most an interval before it
+		if from := time.Unix(0, int64(after)).Add(-c.config.Global.Interval); from.After(since) {
+			since = from
+		}
+	}
+	if since.IsZero() {
+		return points, nil
+	}
+
+	// The buffer covers the window if it reaches back to since
+	until := time.Now()
+	if len(points) > 0 {
+		until = points[0].Timestamp
+	}
+	if until.Sub(since) <= c.config.Global.Interval {
+		return points, nil
+	}
+
+	stored, err := c.storage.Fetch(c.storageKey(targetName), since, until)
+	if err != nil {
+		return points, err
+	}
+	older := make([]storage.DataPoint, 0, len(stored)+len(points))
+	for _, p := range stored {
+		if !math.IsNaN(p.Loss) && p.Timestamp.After(since) && p.Timestamp.Before(until) {
+			older = append(older, p)
+		}
+	}
+	return append(older, points...), nil
+}
+
+// BufferedResults returns a target's results recorded after the result
+// numbered after that are still in the memory buffer, oldest first
+func (c *Collector) BufferedResults(targetName string, after uint64) []storage.DataPoint {
+	return c.memory.Samples(targetName, time.Time{}, after, 0)
+}
+
+// LastSeq returns the sequence number of the newest recorded result. Every
+// result up to it has been broadcast.
+func (c *Collector) LastSeq() uint64 {
+	c.seqMu.Lock()
+	defer c.seqMu.Unlock()
+	return c.lastSeq
+}
+
+// ExportHistory returns a target's complete multi-resolution history
+func (c *Collector) ExportHistory(targetName string) (*storage.Archive, error) {
+	if c.storage == nil {
+		return nil, ErrStorageUnavailable
+	}
+	archive, err := c.storage.Export(c.storageKey(targetName))
+	if err != nil {
+		return nil, err
+	}
+	archive.Target = targetName
+	return archive, nil
+}
+
+// ImportHistory writes an archive into a target's persistent history
+func (c *Collector) ImportHistory(targetName string, archive *storage.Archive, mode storage.ImportMode) error {
+	if c.storage == nil {
+		return ErrStorageUnavailable
+	}
+	if err := c.storage.Import(c.storageKey(targetName), archive, mode); err != nil {
+		return err
+	}
+	log.Printf("[Collector] Imported history for %s (mode=%s)", targetName, mode)
+	return nil
 }
 
 // runAllProbes executes all probes concurrently
 func (c *Collector) runAllProbes() {
+	// Probes run without targetsMu, so a slow round does not hold up renames
+	c.targetsMu.RLock()
+	probes := make([]probe.Probe, 0, len(c.probes))
+	for _, p := range c.probes {
+		probes = append(probes, p)
+	}
+	c.targetsMu.RUnlock()
+
 	var wg sync.WaitGroup
+	var resultsMu sync.Mutex
+	results := make(map[string]probe.ProbeResult, len(probes))
 
-	for _, p := range c.probes {
+	for _, p := range probes {
 		wg.Add(1)
 		go func(p probe.Probe) {
 			defer wg.Done()
-			c.runProbe(p)
+			result, ok := c.runProbe(p)
+			if !ok {
+				return
+			}
+
+			resultsMu.Lock()
+			results[result.Target] = result
+			resultsMu.Unlock()
 		}(p)
 	}
 
 	wg.Wait()
+
+	// Composite targets are derived from the round's results
+	c.targetsMu.RLock()
+	c.recordComposites(results)
+	c.targetsMu.RUnlock()
 }
 
-// runProbe executes a single probe and handles the result
-func (c *Collector) runProbe(p probe.Probe) {
+// runProbe executes a single probe, handles the result and returns it. The
+// target may have been renamed while the probe ran, so its name and storage
+// id are looked up once the result is in; ok is false if it is gone.
+func (c *Collector) runProbe(p probe.Probe) (result probe.ProbeResult, ok bool) {
 	// Create a context with timeout for this probe
 	ctx, cancel := context.WithTimeout(c.ctx, c.config.Global.Timeout)
 	defer cancel()
 
-	result := p.Execute(ctx)
+	result = p.Execute(ctx)
+
+	c.targetsMu.RLock()
+	defer c.targetsMu.RUnlock()
+
+	name, ok := c.probeNameLocked(p)
+	if !ok {
+		return result, false
+	}
+	result.Target = name // The probe keeps its original name after a rename
+
+	c.record(c.storageKeyLocked(name), result)
+	return result, true
+}
+
+// probeNameLocked returns the name of the target a probe runs for.
+// Must be called with targetsMu held
+func (c *Collector) probeNameLocked(p probe.Probe) (string, bool) {
+	for name, q := range c.probes {
+		if q == p {
+			return name, true
+		}
+	}
+	return "", false
+}
 
-	// Store in memory buffer
-	c.memory.Write(result.Target, result.Timestamp, result.LatencyMs)
+// record stores a probe result and broadcasts it to subscribers
+func (c *Collector) record(key string, result probe.ProbeResult) {
+	// Number the result, store it in the memory buffer and broadcast it.
+	// Numbers are the recording time in nanoseconds, kept increasing, so they
+	// still increase after a restart.
+	c.seqMu.Lock()
+	result.Seq = max(uint64(time.Now().UnixNano()), c.lastSeq+1)
+	c.lastSeq = result.Seq
+	c.memory.Write(result.Target, result.Seq, result.Timestamp, result.LatencyMs)
+	c.broadcast(result)
+	c.seqMu.Unlock()
 
 	// Store in persistent storage
 	if c.storage != nil {
 		isLoss := !result.Success
-		if err := c.storage.Write(result.Target, result.Timestamp, result.LatencyMs, isLoss); err != nil {
+		if err := c.storage.Write(key, result.Timestamp, result.LatencyMs, isLoss); err != nil {
 			log.Printf("[Collector] Failed to write to storage for %s: %v", result.Target, err)
 		}
 	}
 
-	// Broadcast to subscribers
-	c.broadcast(result)
+	if alert := c.alerts.observe(result); alert != nil {
+		c.notifyAlert(alert, result.Timestamp)
+	}
+	c.releaseUnreachable(result.Target, result.Timestamp)
+
+	if anomaly := c.anomalies.observe(result); anomaly != nil {
+		eventType := EventAnomalyEnded
+		if anomaly.Active {
+			eventType = EventAnomalyStarted
+			log.Printf("[Collector] Anomaly on %s: %s", anomaly.Target, anomaly.describe())
+		} else {
+			log.Printf("[Collector] Anomaly on %s ended", anomaly.Target)
+		}
+		c.publish(Event{Type: eventType, Target: anomaly.Target, Timestamp: result.Timestamp, Anomaly: anomaly})
+	}
 
 	// Log result using structured logging
 	logging.ProbeResult(result.Target, result.LatencyMs, result.Success, result.Error)
@@ -0,0 +1,108 @@
+package collector
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// blockingProbe succeeds once released
+type blockingProbe struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingProbe) Name() string { return "google" }
+func (p *blockingProbe) Host() string { return "8.8.8.8" }
+func (p *blockingProbe) Type() string { return "icmp" }
+
+func (p *blockingProbe) Execute(ctx context.Context) probe.ProbeResult {
+	close(p.started)
+	<-p.release
+	return probe.ProbeResult{Target: p.Name(), Timestamp: time.Now(), Success: true, LatencyMs: 8}
+}
+
+func TestRenameDuringProbeRound(t *testing.T) {
+	cfg := &config.Config{
+		Targets: []config.Target{{Name: "google", Host: "8.8.8.8", Probe: "icmp"}},
+	}
+	cfg.Global.Timeout = time.Second
+	c := NewCollector(cfg, nil, storage.NewMemoryBuffer(10))
+	p := &blockingProbe{started: make(chan struct{}), release: make(chan struct{})}
+	c.probes["google"] = p
+
+	round := make(chan struct{})
+	go func() {
+		c.runAllProbes()
+		close(round)
+	}()
+	<-p.started
+
+	renamed := make(chan error)
+	go func() { renamed <- c.RenameTarget("google", "google-dns") }()
+	select {
+	case err := <-renamed:
+		if err != nil {
+			t.Fatalf("RenameTarget() error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("RenameTarget() blocked by a running probe")
+	}
+
+	close(p.release)
+	<-round
+
+	if stats := c.GetStats("google-dns"); stats == nil || stats.SampleCount != 1 {
+		t.Errorf("GetStats(google-dns) = %+v, want the round's sample", stats)
+	}
+	if stats := c.GetStats("google"); stats != nil && stats.SampleCount > 0 {
+		t.Errorf("GetStats(google) = %+v, want none after the rename", stats)
+	}
+}
+
+// keyRecorder is a storage that records the ids written to
+type keyRecorder struct {
+	storage.Storage
+	keys map[string]bool
+}
+
+func (s *keyRecorder) Write(targetID string, timestamp time.Time, latencyMs float64, isLoss bool) error {
+	s.keys[targetID] = true
+	return nil
+}
+
+func TestRenameKeepsHistoryAcrossRestart(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	yaml := "global:\n  data_dir: " + dir + "\ntargets:\n  - name: Google DNS\n    host: 8.8.8.8\n    probe: icmp\n"
+	if err := os.WriteFile(path, []byte(yaml), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	store := &keyRecorder{keys: make(map[string]bool)}
+	c := NewCollector(cfg, store, storage.NewMemoryBuffer(10))
+	if err := c.RenameTarget("Google DNS", "google-primary"); err != nil {
+		t.Fatalf("RenameTarget() error = %v", err)
+	}
+	c.record(c.storageKey("google-primary"), probe.ProbeResult{Target: "google-primary", Timestamp: time.Now(), Success: true, LatencyMs: 8})
+
+	// The config file still has the old name, which must map to the same history
+	reloaded, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	restarted := NewCollector(reloaded, store, storage.NewMemoryBuffer(10))
+	if key := restarted.storageKey("Google DNS"); len(store.keys) != 1 || !store.keys[key] {
+		t.Errorf("history written to %v, want %q after a restart", store.keys, key)
+	}
+}
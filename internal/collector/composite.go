@@ -0,0 +1,93 @@
+package collector
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+// compositeResult derives a composite target's result from its members'
+// results of one probe round. Members without a result count as not
+// responding; it returns false if none of them has one.
+//
+// The pings of the result are the members: sent is the number of members,
+// received the number that responded, and the loss the share that did not.
+// Latency is the median of the responding members, or the lowest or highest
+// in best and worst mode.
+func compositeResult(t config.Target, results map[string]probe.ProbeResult) (probe.ProbeResult, bool) {
+	var latencies []float64
+	var last time.Time
+	found := false
+	for _, m := range t.Members {
+		r, ok := results[m]
+		if !ok {
+			continue
+		}
+		found = true
+		if r.Timestamp.After(last) {
+			last = r.Timestamp
+		}
+		if r.Success && r.LatencyMs >= 0 {
+			latencies = append(latencies, r.LatencyMs)
+		}
+	}
+	if !found {
+		return probe.ProbeResult{}, false
+	}
+
+	total, up := len(t.Members), len(latencies)
+	needed := 1
+	switch t.CompositeMode() {
+	case config.CompositeAll, config.CompositeWorst:
+		needed = total
+	case config.CompositeQuorum:
+		needed = t.Quorum
+	}
+
+	result := probe.ProbeResult{
+		Target:    t.Name,
+		Timestamp: last,
+		LatencyMs: -1,
+		LossPct:   100 * float64(total-up) / float64(total),
+		PingsSent: total,
+		PingsRecv: up,
+		RTTsMs:    latencies,
+	}
+	if up < needed {
+		result.Error = fmt.Sprintf("%d of %d members responding, %d needed", up, total, needed)
+		return result, true
+	}
+
+	sort.Float64s(latencies)
+	result.Success = true
+	result.MinMs = latencies[0]
+	result.MaxMs = latencies[len(latencies)-1]
+	result.AvgMs = mean(latencies)
+	switch t.CompositeMode() {
+	case config.CompositeBest:
+		result.LatencyMs = result.MinMs
+	case config.CompositeWorst:
+		result.LatencyMs = result.MaxMs
+	default:
+		result.LatencyMs = median(latencies)
+	}
+	result.Latency = time.Duration(result.LatencyMs * float64(time.Millisecond))
+	return result, true
+}
+
+// recordComposites derives and records the results of composite targets
+// from the member results of a probe round. Must be called with targetsMu
+// held.
+func (c *Collector) recordComposites(results map[string]probe.ProbeResult) {
+	for _, t := range c.config.Targets {
+		if !t.IsComposite() {
+			continue
+		}
+		if result, ok := compositeResult(t, results); ok {
+			c.record(t.StorageKey(), result)
+		}
+	}
+}
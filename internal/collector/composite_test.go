@@ -0,0 +1,149 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestCompositeResult(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	up := func(target string, ms float64) probe.ProbeResult {
+		return probe.ProbeResult{Target: target, Timestamp: at, Success: true, LatencyMs: ms}
+	}
+	down := func(target string) probe.ProbeResult {
+		return probe.ProbeResult{Target: target, Timestamp: at, LatencyMs: -1, LossPct: 100}
+	}
+	members := []string{"google", "cloudflare", "quad9"}
+
+	tests := []struct {
+		name        string
+		mode        string
+		quorum      int
+		results     []probe.ProbeResult
+		wantSuccess bool
+		wantMs      float64
+		wantLoss    float64
+	}{
+		{
+			name:        "any with one member up",
+			results:     []probe.ProbeResult{down("google"), up("cloudflare", 12), down("quad9")},
+			wantSuccess: true,
+			wantMs:      12,
+			wantLoss:    100 * 2.0 / 3,
+		},
+		{
+			name:        "any with every member down",
+			results:     []probe.ProbeResult{down("google"), down("cloudflare"), down("quad9")},
+			wantSuccess: false,
+			wantMs:      -1,
+			wantLoss:    100,
+		},
+		{
+			name:        "all with one member down",
+			mode:        config.CompositeAll,
+			results:     []probe.ProbeResult{up("google", 10), up("cloudflare", 12), down("quad9")},
+			wantSuccess: false,
+			wantMs:      -1,
+			wantLoss:    100.0 / 3,
+		},
+		{
+			name:        "quorum met",
+			mode:        config.CompositeQuorum,
+			quorum:      2,
+			results:     []probe.ProbeResult{up("google", 10), up("cloudflare", 20), down("quad9")},
+			wantSuccess: true,
+			wantMs:      15,
+			wantLoss:    100.0 / 3,
+		},
+		{
+			name:        "quorum missed",
+			mode:        config.CompositeQuorum,
+			quorum:      2,
+			results:     []probe.ProbeResult{up("google", 10), down("cloudflare"), down("quad9")},
+			wantSuccess: false,
+			wantMs:      -1,
+			wantLoss:    100 * 2.0 / 3,
+		},
+		{
+			name:        "missing member counts as down",
+			mode:        config.CompositeQuorum,
+			quorum:      3,
+			results:     []probe.ProbeResult{up("google", 10), up("cloudflare", 20)},
+			wantSuccess: false,
+			wantMs:      -1,
+			wantLoss:    100.0 / 3,
+		},
+		{
+			name:        "best latency",
+			mode:        config.CompositeBest,
+			results:     []probe.ProbeResult{up("google", 10), up("cloudflare", 5), down("quad9")},
+			wantSuccess: true,
+			wantMs:      5,
+			wantLoss:    100.0 / 3,
+		},
+		{
+			name:        "worst latency",
+			mode:        config.CompositeWorst,
+			results:     []probe.ProbeResult{up("google", 10), up("cloudflare", 5), up("quad9", 30)},
+			wantSuccess: true,
+			wantMs:      30,
+			wantLoss:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := config.Target{Name: "Internet", Probe: config.ProbeComposite, Members: members, Mode: tt.mode, Quorum: tt.quorum}
+			results := make(map[string]probe.ProbeResult)
+			for _, r := range tt.results {
+				results[r.Target] = r
+			}
+
+			got, ok := compositeResult(target, results)
+			if !ok {
+				t.Fatal("compositeResult() found no member results")
+			}
+			if got.Target != "Internet" || got.Success != tt.wantSuccess || got.LatencyMs != tt.wantMs {
+				t.Errorf("compositeResult() = %s success %v latency %v, want success %v latency %v",
+					got.Target, got.Success, got.LatencyMs, tt.wantSuccess, tt.wantMs)
+			}
+			if diff := got.LossPct - tt.wantLoss; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("LossPct = %v, want %v", got.LossPct, tt.wantLoss)
+			}
+		})
+	}
+
+	if _, ok := compositeResult(config.Target{Members: members}, nil); ok {
+		t.Error("compositeResult() without member results = ok, want none")
+	}
+}
+
+func TestCompositeFollowsRenamedMember(t *testing.T) {
+	cfg := &config.Config{
+		Targets: []config.Target{
+			{Name: "google", Host: "8.8.8.8", Probe: "icmp"},
+			{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+			{Name: "Internet", Probe: config.ProbeComposite, Members: []string{"google", "cloudflare"}},
+		},
+	}
+	c := NewCollector(cfg, nil, storage.NewMemoryBuffer(10))
+
+	if err := c.RenameTarget("google", "google-dns"); err != nil {
+		t.Fatalf("RenameTarget() error = %v", err)
+	}
+	c.targetsMu.RLock()
+	c.recordComposites(map[string]probe.ProbeResult{
+		"google-dns": {Target: "google-dns", Timestamp: time.Now(), Success: true, LatencyMs: 8},
+		"cloudflare": {Target: "cloudflare", Timestamp: time.Now(), LatencyMs: -1, LossPct: 100},
+	})
+	c.targetsMu.RUnlock()
+
+	stats := c.GetStats("Internet")
+	if stats == nil || stats.SampleCount != 1 || stats.LastMs != 8 {
+		t.Errorf("GetStats(Internet) = %+v, want one sample of 8ms", stats)
+	}
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+)
+
+// Dependency is the state of a target that depends on a parent target
+type Dependency struct {
+	Target      string    `json:"target"`
+	DependsOn   string    `json:"depends_on"`
+	DownParent  string    `json:"down_parent,omitempty"` // Topmost parent that is down, if any
+	Unreachable bool      `json:"unreachable"`           // Failing, and attributed to a down parent instead of alerting
+	Since       time.Time `json:"since,omitzero"`        // Start of the unreachable state
+}
+
+// setParents records which target each target depends on
+func (t *alertTracker) setParents(targets []config.Target) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, target := range targets {
+		if target.DependsOn != "" {
+			t.parents[target.Name] = target.DependsOn
+		}
+	}
+}
+
+// downParentLocked returns the topmost parent of a target that is down: its
+// alert is firing or its last probe failed. The last probe counts so children
+// failing in the same round as their parent are attributed to it.
+func (t *alertTracker) downParentLocked(name string) string {
+	down := ""
+	// Dependencies are acyclic; the bound only guards against surprises
+	for p, n := t.parents[name], 0; p != "" && n <= len(t.parents); p, n = t.parents[p], n+1 {
+		if state, ok := t.states[p]; ok && (state.alert.Firing || state.failing) {
+			down = p
+		}
+	}
+	return down
+}
+
+// attribute marks a target's firing alert as caused by a down parent and
+// returns the parent, or "" if no parent is down
+func (t *alertTracker) attribute(name string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[name]
+	if !ok {
+		return ""
+	}
+	parent := t.downParentLocked(name)
+	state.alert.Parent = parent
+	return parent
+}
+
+// detach clears the parent a target's alert is attributed to and returns it
+func (t *alertTracker) detach(name string) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[name]
+	if !ok {
+		return ""
+	}
+	parent := state.alert.Parent
+	state.alert.Parent = ""
+	return parent
+}
+
+// release detaches a target's alert from its parent when the target failed
+// again after the parent recovered, and returns the alert as it was. Passing
+// probes keep the alert attributed to the parent until it resolves.
+func (t *alertTracker) release(name string) *Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.states[name]
+	if !ok || state.alert.Parent == "" || !state.failing || t.downParentLocked(name) != "" {
+		return nil
+	}
+	alert := state.alert
+	state.alert.Parent = ""
+	return &alert
+}
+
+// unreachable returns the alerts attributed to a down parent, sorted by
+// target
+func (t *alertTracker) unreachable() []Alert {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	alerts := make([]Alert, 0)
+	for _, state := range t.states {
+		if state.alert.Firing && state.alert.Parent != "" {
+			alerts = append(alerts, state.alert)
+		}
+	}
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Target < alerts[j].Target })
+	return alerts
+}
+
+// dependency returns the dependency state of a target, or nil if it depends
+// on no other target
+func (t *alertTracker) dependency(name string) *Dependency {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, ok := t.parents[name]
+	if !ok {
+		return nil
+	}
+	dep := &Dependency{Target: name, DependsOn: parent, DownParent: t.downParentLocked(name)}
+	if state, ok := t.states[name]; ok && state.alert.Firing && state.alert.Parent != "" {
+		dep.Unreachable = true
+		dep.DownParent = state.alert.Parent
+		dep.Since = state.alert.Since
+	}
+	return dep
+}
+
+// Dependency returns the dependency state of a target, or nil if it has no
+// depends_on
+func (c *Collector) Dependency(targetName string) *Dependency {
+	return c.alerts.dependency(targetName)
+}
+
+// Unreachable returns the targets failing while a parent is down, whose
+// alerts are attributed to the parent, sorted by target
+func (c *Collector) Unreachable() []Alert {
+	return c.alerts.unreachable()
+}
+
+// releaseUnreachable publishes the alert of a target that is still failing
+// after the parent it was attributed to has recovered
+func (c *Collector) releaseUnreachable(name string, at time.Time) {
+	alert := c.alerts.release(name)
+	if alert == nil {
+		return
+	}
+	log.Printf("[Collector] %s still down after %s recovered", alert.Target, alert.Parent)
+	c.publish(Event{Type: EventTargetReachable, Target: alert.Target, Timestamp: at, Alert: alert})
+
+	released := *alert
+	released.Parent = ""
+	c.notifyAlert(&released, at)
+}
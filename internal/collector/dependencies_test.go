@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestDependencyAttribution(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	up := func(target string) probe.ProbeResult {
+		return probe.ProbeResult{Target: target, Success: true, LatencyMs: 10}
+	}
+	down := func(target string) probe.ProbeResult {
+		return probe.ProbeResult{Target: target, LatencyMs: -1, LossPct: 100}
+	}
+
+	tests := []struct {
+		name       string
+		results    []probe.ProbeResult // One probe round each
+		wantEvents []EventType
+		wantAlerts []string // Firing alerts afterwards
+	}{
+		{
+			name:       "children of a down parent are unreachable",
+			results:    []probe.ProbeResult{down("router"), down("host"), down("host2")},
+			wantEvents: []EventType{EventAlertFiring, EventTargetUnreachable, EventTargetUnreachable},
+			wantAlerts: []string{"router"},
+		},
+		{
+			name:       "child failing before its parent in the same round",
+			results:    []probe.ProbeResult{up("router"), up("host"), down("router"), down("host")},
+			wantEvents: []EventType{EventAlertFiring, EventTargetUnreachable},
+			wantAlerts: []string{"router"},
+		},
+		{
+			name:       "child recovering with its parent",
+			results:    []probe.ProbeResult{down("router"), down("host"), up("router"), up("host")},
+			wantEvents: []EventType{EventAlertFiring, EventTargetUnreachable, EventAlertResolved, EventTargetReachable},
+			wantAlerts: []string{},
+		},
+		{
+			name:       "child still down after its parent recovered",
+			results:    []probe.ProbeResult{down("router"), down("host"), up("router"), down("host")},
+			wantEvents: []EventType{EventAlertFiring, EventTargetUnreachable, EventAlertResolved, EventTargetReachable, EventAlertFiring},
+			wantAlerts: []string{"host"},
+		},
+		{
+			name:       "grandchild attributed to the topmost down parent",
+			results:    []probe.ProbeResult{down("router"), down("switch"), down("printer")},
+			wantEvents: []EventType{EventAlertFiring, EventTargetUnreachable, EventTargetUnreachable},
+			wantAlerts: []string{"router"},
+		},
+		{
+			name:       "child down on its own",
+			results:    []probe.ProbeResult{up("router"), down("host")},
+			wantEvents: []EventType{EventAlertFiring},
+			wantAlerts: []string{"host"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Targets: []config.Target{
+					{Name: "router", Host: "10.1.0.1", Probe: "icmp"},
+					{Name: "host", Host: "10.1.0.5", Probe: "icmp", DependsOn: "router"},
+					{Name: "host2", Host: "10.1.0.6", Probe: "icmp", DependsOn: "router"},
+					{Name: "switch", Host: "10.1.0.2", Probe: "icmp", DependsOn: "router"},
+					{Name: "printer", Host: "10.1.0.9", Probe: "icmp", DependsOn: "switch"},
+				},
+				Alerts: config.AlertConfig{Failures: 1, Recoveries: 1},
+			}
+			c := NewCollector(cfg, nil, storage.NewMemoryBuffer(0))
+			events := c.SubscribeEvents()
+
+			for i, r := range tt.results {
+				r.Timestamp = start.Add(time.Duration(i) * time.Second)
+				c.record(r.Target, r)
+			}
+
+			var got []EventType
+			for len(events) > 0 {
+				got = append(got, (<-events).Type)
+			}
+			if !slices.Equal(got, tt.wantEvents) {
+				t.Errorf("events = %v, want %v", got, tt.wantEvents)
+			}
+
+			alerts := []string{}
+			for _, a := range c.Alerts() {
+				alerts = append(alerts, a.Target)
+			}
+			if !slices.Equal(alerts, tt.wantAlerts) {
+				t.Errorf("Alerts() = %v, want %v", alerts, tt.wantAlerts)
+			}
+		})
+	}
+}
+
+func TestDependencyState(t *testing.T) {
+	cfg := &config.Config{
+		Targets: []config.Target{
+			{Name: "router", Host: "10.1.0.1", Probe: "icmp"},
+			{Name: "host", Host: "10.1.0.5", Probe: "icmp", DependsOn: "router"},
+		},
+		Alerts: config.AlertConfig{Failures: 1, Recoveries: 1},
+	}
+	c := NewCollector(cfg, nil, storage.NewMemoryBuffer(0))
+	now := time.Now()
+
+	if dep := c.Dependency("router"); dep != nil {
+		t.Errorf("Dependency(router) = %+v, want nil", dep)
+	}
+
+	c.record("router", probe.ProbeResult{Target: "router", Timestamp: now, LatencyMs: -1, LossPct: 100})
+	dep := c.Dependency("host")
+	if dep == nil || dep.DownParent != "router" || dep.Unreachable {
+		t.Errorf("Dependency(host) with router down = %+v, want parent down", dep)
+	}
+
+	c.record("host", probe.ProbeResult{Target: "host", Timestamp: now, LatencyMs: -1, LossPct: 100})
+	dep = c.Dependency("host")
+	if dep == nil || !dep.Unreachable || !dep.Since.Equal(now) {
+		t.Errorf("Dependency(host) failing = %+v, want unreachable since %v", dep, now)
+	}
+
+	inc := c.Incidents(storage.IncidentQuery{Target: "host"})
+	if len(inc) != 1 || inc[0].Kind != storage.IncidentUnreachable || !inc[0].Ongoing {
+		t.Errorf("incidents of host = %+v, want one ongoing unreachable", inc)
+	}
+
+	if err := c.RenameTarget("router", "gateway"); err != nil {
+		t.Fatalf("RenameTarget() error = %v", err)
+	}
+	if dep := c.Dependency("host"); dep == nil || dep.DependsOn != "gateway" || dep.DownParent != "gateway" {
+		t.Errorf("Dependency(host) after rename = %+v, want gateway", dep)
+	}
+}
@@ -0,0 +1,77 @@
+package collector
+
+import "time"
+
+// EventType identifies a collector event
+type EventType string
+
+// Event types
+const (
+	EventTargetRenamed  EventType = "target_renamed"
+	EventAlertFiring    EventType = "alert_firing"
+	EventAlertResolved  EventType = "alert_resolved"
+	EventAnomalyStarted EventType = "anomaly_started"
+	EventAnomalyEnded   EventType = "anomaly_ended"
+	EventLatencyShift   EventType = "latency_shift"
+
+	EventMaintenanceStarted EventType = "maintenance_started"
+	EventMaintenanceEnded   EventType = "maintenance_ended"
+
+	EventTargetUnreachable EventType = "target_unreachable" // Failing while a parent is down
+	EventTargetReachable   EventType = "target_reachable"
+)
+
+// Event is a change in the collector's state, as opposed to a probe result
+type Event struct {
+	Type      EventType     `json:"type"`
+	Target    string        `json:"target"`
+	Timestamp time.Time     `json:"timestamp"`
+	OldName   string        `json:"old_name,omitempty"` // target_renamed: name before the rename
+	Alert     *Alert        `json:"alert,omitempty"`    // alert_*, target_*reachable: state after the transition
+	Anomaly   *Anomaly      `json:"anomaly,omitempty"`  // anomaly_*: state after the transition
+	Shift     *LatencyShift `json:"shift,omitempty"`    // latency_shift: the step found
+
+	Maintenance *Maintenance `json:"maintenance,omitempty"` // maintenance_*: the period
+}
+
+// SubscribeEvents returns a channel that receives collector events
+func (c *Collector) SubscribeEvents() <-chan Event {
+	ch := make(chan Event, 100) // Buffered to prevent blocking
+
+	c.subMu.Lock()
+	c.eventSubscribers[ch] = struct{}{}
+	c.subMu.Unlock()
+
+	return ch
+}
+
+// UnsubscribeEvents removes an event subscriber
+func (c *Collector) UnsubscribeEvents(ch <-chan Event) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for subCh := range c.eventSubscribers {
+		if subCh == ch {
+			close(subCh)
+			delete(c.eventSubscribers, subCh)
+			return
+		}
+	}
+}
+
+// publish records an event in the incident log and sends it to all event
+// subscribers
+func (c *Collector) publish(event Event) {
+	c.logIncident(event)
+
+	c.subMu.RLock()
+	defer c.subMu.RUnlock()
+
+	for ch := range c.eventSubscribers {
+		select {
+		case ch <- event:
+		default:
+			// Channel buffer full, skip to prevent blocking
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Causes of incidents, so overlapping ones of a target end independently
+const (
+	causeAlert   = "alert"
+	causeAnomaly = "anomaly"
+	causeShift   = "shift"
+	causeConfig  = "config"
+	causeRestart = "restart"
+	causeMaint   = "maintenance"
+	causeParent  = "parent"
+)
+
+// openIncidentLog opens the incident log next to the RRD files, or keeps it in
+// memory when there is no persistent storage or the file cannot be read
+func openIncidentLog(cfg *config.Config, persistent bool) *storage.IncidentLog {
+	dataDir := ""
+	if persistent && cfg.Global.DataDir != "" {
+		dataDir = cfg.Global.DataDir
+	}
+	l, err := storage.OpenIncidentLog(dataDir, cfg.Events.MaxAge)
+	if err != nil {
+		log.Printf("[Collector] Failed to load incident log, keeping incidents in memory: %v", err)
+		l, _ = storage.OpenIncidentLog("", cfg.Events.MaxAge)
+	}
+	return l
+}
+
+// Incidents returns the recorded incidents matching q, newest first
+func (c *Collector) Incidents(q storage.IncidentQuery) []storage.Incident {
+	return c.incidents.Query(q)
+}
+
+// recordStartup closes incidents left open by the previous run, records
+// configuration changes since then and the restart itself
+func (c *Collector) recordStartup() {
+	now := time.Now()
+	if n, err := c.incidents.EndAll(now, "closed at restart"); err != nil {
+		log.Printf("[Collector] Failed to close incidents of the previous run: %v", err)
+	} else if n > 0 {
+		log.Printf("[Collector] Closed %d incident(s) left open by the previous run", n)
+	}
+
+	current := targetSpecs(c.config.Targets)
+	if last := c.incidents.Last(storage.IncidentDaemonRestart); last != nil && last.Config != nil {
+		for _, change := range diffTargetSpecs(last.Config, current) {
+			c.addIncident(storage.Incident{
+				Target:  change.target,
+				Kind:    storage.IncidentConfigChanged,
+				Cause:   causeConfig,
+				Message: change.message,
+				Start:   now,
+				End:     now,
+			})
+		}
+	}
+
+	c.addIncident(storage.Incident{
+		Kind:    storage.IncidentDaemonRestart,
+		Cause:   causeRestart,
+		Message: fmt.Sprintf("Collector started with %d target(s)", len(current)),
+		Start:   now,
+		End:     now,
+		Config:  current,
+	})
+}
+
+// logIncident turns a collector event into incidents: alerts, anomalies,
+// unreachable targets and maintenance open and close them, latency shifts and
+// renames are recorded as instant events
+func (c *Collector) logIncident(event Event) {
+	switch event.Type {
+	case EventAlertFiring:
+		kind := storage.IncidentDegraded
+		if event.Alert.LossPct >= 100 {
+			kind = storage.IncidentDown
+		}
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    kind,
+			Cause:   causeAlert,
+			Message: event.Alert.Reason,
+			Start:   event.Alert.Since,
+		})
+
+	case EventAlertResolved:
+		c.endIncident(event.Target, causeAlert, event.Alert.Since)
+
+	case EventAnomalyStarted:
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    storage.IncidentDegraded,
+			Cause:   causeAnomaly,
+			Message: "Anomaly: " + event.Anomaly.describe(),
+			Start:   event.Anomaly.Since,
+		})
+
+	case EventAnomalyEnded:
+		c.endIncident(event.Target, causeAnomaly, event.Anomaly.Since)
+
+	case EventLatencyShift:
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    storage.IncidentPathChanged,
+			Cause:   causeShift,
+			Message: fmt.Sprintf("Median latency %.2fms -> %.2fms", event.Shift.BeforeMs, event.Shift.AfterMs),
+			Start:   event.Shift.Timestamp,
+			End:     event.Shift.Timestamp,
+		})
+
+	case EventTargetUnreachable:
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    storage.IncidentUnreachable,
+			Cause:   causeParent,
+			Message: fmt.Sprintf("Unreachable: %s is down (%s)", event.Alert.Parent, event.Alert.Reason),
+			Start:   event.Alert.Since,
+		})
+
+	case EventTargetReachable:
+		end := event.Timestamp
+		if !event.Alert.Firing {
+			end = event.Alert.Since
+		}
+		c.endIncident(event.Target, causeParent, end)
+
+	case EventMaintenanceStarted:
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    storage.IncidentMaintenance,
+			Cause:   causeMaint,
+			Message: "Maintenance: " + event.Maintenance.describe(),
+			Start:   event.Maintenance.Start,
+		})
+
+	case EventMaintenanceEnded:
+		c.endIncident(event.Target, causeMaint, event.Maintenance.End)
+
+	case EventTargetRenamed:
+		if err := c.incidents.Rename(event.OldName, event.Target); err != nil {
+			log.Printf("[Collector] Failed to move incidents of %s: %v", event.OldName, err)
+		}
+		c.addIncident(storage.Incident{
+			Target:  event.Target,
+			Kind:    storage.IncidentConfigChanged,
+			Cause:   causeConfig,
+			Message: "Renamed from " + event.OldName,
+			Start:   event.Timestamp,
+			End:     event.Timestamp,
+		})
+	}
+}
+
+// addIncident records an incident, logging write failures
+func (c *Collector) addIncident(inc storage.Incident) {
+	if _, err := c.incidents.Add(inc); err != nil {
+		log.Printf("[Collector] Failed to record incident for %s: %v", inc.Target, err)
+	}
+}
+
+// endIncident closes a target's ongoing incident, logging write failures
+func (c *Collector) endIncident(target, cause string, end time.Time) {
+	if _, err := c.incidents.End(target, cause, end, ""); err != nil {
+		log.Printf("[Collector] Failed to record end of incident for %s: %v", target, err)
+	}
+}
+
+// targetSpecs describes the endpoint of each configured target by name
+func targetSpecs(targets []config.Target) map[string]string {
+	specs := make(map[string]string, len(targets))
+	for _, t := range targets {
+		specs[t.Name] = t.Probe + " " + t.Endpoint()
+	}
+	return specs
+}
+
+// targetChange is a difference between two target configurations
+type targetChange struct {
+	target  string
+	message string
+}
+
+// diffTargetSpecs lists the targets added, removed or changed between two
+// configurations, sorted by name
+func diffTargetSpecs(before, after map[string]string) []targetChange {
+	var changes []targetChange
+	for name, spec := range after {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			changes = append(changes, targetChange{name, "Added (" + spec + ")"})
+		case old != spec:
+			changes = append(changes, targetChange{name, "Changed from " + old + " to " + spec})
+		}
+	}
+	for name, spec := range before {
+		if _, ok := after[name]; !ok {
+			changes = append(changes, targetChange{name, "Removed (" + spec + ")"})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].target < changes[j].target })
+	return changes
+}
@@ -0,0 +1,77 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestDiffTargetSpecs(t *testing.T) {
+	before := targetSpecs([]config.Target{
+		{Name: "dns", Host: "8.8.8.8", Probe: "icmp"},
+		{Name: "web", Host: "example.com", Port: 443, Probe: "tcp"},
+		{Name: "old", Host: "10.0.0.1", Probe: "icmp"},
+	})
+	after := targetSpecs([]config.Target{
+		{Name: "dns", Host: "8.8.8.8", Probe: "icmp"},
+		{Name: "web", Host: "example.com", Port: 8443, Probe: "tcp"},
+		{Name: "gw", Host: "192.168.1.1", Probe: "icmp"},
+	})
+
+	want := []targetChange{
+		{"gw", "Added (icmp 192.168.1.1)"},
+		{"old", "Removed (icmp 10.0.0.1)"},
+		{"web", "Changed from tcp example.com:443 to tcp example.com:8443"},
+	}
+	got := diffTargetSpecs(before, after)
+	if len(got) != len(want) {
+		t.Fatalf("diffTargetSpecs() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("change %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestLogIncident(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	c := NewCollector(&config.Config{}, nil, storage.NewMemoryBuffer(0))
+
+	events := []Event{
+		{Type: EventAlertFiring, Target: "gw", Alert: &Alert{Target: "gw", Firing: true, Since: start, Reason: "unreachable", LossPct: 100}},
+		{Type: EventAnomalyStarted, Target: "gw", Anomaly: &Anomaly{Target: "gw", Active: true, Kind: AnomalyLoss, Since: start.Add(time.Minute)}},
+		{Type: EventAlertResolved, Target: "gw", Alert: &Alert{Target: "gw", Since: start.Add(5 * time.Minute)}},
+		{Type: EventLatencyShift, Target: "gw", Shift: &LatencyShift{Target: "gw", Timestamp: start.Add(10 * time.Minute), BeforeMs: 12, AfterMs: 31}},
+		{Type: EventTargetRenamed, Target: "router", OldName: "gw", Timestamp: start.Add(20 * time.Minute)},
+	}
+	for _, e := range events {
+		c.logIncident(e)
+	}
+
+	got := c.Incidents(storage.IncidentQuery{})
+	want := []struct {
+		kind    storage.IncidentKind
+		ongoing bool
+		secs    float64
+	}{
+		{storage.IncidentConfigChanged, false, 0},
+		{storage.IncidentPathChanged, false, 0},
+		{storage.IncidentDegraded, true, -1}, // Anomaly still ongoing
+		{storage.IncidentDown, false, 300},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Incidents() = %+v, want %d incidents", got, len(want))
+	}
+	for i, w := range want {
+		inc := got[i]
+		if inc.Kind != w.kind || inc.Ongoing != w.ongoing || inc.Target != "router" {
+			t.Errorf("incident %d = %+v, want %s of router, ongoing %v", i, inc, w.kind, w.ongoing)
+		}
+		if w.secs >= 0 && inc.DurationSecs != w.secs {
+			t.Errorf("incident %d lasted %vs, want %vs", i, inc.DurationSecs, w.secs)
+		}
+	}
+}
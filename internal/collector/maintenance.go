@@ -0,0 +1,524 @@
+package collector
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/cron"
+	"github.com/wellsgz/pulse/internal/report"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// silenceFileName is the data_dir file holding ad-hoc silences
+const silenceFileName = "silences.json"
+
+// maintenanceInterval is how often targets are checked for entering or
+// leaving maintenance
+const maintenanceInterval = 15 * time.Second
+
+// Silence suppresses the alerts of the targets it matches from Start until
+// End. It matches by exactly one of target name, group and tag.
+type Silence struct {
+	ID        string    `json:"id"`
+	Target    string    `json:"target,omitempty"`
+	Group     string    `json:"group,omitempty"`
+	Tag       string    `json:"tag,omitempty"`
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Comment   string    `json:"comment,omitempty"`
+	CreatedBy string    `json:"created_by,omitempty"`
+}
+
+// matches reports whether the silence covers a target
+func (s *Silence) matches(t config.Target) bool {
+	switch {
+	case s.Target != "":
+		return t.BaseName() == s.Target
+	case s.Group != "":
+		return t.Group == s.Group
+	default:
+		return t.HasTag(s.Tag)
+	}
+}
+
+// describe names what the silence matches, for logs and messages
+func (s *Silence) describe() string {
+	switch {
+	case s.Target != "":
+		return "target " + s.Target
+	case s.Group != "":
+		return "group " + s.Group
+	default:
+		return "tag " + s.Tag
+	}
+}
+
+// Maintenance is a period during which a target's alerts are suppressed
+type Maintenance struct {
+	Target  string    `json:"target"`
+	Window  string    `json:"window,omitempty"`  // Configured maintenance window
+	Silence string    `json:"silence,omitempty"` // Id of the ad-hoc silence
+	Comment string    `json:"comment,omitempty"`
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+}
+
+// describe summarizes a maintenance period for logs and the incident log
+func (m *Maintenance) describe() string {
+	what := "silence " + m.Silence
+	if m.Window != "" {
+		what = "window " + m.Window
+	}
+	if m.Comment != "" {
+		what += ": " + m.Comment
+	}
+	return what
+}
+
+// MaintenanceWindowStatus is a configured maintenance window with its
+// current or next occurrence
+type MaintenanceWindowStatus struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule,omitempty"` // Cron expression of recurring windows
+	Targets  []string  `json:"targets,omitempty"`
+	Groups   []string  `json:"groups,omitempty"`
+	Tags     []string  `json:"tags,omitempty"`
+	Comment  string    `json:"comment,omitempty"`
+	Active   bool      `json:"active"`
+	Start    time.Time `json:"start,omitzero"` // Current or next occurrence (zero if none is left)
+	End      time.Time `json:"end,omitzero"`
+}
+
+// maintenanceWindow is a configured window with its parsed times
+type maintenanceWindow struct {
+	config.MaintenanceWindow
+	schedule   *cron.Schedule // Recurring windows
+	start, end time.Time      // One-off windows
+}
+
+// occurrence returns the occurrence of the window that covers t or, with
+// upcoming set, the next one after t
+func (w *maintenanceWindow) occurrence(t time.Time, upcoming bool) (start, end time.Time, ok bool) {
+	if w.schedule == nil {
+		if t.Before(w.end) && (upcoming || !t.Before(w.start)) {
+			return w.start, w.end, true
+		}
+		return time.Time{}, time.Time{}, false
+	}
+
+	// The latest occurrence starting at or before t, if it has not ended yet
+	start = w.schedule.Next(t.Add(-w.Duration))
+	if start.IsZero() || (start.After(t) && !upcoming) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, start.Add(w.Duration), true
+}
+
+// maintenanceTracker decides which targets are in maintenance, from the
+// configured windows and the ad-hoc silences
+type maintenanceTracker struct {
+	path    string // Silence file; empty keeps silences in memory only
+	started time.Time
+
+	windows  []maintenanceWindow
+	targets  map[string]config.Target // By name, for matching groups and tags
+	silences []Silence                // Not yet expired, by start
+	active   map[string]Maintenance   // Targets currently in maintenance
+	mu       sync.Mutex
+}
+
+// newMaintenanceTracker creates a tracker for the configured windows and
+// loads the silences saved by a previous run
+func newMaintenanceTracker(cfg *config.Config, targets []config.Target, persistent bool) *maintenanceTracker {
+	t := &maintenanceTracker{
+		started: time.Now(),
+		targets: make(map[string]config.Target, len(targets)),
+		active:  make(map[string]Maintenance),
+	}
+	for _, target := range targets {
+		t.targets[target.Name] = target
+	}
+
+	for _, w := range cfg.Maintenance {
+		mw := maintenanceWindow{MaintenanceWindow: w}
+		if w.Schedule != "" {
+			s, err := cron.Parse(w.Schedule)
+			if err != nil {
+				log.Printf("[Collector] Skipping maintenance window %s: %v", w.Name, err)
+				continue
+			}
+			mw.schedule = s
+		} else {
+			mw.start, _ = time.Parse(time.RFC3339, w.Start)
+			mw.end, _ = time.Parse(time.RFC3339, w.End)
+		}
+		t.windows = append(t.windows, mw)
+	}
+
+	if persistent && cfg.Global.DataDir != "" {
+		t.path = filepath.Join(cfg.Global.DataDir, silenceFileName)
+		if err := t.load(); err != nil {
+			log.Printf("[Collector] Failed to load silences: %v", err)
+		}
+	}
+	return t
+}
+
+// load reads the silences saved by a previous run
+func (t *maintenanceTracker) load() error {
+	data, err := os.ReadFile(t.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, &t.silences)
+}
+
+// saveLocked writes the silences to the silence file. Must be called with mu held
+func (t *maintenanceTracker) saveLocked() {
+	if t.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(t.silences, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0755); err != nil {
+		log.Printf("[Collector] Failed to save silences: %v", err)
+		return
+	}
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		log.Printf("[Collector] Failed to save silences: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, t.path); err != nil {
+		log.Printf("[Collector] Failed to save silences: %v", err)
+	}
+}
+
+// covering returns the maintenance period covering a target at now, or nil.
+// Of overlapping windows and silences, the one ending last is returned.
+func (t *maintenanceTracker) covering(name string, now time.Time) *Maintenance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.coveringLocked(name, now)
+}
+
+// coveringLocked is covering for callers holding mu
+func (t *maintenanceTracker) coveringLocked(name string, now time.Time) *Maintenance {
+	target, ok := t.targets[name]
+	if !ok {
+		return nil
+	}
+
+	var best *Maintenance
+	consider := func(m Maintenance) {
+		if best == nil || m.End.After(best.End) {
+			best = &m
+		}
+	}
+	for i := range t.windows {
+		w := &t.windows[i]
+		if !w.Matches(target) {
+			continue
+		}
+		if start, end, ok := w.occurrence(now, false); ok {
+			consider(Maintenance{Target: name, Window: w.Name, Comment: w.Comment, Start: start, End: end})
+		}
+	}
+	for i := range t.silences {
+		s := &t.silences[i]
+		if s.matches(target) && !now.Before(s.Start) && now.Before(s.End) {
+			consider(Maintenance{Target: name, Silence: s.ID, Comment: s.Comment, Start: s.Start, End: s.End})
+		}
+	}
+	return best
+}
+
+// update drops expired silences and returns the targets that entered and
+// left maintenance since the last update. Periods that began before this run
+// are reported as starting with it, as earlier ones were closed at startup.
+func (t *maintenanceTracker) update(now time.Time) (started, ended []Maintenance) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	kept := t.silences[:0]
+	for _, s := range t.silences {
+		if now.Before(s.End) {
+			kept = append(kept, s)
+		}
+	}
+	if len(kept) != len(t.silences) {
+		t.silences = kept
+		t.saveLocked()
+	}
+
+	for name := range t.targets {
+		m := t.coveringLocked(name, now)
+		prev, wasActive := t.active[name]
+		switch {
+		case m != nil && !wasActive:
+			if m.Start.Before(t.started) {
+				m.Start = t.started
+			}
+			t.active[name] = *m
+			started = append(started, *m)
+		case m != nil:
+			// Still in maintenance, possibly extended by another window or silence
+			m.Start = prev.Start
+			t.active[name] = *m
+		case wasActive:
+			if now.Before(prev.End) {
+				prev.End = now // Silence removed early
+			}
+			delete(t.active, name)
+			ended = append(ended, prev)
+		}
+	}
+
+	sort.Slice(started, func(i, j int) bool { return started[i].Target < started[j].Target })
+	sort.Slice(ended, func(i, j int) bool { return ended[i].Target < ended[j].Target })
+	return started, ended
+}
+
+// activeFor returns a target's current maintenance period, or nil
+func (t *maintenanceTracker) activeFor(name string) *Maintenance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if m, ok := t.active[name]; ok {
+		return &m
+	}
+	return nil
+}
+
+// all returns the current maintenance periods, sorted by target
+func (t *maintenanceTracker) all() []Maintenance {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	periods := make([]Maintenance, 0, len(t.active))
+	for _, m := range t.active {
+		periods = append(periods, m)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].Target < periods[j].Target })
+	return periods
+}
+
+// add validates and records an ad-hoc silence
+func (t *maintenanceTracker) add(s Silence, now time.Time) (Silence, error) {
+	set := 0
+	for _, v := range []string{s.Target, s.Group, s.Tag} {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 1 {
+		return Silence{}, fmt.Errorf("exactly one of target, group and tag is required")
+	}
+	if s.Start.IsZero() {
+		s.Start = now
+	}
+	if !s.End.After(s.Start) || !s.End.After(now) {
+		return Silence{}, fmt.Errorf("end must be after start and in the future")
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	matched := false
+	for _, target := range t.targets {
+		if s.matches(target) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return Silence{}, fmt.Errorf("no target matches %s", s.describe())
+	}
+
+	id := make([]byte, 4)
+	rand.Read(id)
+	s.ID = hex.EncodeToString(id)
+
+	t.silences = append(t.silences, s)
+	sort.SliceStable(t.silences, func(i, j int) bool { return t.silences[i].Start.Before(t.silences[j].Start) })
+	t.saveLocked()
+	return s, nil
+}
+
+// remove deletes an ad-hoc silence
+func (t *maintenanceTracker) remove(id string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := slices.IndexFunc(t.silences, func(s Silence) bool { return s.ID == id })
+	if i < 0 {
+		return fmt.Errorf("silence not found: %s", id)
+	}
+	t.silences = slices.Delete(t.silences, i, i+1)
+	t.saveLocked()
+	return nil
+}
+
+// list returns the silences that have not expired, by start
+func (t *maintenanceTracker) list() []Silence {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append(make([]Silence, 0, len(t.silences)), t.silences...)
+}
+
+// windowStatus returns the configured windows with their current or next
+// occurrence
+func (t *maintenanceTracker) windowStatus(now time.Time) []MaintenanceWindowStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	windows := make([]MaintenanceWindowStatus, 0, len(t.windows))
+	for i := range t.windows {
+		w := &t.windows[i]
+		status := MaintenanceWindowStatus{
+			Name:     w.Name,
+			Schedule: w.Schedule,
+			Targets:  w.Targets,
+			Groups:   w.Groups,
+			Tags:     w.Tags,
+			Comment:  w.Comment,
+		}
+		if start, end, ok := w.occurrence(now, true); ok {
+			status.Start, status.End = start, end
+			status.Active = !now.Before(start)
+		}
+		windows = append(windows, status)
+	}
+	return windows
+}
+
+// rename moves a target's maintenance state and silences to its new name
+func (t *maintenanceTracker) rename(oldName, newName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if target, ok := t.targets[oldName]; ok {
+		target.Name = newName
+		t.targets[newName] = target
+		delete(t.targets, oldName)
+	}
+	if m, ok := t.active[oldName]; ok {
+		m.Target = newName
+		t.active[newName] = m
+		delete(t.active, oldName)
+	}
+	for i := range t.windows {
+		w := &t.windows[i]
+		if j := slices.Index(w.Targets, oldName); j >= 0 {
+			w.Targets = slices.Clone(w.Targets)
+			w.Targets[j] = newName
+		}
+	}
+
+	renamed := false
+	for i := range t.silences {
+		if t.silences[i].Target == oldName {
+			t.silences[i].Target = newName
+			renamed = true
+		}
+	}
+	if renamed {
+		t.saveLocked()
+	}
+}
+
+// Maintenance returns a target's current maintenance period, or nil
+func (c *Collector) Maintenance(targetName string) *Maintenance {
+	return c.maintenance.activeFor(targetName)
+}
+
+// MaintenanceActive returns the targets currently in maintenance, sorted by
+// target
+func (c *Collector) MaintenanceActive() []Maintenance {
+	return c.maintenance.all()
+}
+
+// MaintenanceWindows returns the configured maintenance windows with their
+// current or next occurrence
+func (c *Collector) MaintenanceWindows() []MaintenanceWindowStatus {
+	return c.maintenance.windowStatus(time.Now())
+}
+
+// Silences returns the ad-hoc silences that have not expired, by start
+func (c *Collector) Silences() []Silence {
+	return c.maintenance.list()
+}
+
+// AddSilence records an ad-hoc silence. A zero Start means now.
+func (c *Collector) AddSilence(s Silence) (Silence, error) {
+	s, err := c.maintenance.add(s, time.Now())
+	if err != nil {
+		return Silence{}, err
+	}
+	log.Printf("[Collector] Silenced %s until %s", s.describe(), s.End.Format(time.RFC3339))
+	c.checkMaintenance()
+	return s, nil
+}
+
+// RemoveSilence deletes an ad-hoc silence, ending the maintenance it caused
+func (c *Collector) RemoveSilence(id string) error {
+	if err := c.maintenance.remove(id); err != nil {
+		return err
+	}
+	log.Printf("[Collector] Removed silence %s", id)
+	c.checkMaintenance()
+	return nil
+}
+
+// MaintenancePeriods returns the recorded maintenance periods of a target
+// overlapping from..to, for excluding them from SLA reports
+func (c *Collector) MaintenancePeriods(targetName string, from, to time.Time) []report.Period {
+	incidents := c.incidents.Query(storage.IncidentQuery{
+		Target: targetName,
+		Kind:   storage.IncidentMaintenance,
+		From:   from,
+		To:     to,
+	})
+	periods := make([]report.Period, 0, len(incidents))
+	for _, inc := range incidents {
+		end := inc.End
+		if inc.Ongoing {
+			end = time.Now()
+		}
+		periods = append(periods, report.Period{From: inc.Start, To: end, Label: inc.Message})
+	}
+	return periods
+}
+
+// checkMaintenance publishes targets entering and leaving maintenance. Alerts
+// that fired unnoticed during maintenance are published when it ends.
+func (c *Collector) checkMaintenance() {
+	started, ended := c.maintenance.update(time.Now())
+	for _, m := range started {
+		log.Printf("[Collector] %s in maintenance until %s (%s)", m.Target, m.End.Format(time.RFC3339), m.describe())
+		c.publish(Event{Type: EventMaintenanceStarted, Target: m.Target, Timestamp: m.Start, Maintenance: &m})
+	}
+	for _, m := range ended {
+		log.Printf("[Collector] %s out of maintenance", m.Target)
+		c.publish(Event{Type: EventMaintenanceEnded, Target: m.Target, Timestamp: m.End, Maintenance: &m})
+		if alert := c.alerts.unsuppress(m.Target); alert != nil {
+			c.notifyAlert(alert, m.End)
+		}
+	}
+}
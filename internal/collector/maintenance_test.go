@@ -0,0 +1,105 @@
+package collector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestMaintenanceCovering(t *testing.T) {
+	// A Sunday
+	sunday := time.Date(2026, 9, 20, 0, 0, 0, 0, time.UTC)
+	targets := []config.Target{
+		{Name: "gw", Group: "wan", Tags: []string{"isp-a"}},
+		{Name: "dns", Tags: []string{"isp-b"}},
+		{Name: "gw@sg", Group: "wan", Agent: "sg"},
+	}
+	cfg := &config.Config{Maintenance: []config.MaintenanceWindow{
+		{Name: "isp-a weekly", Tags: []string{"isp-a"}, Schedule: "0 2 * * 0", Duration: 2 * time.Hour},
+		{Name: "dns move", Targets: []string{"dns"}, Start: "2026-09-21T10:00:00Z", End: "2026-09-21T11:00:00Z"},
+	}}
+	tracker := newMaintenanceTracker(cfg, targets, false)
+	if _, err := tracker.add(Silence{Group: "wan", Start: sunday.Add(3 * time.Hour), End: sunday.Add(5 * time.Hour)}, sunday); err != nil {
+		t.Fatalf("add() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		at      time.Time
+		want    string // Window name, "silence" or "" for none
+		wantEnd time.Time
+	}{
+		{"before the window", "gw", sunday.Add(time.Hour), "", time.Time{}},
+		{"recurring window", "gw", sunday.Add(2*time.Hour + 30*time.Minute), "isp-a weekly", sunday.Add(4 * time.Hour)},
+		{"silence ending later wins", "gw", sunday.Add(3*time.Hour + 30*time.Minute), "silence", sunday.Add(5 * time.Hour)},
+		{"next week", "gw", sunday.AddDate(0, 0, 7).Add(3 * time.Hour), "isp-a weekly", sunday.AddDate(0, 0, 7).Add(4 * time.Hour)},
+		{"agent series by group", "gw@sg", sunday.Add(4 * time.Hour), "silence", sunday.Add(5 * time.Hour)},
+		{"agent series without tag", "gw@sg", sunday.Add(2 * time.Hour), "", time.Time{}},
+		{"other tag", "dns", sunday.Add(2 * time.Hour), "", time.Time{}},
+		{"one-off window", "dns", sunday.Add(34 * time.Hour), "dns move", sunday.Add(35 * time.Hour)},
+		{"one-off window over", "dns", sunday.Add(35 * time.Hour), "", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := tracker.covering(tt.target, tt.at)
+			switch {
+			case tt.want == "" && m != nil:
+				t.Errorf("covering() = %+v, want none", m)
+			case tt.want == "":
+			case m == nil:
+				t.Errorf("covering() = nil, want %s", tt.want)
+			case tt.want == "silence" && m.Silence == "", tt.want != "silence" && m.Window != tt.want:
+				t.Errorf("covering() = %+v, want %s", m, tt.want)
+			case !m.End.Equal(tt.wantEnd):
+				t.Errorf("covering() ends %v, want %v", m.End, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestMaintenanceSuppressesAlerts(t *testing.T) {
+	cfg := &config.Config{
+		Targets: []config.Target{{Name: "gw", Host: "192.168.1.1", Probe: "icmp"}},
+		Alerts:  config.AlertConfig{Failures: 1, Recoveries: 1},
+	}
+	c := NewCollector(cfg, nil, storage.NewMemoryBuffer(0))
+	events := c.SubscribeEvents()
+
+	silence, err := c.AddSilence(Silence{Target: "gw", End: time.Now().Add(time.Hour), Comment: "ISP work"})
+	if err != nil {
+		t.Fatalf("AddSilence() error = %v", err)
+	}
+	if got := (<-events).Type; got != EventMaintenanceStarted {
+		t.Fatalf("event = %s, want %s", got, EventMaintenanceStarted)
+	}
+
+	c.record("gw", probe.ProbeResult{Target: "gw", Timestamp: time.Now(), LatencyMs: -1, LossPct: 100})
+	if alerts := c.Alerts(); len(alerts) != 0 {
+		t.Errorf("Alerts() during maintenance = %+v, want none", alerts)
+	}
+
+	// The alert still firing when the silence goes is published then
+	if err := c.RemoveSilence(silence.ID); err != nil {
+		t.Fatalf("RemoveSilence() error = %v", err)
+	}
+	var got []EventType
+	for len(events) > 0 {
+		got = append(got, (<-events).Type)
+	}
+	if len(got) != 2 || got[0] != EventMaintenanceEnded || got[1] != EventAlertFiring {
+		t.Errorf("events after maintenance = %v, want [%s %s]", got, EventMaintenanceEnded, EventAlertFiring)
+	}
+	if alerts := c.Alerts(); len(alerts) != 1 {
+		t.Errorf("Alerts() after maintenance = %+v, want gw", alerts)
+	}
+
+	inc := c.Incidents(storage.IncidentQuery{Kind: storage.IncidentMaintenance})
+	if len(inc) != 1 || inc[0].Ongoing || inc[0].Message != "Maintenance: silence "+silence.ID+": ISP work" {
+		t.Errorf("maintenance incidents = %+v, want one ended", inc)
+	}
+}
@@ -0,0 +1,344 @@
+package collector
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Defaults for zero shift settings
+const (
+	defaultShiftMinMs       = 5
+	defaultShiftMinPct      = 25
+	defaultShiftSensitivity = 4
+	defaultShiftMinDuration = 30 * time.Minute
+	defaultShiftWindow      = 6 * time.Hour
+	defaultShiftInterval    = 5 * time.Minute
+)
+
+// maxShifts is how many recorded shifts are kept per target
+const maxShifts = 100
+
+// LatencyShift is a lasting step in a target's median latency, typically
+// left by a routing change
+type LatencyShift struct {
+	Target      string    `json:"target"`
+	Timestamp   time.Time `json:"timestamp"`    // First minute at the new level
+	BeforeMs    float64   `json:"before_ms"`    // Median latency from BeforeSince to the step
+	AfterMs     float64   `json:"after_ms"`     // Median latency from the step to AfterUntil
+	ShiftMs     float64   `json:"shift_ms"`     // AfterMs - BeforeMs
+	Score       float64   `json:"score"`        // Step size in scaled MADs
+	BeforeSince time.Time `json:"before_since"` // Start of the data the old level was measured on
+	AfterUntil  time.Time `json:"after_until"`  // End of the data the new level was measured on
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// minuteLevel is the median latency of one minute
+type minuteLevel struct {
+	at    time.Time
+	value float64
+}
+
+// shiftDetector finds latency steps in a series by binary segmentation
+type shiftDetector struct {
+	minMs       float64
+	minPct      float64
+	sensitivity float64
+	minDuration time.Duration
+}
+
+// find returns the latency steps in points, oldest first. Points are reduced
+// to per-minute medians first, so single slow probes do not matter.
+func (d shiftDetector) find(points []storage.DataPoint) []LatencyShift {
+	levels := minuteLevels(points)
+	var shifts []LatencyShift
+	d.segment(levels, &shifts)
+	sort.Slice(shifts, func(i, j int) bool {
+		return shifts[i].Timestamp.Before(shifts[j].Timestamp)
+	})
+	return shifts
+}
+
+// segment splits levels where the squared error around the two means is
+// smallest, and keeps the split if it is a real step: the minDuration on
+// either side differ by enough in absolute, relative and statistical terms.
+// Judging only the neighborhood of the split keeps a step from being hidden
+// by another one further away. Each side is then searched for further steps.
+func (d shiftDetector) segment(levels []minuteLevel, shifts *[]LatencyShift) {
+	n := len(levels)
+	if n < 2 || levels[n-1].at.Add(time.Minute).Sub(levels[0].at) < 2*d.minDuration {
+		return
+	}
+
+	sum := make([]float64, n+1)
+	sumSq := make([]float64, n+1)
+	for i, l := range levels {
+		sum[i+1] = sum[i] + l.value
+		sumSq[i+1] = sumSq[i] + l.value*l.value
+	}
+	sse := func(from, to int) float64 {
+		s, m := sum[to]-sum[from], float64(to-from)
+		return sumSq[to] - sumSq[from] - s*s/m
+	}
+
+	best, bestCost := -1, math.Inf(1)
+	for k := 1; k < n; k++ {
+		if levels[k].at.Sub(levels[0].at) < d.minDuration ||
+			levels[n-1].at.Add(time.Minute).Sub(levels[k].at) < d.minDuration {
+			continue
+		}
+		if cost := sse(0, k) + sse(k, n); cost < bestCost {
+			best, bestCost = k, cost
+		}
+	}
+	if best < 0 {
+		return
+	}
+
+	at := levels[best].at
+	first := sort.Search(best, func(i int) bool { return at.Sub(levels[i].at) <= d.minDuration })
+	last := best + sort.Search(n-best, func(i int) bool { return levels[best+i].at.Sub(at) >= d.minDuration })
+	before := values(levels[first:best])
+	after := values(levels[best:last])
+	beforeMs := median(before)
+	afterMs := median(after)
+	deviations := make([]float64, 0, n)
+	for _, v := range before {
+		deviations = append(deviations, math.Abs(v-beforeMs))
+	}
+	for _, v := range after {
+		deviations = append(deviations, math.Abs(v-afterMs))
+	}
+	sort.Float64s(deviations)
+
+	shift := afterMs - beforeMs
+	score := shift / (madScale * math.Max(median(deviations), minMADMs))
+	if math.Abs(shift) < d.minMs || math.Abs(shift) < beforeMs*d.minPct/100 || math.Abs(score) < d.sensitivity {
+		return
+	}
+
+	*shifts = append(*shifts, LatencyShift{
+		Timestamp:   at,
+		BeforeMs:    beforeMs,
+		AfterMs:     afterMs,
+		ShiftMs:     shift,
+		Score:       score,
+		BeforeSince: levels[first].at,
+		AfterUntil:  levels[last-1].at.Add(time.Minute),
+	})
+	d.segment(levels[:best], shifts)
+	d.segment(levels[best:], shifts)
+}
+
+// minuteLevels returns the median latency of each minute with a reply,
+// oldest first
+func minuteLevels(points []storage.DataPoint) []minuteLevel {
+	var levels []minuteLevel
+	var minute time.Time
+	var pending []float64
+	flush := func() {
+		if len(pending) > 0 {
+			sort.Float64s(pending)
+			levels = append(levels, minuteLevel{at: minute, value: median(pending)})
+			pending = pending[:0]
+		}
+	}
+
+	for _, p := range points {
+		if math.IsNaN(p.Value) || p.Value < 0 {
+			continue // Lost or no data
+		}
+		if m := p.Timestamp.Truncate(time.Minute); !m.Equal(minute) {
+			flush()
+			minute = m
+		}
+		pending = append(pending, p.Value)
+	}
+	flush()
+	return levels
+}
+
+// values returns the latencies of levels, sorted
+func values(levels []minuteLevel) []float64 {
+	v := make([]float64, len(levels))
+	for i, l := range levels {
+		v[i] = l.value
+	}
+	sort.Float64s(v)
+	return v
+}
+
+// shiftTracker periodically searches recent history for latency steps and
+// remembers the ones found, so each is reported once
+type shiftTracker struct {
+	detector shiftDetector
+	window   time.Duration
+	interval time.Duration
+
+	shifts map[string][]LatencyShift // Recorded shifts by target, oldest first
+	seeded map[string]bool           // Targets searched at least once
+	mu     sync.Mutex
+}
+
+// newShiftTracker creates a tracker, filling in defaults for zero settings
+func newShiftTracker(cfg config.ShiftConfig) *shiftTracker {
+	t := &shiftTracker{
+		detector: shiftDetector{
+			minMs:       cfg.MinShiftMs,
+			minPct:      cfg.MinShiftPct,
+			sensitivity: cfg.Sensitivity,
+			minDuration: cfg.MinDuration,
+		},
+		window:   cfg.Window,
+		interval: cfg.Interval,
+		shifts:   make(map[string][]LatencyShift),
+		seeded:   make(map[string]bool),
+	}
+	if t.detector.minMs <= 0 {
+		t.detector.minMs = defaultShiftMinMs
+	}
+	if t.detector.minPct <= 0 {
+		t.detector.minPct = defaultShiftMinPct
+	}
+	if t.detector.sensitivity <= 0 {
+		t.detector.sensitivity = defaultShiftSensitivity
+	}
+	if t.detector.minDuration <= 0 {
+		t.detector.minDuration = defaultShiftMinDuration
+	}
+	if t.window <= 0 {
+		t.window = defaultShiftWindow
+	}
+	if t.interval <= 0 {
+		t.interval = defaultShiftInterval
+	}
+	return t
+}
+
+// record remembers the shifts found for a target and returns those not seen
+// before. Shifts within minDuration of a recorded one are the same step found
+// again by a later search. The first search of a target only learns what is
+// already in its history, so restarts do not report old steps again.
+func (t *shiftTracker) record(name string, found []LatencyShift, now time.Time) []LatencyShift {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	seeded := t.seeded[name]
+	t.seeded[name] = true
+
+	var added []LatencyShift
+	for _, s := range found {
+		known := false
+		for _, r := range t.shifts[name] {
+			if d := s.Timestamp.Sub(r.Timestamp); d > -t.detector.minDuration && d < t.detector.minDuration {
+				known = true
+				break
+			}
+		}
+		if known {
+			continue
+		}
+
+		s.Target = name
+		s.DetectedAt = now
+		t.shifts[name] = append(t.shifts[name], s)
+		if seeded {
+			added = append(added, s)
+		}
+	}
+
+	recorded := t.shifts[name]
+	sort.Slice(recorded, func(i, j int) bool {
+		return recorded[i].Timestamp.Before(recorded[j].Timestamp)
+	})
+	if len(recorded) > maxShifts {
+		t.shifts[name] = recorded[len(recorded)-maxShifts:]
+	}
+	return added
+}
+
+// recorded returns the shifts recorded for a target, oldest first
+func (t *shiftTracker) recorded(name string) []LatencyShift {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append(make([]LatencyShift, 0, len(t.shifts[name])), t.shifts[name]...)
+}
+
+// rename moves a target's recorded shifts to its new name
+func (t *shiftTracker) rename(oldName, newName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if shifts, ok := t.shifts[oldName]; ok {
+		for i := range shifts {
+			shifts[i].Target = newName
+		}
+		t.shifts[newName] = shifts
+		delete(t.shifts, oldName)
+	}
+	if t.seeded[oldName] {
+		t.seeded[newName] = true
+		delete(t.seeded, oldName)
+	}
+}
+
+// describe summarizes a shift for logs
+func (s *LatencyShift) describe() string {
+	return fmt.Sprintf("%.2fms -> %.2fms (%+.2fms) since %s", s.BeforeMs, s.AfterMs, s.ShiftMs, s.Timestamp.Format(time.RFC3339))
+}
+
+// LatencyShifts returns the latency steps recorded for a target, oldest first
+func (c *Collector) LatencyShifts(targetName string) []LatencyShift {
+	return c.shifts.recorded(targetName)
+}
+
+// FindLatencyShifts searches a target's history between from and to for
+// latency steps, at the resolution storage keeps for that period
+func (c *Collector) FindLatencyShifts(targetName string, from, to time.Time) ([]LatencyShift, error) {
+	var points []storage.DataPoint
+	var err error
+	if time.Since(to) < c.config.Global.Interval {
+		// Up to now: include the memory buffer's full-resolution results
+		points, err = c.RecentResults(targetName, from, 0, 0)
+	} else {
+		points, err = c.FetchHistory(targetName, from, to)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	shifts := c.shifts.detector.find(points)
+	now := time.Now()
+	for i := range shifts {
+		shifts[i].Target = targetName
+		shifts[i].DetectedAt = now
+	}
+	return shifts, nil
+}
+
+// checkShifts searches every target's recent history for new latency steps
+// and publishes them
+func (c *Collector) checkShifts() {
+	now := time.Now()
+	for _, t := range c.GetTargets() {
+		if c.ctx.Err() != nil {
+			return
+		}
+		found, err := c.FindLatencyShifts(t.Name, now.Add(-c.shifts.window), now)
+		if err != nil {
+			log.Printf("[Collector] Failed to search %s for latency shifts: %v", t.Name, err)
+			continue
+		}
+		for _, s := range c.shifts.record(t.Name, found, now) {
+			log.Printf("[Collector] Latency shift on %s: %s", s.Target, s.describe())
+			c.publish(Event{Type: EventLatencyShift, Target: s.Target, Timestamp: s.Timestamp, Shift: &s})
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package collector
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// series returns one point every 10 seconds from start, with the latency
+// given by level for each point's offset from start
+func series(start time.Time, span time.Duration, level func(time.Duration) float64) []storage.DataPoint {
+	var points []storage.DataPoint
+	for d := time.Duration(0); d < span; d += 10 * time.Second {
+		v := level(d)
+		loss := 0.0
+		if math.IsNaN(v) {
+			loss = 1
+		}
+		points = append(points, storage.DataPoint{Timestamp: start.Add(d), Value: v, Loss: loss})
+	}
+	return points
+}
+
+// jitter returns a repeating wobble of about 1ms
+func jitter(d time.Duration) float64 {
+	return float64(int(d/(10*time.Second))%5) * 0.4
+}
+
+func TestShiftDetector(t *testing.T) {
+	start := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	detector := newShiftTracker(config.ShiftConfig{}).detector
+
+	tests := []struct {
+		name   string
+		points []storage.DataPoint
+		want   []time.Duration // Offsets of the steps from start
+	}{
+		{
+			name:   "flat",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 { return 12 + jitter(d) }),
+		},
+		{
+			name: "step up",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 {
+				if d >= 100*time.Minute {
+					return 31 + jitter(d)
+				}
+				return 12 + jitter(d)
+			}),
+			want: []time.Duration{100 * time.Minute},
+		},
+		{
+			name: "up and back down",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 {
+				if d >= 60*time.Minute && d < 120*time.Minute {
+					return 40 + jitter(d)
+				}
+				return 12 + jitter(d)
+			}),
+			want: []time.Duration{60 * time.Minute, 120 * time.Minute},
+		},
+		{
+			name: "too short to last",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 {
+				if d >= 60*time.Minute && d < 70*time.Minute {
+					return 80 + jitter(d)
+				}
+				return 12 + jitter(d)
+			}),
+		},
+		{
+			name: "step too small",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 {
+				if d >= 90*time.Minute {
+					return 14 + jitter(d)
+				}
+				return 12 + jitter(d)
+			}),
+		},
+		{
+			name: "loss and spikes ignored",
+			points: series(start, 3*time.Hour, func(d time.Duration) float64 {
+				switch {
+				case int(d/(10*time.Second))%17 == 0:
+					return math.NaN()
+				case int(d/(10*time.Second))%13 == 0:
+					return 500
+				case d >= 90*time.Minute:
+					return 6 + jitter(d)
+				}
+				return 20 + jitter(d)
+			}),
+			want: []time.Duration{90 * time.Minute},
+		},
+		{
+			name:   "shorter than both levels",
+			points: series(start, 50*time.Minute, func(d time.Duration) float64 { return 12 + float64(d/(25*time.Minute))*20 }),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shifts := detector.find(tt.points)
+			if len(shifts) != len(tt.want) {
+				t.Fatalf("found %d shifts, want %d: %+v", len(shifts), len(tt.want), shifts)
+			}
+			for i, s := range shifts {
+				if got := s.Timestamp.Sub(start); got != tt.want[i] {
+					t.Errorf("shift %d at %v, want %v", i, got, tt.want[i])
+				}
+				if math.Abs(s.ShiftMs-(s.AfterMs-s.BeforeMs)) > 1e-9 {
+					t.Errorf("shift %d: ShiftMs %v does not match %v -> %v", i, s.ShiftMs, s.BeforeMs, s.AfterMs)
+				}
+			}
+		})
+	}
+}
+
+func TestShiftTrackerRecord(t *testing.T) {
+	now := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	shiftAt := func(d time.Duration) LatencyShift {
+		return LatencyShift{Timestamp: now.Add(-d), BeforeMs: 12, AfterMs: 31, ShiftMs: 19}
+	}
+	tracker := newShiftTracker(config.ShiftConfig{})
+
+	// The first search only learns existing steps
+	if added := tracker.record("A", []LatencyShift{shiftAt(5 * time.Hour)}, now); len(added) != 0 {
+		t.Errorf("first search reported %d shifts, want 0", len(added))
+	}
+
+	// The same step found again a minute off is not new; a later one is
+	added := tracker.record("A", []LatencyShift{shiftAt(5*time.Hour - time.Minute), shiftAt(time.Hour)}, now)
+	if len(added) != 1 || !added[0].Timestamp.Equal(now.Add(-time.Hour)) || added[0].Target != "A" {
+		t.Errorf("added = %+v, want the step an hour ago", added)
+	}
+	if added := tracker.record("A", []LatencyShift{shiftAt(time.Hour)}, now); len(added) != 0 {
+		t.Errorf("repeated search reported %d shifts, want 0", len(added))
+	}
+
+	tracker.rename("A", "B")
+	recorded := tracker.recorded("B")
+	if len(recorded) != 2 || recorded[0].Target != "B" || !recorded[0].Timestamp.Before(recorded[1].Timestamp) {
+		t.Errorf("recorded(B) after rename = %+v", recorded)
+	}
+	if len(tracker.recorded("A")) != 0 {
+		t.Error("shifts still recorded under the old name")
+	}
+}
@@ -2,11 +2,16 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/spf13/viper"
+	"github.com/wellsgz/pulse/internal/cron"
+	"github.com/wellsgz/pulse/internal/paths"
 )
 
 // Config represents the root configuration
@@ -14,13 +19,162 @@ type Config struct {
 	Server  ServerConfig  `mapstructure:"server"`
 	Global  GlobalConfig  `mapstructure:"global"`
 	Storage StorageConfig `mapstructure:"storage"`
-	Targets []Target      `mapstructure:"targets"`
+	Reports ReportConfig  `mapstructure:"reports"`
+	Alerts  AlertConfig   `mapstructure:"alerts"`
+	Anomaly AnomalyConfig `mapstructure:"anomaly"`
+	Shifts  ShiftConfig   `mapstructure:"shifts"`
+	Events  EventsConfig  `mapstructure:"events"`
+
+	Maintenance []MaintenanceWindow `mapstructure:"maintenance"`
+
+	Master  MasterConfig `mapstructure:"master"`
+	Agent   AgentConfig  `mapstructure:"agent"`
+	Targets []Target     `mapstructure:"targets"`
+}
+
+// MasterConfig holds settings for collecting results from remote agents
+type MasterConfig struct {
+	Agents []string `mapstructure:"agents"` // Agent names; each authenticates with an "agent" token of the same name
+}
+
+// AgentConfig makes this instance an agent that probes targets assigned by a
+// master and reports the results to it
+type AgentConfig struct {
+	Master       string        `mapstructure:"master"`        // Master API URL, e.g. "https://pulse.example.com:8080" (empty = not an agent)
+	Token        string        `mapstructure:"token"`         // Token with the "agent" role on the master
+	CAFile       string        `mapstructure:"ca_file"`       // CA for the master certificate (default: system roots)
+	CertFile     string        `mapstructure:"cert_file"`     // Client certificate when the master requires mTLS
+	KeyFile      string        `mapstructure:"key_file"`      // Client key when the master requires mTLS
+	BufferSize   int           `mapstructure:"buffer_size"`   // Results kept while the master is unreachable (default: 10000)
+	SyncInterval time.Duration `mapstructure:"sync_interval"` // How often to check for new assignments (default: 1m)
+}
+
+// Enabled reports whether this instance runs as an agent
+func (a *AgentConfig) Enabled() bool {
+	return a.Master != ""
+}
+
+// validate checks agent settings
+func (a *AgentConfig) validate() error {
+	if !a.Enabled() {
+		return nil
+	}
+	u, err := url.Parse(a.Master)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("master must be an http:// or https:// URL, got %q", a.Master)
+	}
+	if a.Token == "" {
+		return fmt.Errorf("token is required")
+	}
+	if (a.CertFile == "") != (a.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if a.BufferSize < 0 {
+		return fmt.Errorf("buffer_size must not be negative")
+	}
+	if a.SyncInterval < 0 {
+		return fmt.Errorf("sync_interval must not be negative")
+	}
+	return nil
 }
 
 // ServerConfig holds API server settings
 type ServerConfig struct {
-	Address   string `mapstructure:"address"`
-	EnableTUI bool   `mapstructure:"enable_tui"`
+	Address        string       `mapstructure:"address"`
+	EnableTUI      bool         `mapstructure:"enable_tui"`
+	Dashboard      bool         `mapstructure:"dashboard"` // Serve the web dashboard at /ui/
+	Auth           AuthConfig   `mapstructure:"auth"`
+	TLS            TLSConfig    `mapstructure:"tls"`
+	Remote         RemoteConfig `mapstructure:"remote"`
+	AllowedOrigins []string     `mapstructure:"allowed_origins"` // Browser origins allowed for CORS and WebSocket ("*" = any)
+}
+
+// RemoteConfig holds settings for the network IPC listener used by remote TUIs
+type RemoteConfig struct {
+	Address string    `mapstructure:"address"` // e.g. ":7443" (empty = disabled)
+	TLS     TLSConfig `mapstructure:"tls"`     // Defaults to server.tls
+}
+
+// RemoteTLS returns the TLS settings for the remote IPC listener
+func (s *ServerConfig) RemoteTLS() TLSConfig {
+	if s.Remote.TLS.Enabled() {
+		return s.Remote.TLS
+	}
+	return s.TLS
+}
+
+// validateRemote checks that the remote listener is encrypted and authenticated
+func (s *ServerConfig) validateRemote() error {
+	if s.Remote.Address == "" {
+		return nil
+	}
+	if err := s.Remote.TLS.validate(); err != nil {
+		return fmt.Errorf("tls.%w", err)
+	}
+	tlsCfg := s.RemoteTLS()
+	if !tlsCfg.Enabled() {
+		return fmt.Errorf("requires a certificate in server.remote.tls or server.tls")
+	}
+	if len(s.Auth.Tokens) == 0 && s.Auth.TokenFile == "" && tlsCfg.ClientCAFile == "" {
+		return fmt.Errorf("requires API tokens (server.auth) or client certificates (client_ca_file)")
+	}
+	return nil
+}
+
+// TLSConfig holds TLS settings for the API server. Certificate files are
+// reloaded when they change.
+type TLSConfig struct {
+	CertFile       string        `mapstructure:"cert_file"`
+	KeyFile        string        `mapstructure:"key_file"`
+	ClientCAFile   string        `mapstructure:"client_ca_file"`  // Require client certificates signed by this CA (mTLS)
+	ReloadInterval time.Duration `mapstructure:"reload_interval"` // How often to check the files for changes (default: 1m)
+}
+
+// Enabled reports whether TLS is configured
+func (t *TLSConfig) Enabled() bool {
+	return t.CertFile != ""
+}
+
+// validate checks that certificate settings are complete
+func (t *TLSConfig) validate() error {
+	if (t.CertFile == "") != (t.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be set together")
+	}
+	if t.ClientCAFile != "" && t.CertFile == "" {
+		return fmt.Errorf("client_ca_file requires cert_file and key_file")
+	}
+	if t.ReloadInterval < 0 {
+		return fmt.Errorf("reload_interval must not be negative")
+	}
+	return nil
+}
+
+// AuthConfig holds API authentication settings. Authentication is enabled
+// when at least one token is configured inline or in the token file.
+type AuthConfig struct {
+	Tokens    []TokenConfig `mapstructure:"tokens"`
+	TokenFile string        `mapstructure:"token_file"` // One "<role> <token> [name]" per line
+}
+
+// TokenConfig is an API token and the role it grants
+type TokenConfig struct {
+	Name  string `mapstructure:"name"`
+	Token string `mapstructure:"token"`
+	Role  string `mapstructure:"role"` // "read", "admin" or "agent"
+}
+
+// minTokenLength is the shortest accepted API token
+const minTokenLength = 16
+
+// Validate checks a token's role and length
+func (t *TokenConfig) Validate() error {
+	if t.Role != "read" && t.Role != "admin" && t.Role != "agent" {
+		return fmt.Errorf("role must be 'read', 'admin' or 'agent', got %q", t.Role)
+	}
+	if len(t.Token) < minTokenLength {
+		return fmt.Errorf("token must be at least %d characters", minTokenLength)
+	}
+	return nil
 }
 
 // GlobalConfig holds global probe settings
@@ -38,12 +192,301 @@ type StorageConfig struct {
 	XFF         float64 `mapstructure:"xff"`
 }
 
+// ReportConfig holds SLA report settings
+type ReportConfig struct {
+	Schedule         string   `mapstructure:"schedule"`             // "daily", "weekly", "monthly" or "" (on demand only)
+	OutputDir        string   `mapstructure:"output_dir"`           // Directory for scheduled reports (default: <data_dir>/reports)
+	Formats          []string `mapstructure:"formats"`              // Formats written by the scheduler: json, csv, html
+	LatencyThreshold float64  `mapstructure:"latency_threshold_ms"` // Max median latency for an interval to count as available (0 = ignore latency)
+	LossThreshold    float64  `mapstructure:"loss_threshold_pct"`   // Max loss for an interval to count as available
+	SLO              float64  `mapstructure:"slo"`                  // Availability objective in percent (e.g. 99.9)
+	WorstIncidents   int      `mapstructure:"worst_incidents"`      // Number of worst incidents listed per target
+}
+
+// AlertConfig holds the thresholds at which a target's alert fires. Zero
+// values select the defaults.
+type AlertConfig struct {
+	LossThreshold    float64 `mapstructure:"loss_threshold_pct"`   // Burst loss at or above which a probe fails (default: 100, total loss)
+	LatencyThreshold float64 `mapstructure:"latency_threshold_ms"` // Median latency above which a probe fails (0 = ignore latency)
+	Failures         int     `mapstructure:"failures"`             // Consecutive failing probes before the alert fires (default: 3)
+	Recoveries       int     `mapstructure:"recoveries"`           // Consecutive passing probes before it resolves (default: 3)
+}
+
+// validate checks alert thresholds
+func (a *AlertConfig) validate() error {
+	if a.LossThreshold < 0 || a.LossThreshold > 100 {
+		return fmt.Errorf("loss_threshold_pct must be between 0 and 100")
+	}
+	if a.LatencyThreshold < 0 {
+		return fmt.Errorf("latency_threshold_ms must not be negative")
+	}
+	if a.Failures < 0 || a.Recoveries < 0 {
+		return fmt.Errorf("failures and recoveries must not be negative")
+	}
+	return nil
+}
+
+// AnomalyConfig tunes anomaly detection against the baseline each target
+// learns from its own history. Zero values select the defaults.
+type AnomalyConfig struct {
+	Sensitivity  float64       `mapstructure:"sensitivity"`       // Robust z-score (deviations in MADs) beyond which latency is anomalous (default: 5)
+	MinDeviation float64       `mapstructure:"min_deviation_pct"` // Smallest latency change from the baseline median that counts (default: 20)
+	LossMargin   float64       `mapstructure:"loss_margin_pct"`   // Burst loss above the baseline loss rate that counts (default: 10)
+	Consecutive  int           `mapstructure:"consecutive"`       // Consecutive probes before an anomaly starts or ends (default: 3)
+	Window       time.Duration `mapstructure:"window"`            // History the baseline is learned from (default: 168h)
+	MinSamples   int           `mapstructure:"min_samples"`       // Minutes of data a baseline needs before it is used (default: 30)
+}
+
+// validate checks anomaly detection settings
+func (a *AnomalyConfig) validate() error {
+	if a.Sensitivity < 0 || a.MinDeviation < 0 || a.Window < 0 {
+		return fmt.Errorf("sensitivity, min_deviation_pct and window must not be negative")
+	}
+	if a.LossMargin < 0 || a.LossMargin > 100 {
+		return fmt.Errorf("loss_margin_pct must be between 0 and 100")
+	}
+	if a.Consecutive < 0 || a.MinSamples < 0 {
+		return fmt.Errorf("consecutive and min_samples must not be negative")
+	}
+	return nil
+}
+
+// ShiftConfig tunes change-point detection, which finds lasting steps in a
+// target's median latency such as those left by routing changes. Zero values
+// select the defaults.
+type ShiftConfig struct {
+	MinShiftMs  float64       `mapstructure:"min_shift_ms"`  // Smallest step that counts (default: 5)
+	MinShiftPct float64       `mapstructure:"min_shift_pct"` // Smallest step relative to the old level (default: 25)
+	Sensitivity float64       `mapstructure:"sensitivity"`   // Step size in scaled MADs of the jitter around both levels (default: 4)
+	MinDuration time.Duration `mapstructure:"min_duration"`  // How long both levels must hold (default: 30m)
+	Window      time.Duration `mapstructure:"window"`        // Recent history searched for steps (default: 6h)
+	Interval    time.Duration `mapstructure:"interval"`      // How often it is searched (default: 5m)
+}
+
+// validate checks change-point detection settings
+func (s *ShiftConfig) validate() error {
+	if s.MinShiftMs < 0 || s.MinShiftPct < 0 || s.Sensitivity < 0 {
+		return fmt.Errorf("min_shift_ms, min_shift_pct and sensitivity must not be negative")
+	}
+	if s.MinDuration < 0 || s.Window < 0 || s.Interval < 0 {
+		return fmt.Errorf("min_duration, window and interval must not be negative")
+	}
+	if s.Window > 0 && s.MinDuration > 0 && s.Window < 2*s.MinDuration {
+		return fmt.Errorf("window must be at least twice min_duration")
+	}
+	return nil
+}
+
+// EventsConfig holds settings for the incident log kept in the data directory
+type EventsConfig struct {
+	MaxAge time.Duration `mapstructure:"max_age"` // Drop incidents that ended longer ago (0 = keep all, default: 2160h)
+}
+
+// MaintenanceWindow is a planned period during which the alerts of matching
+// targets are suppressed and their data is excluded from SLA reports. It is
+// either one-off (start and end) or recurring (schedule and duration).
+type MaintenanceWindow struct {
+	Name    string   `mapstructure:"name"`
+	Targets []string `mapstructure:"targets"` // Target names
+	Groups  []string `mapstructure:"groups"`
+	Tags    []string `mapstructure:"tags"`
+	Comment string   `mapstructure:"comment"`
+
+	Start    string        `mapstructure:"start"`    // One-off: RFC 3339 start
+	End      string        `mapstructure:"end"`      // One-off: RFC 3339 end
+	Schedule string        `mapstructure:"schedule"` // Recurring: cron expression in local time, e.g. "0 2 * * 0"
+	Duration time.Duration `mapstructure:"duration"` // Recurring: length of each occurrence
+}
+
+// Matches reports whether the window covers a target
+func (w *MaintenanceWindow) Matches(t Target) bool {
+	return slices.Contains(w.Targets, t.BaseName()) ||
+		(t.Group != "" && slices.Contains(w.Groups, t.Group)) ||
+		slices.ContainsFunc(w.Tags, t.HasTag)
+}
+
+// validate checks that the window selects targets and has a valid time
+func (w *MaintenanceWindow) validate(targets map[string]bool) error {
+	if w.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(w.Targets) == 0 && len(w.Groups) == 0 && len(w.Tags) == 0 {
+		return fmt.Errorf("targets, groups or tags are required")
+	}
+	for _, name := range w.Targets {
+		if !targets[name] {
+			return fmt.Errorf("unknown target %q", name)
+		}
+	}
+
+	switch {
+	case w.Schedule != "" && (w.Start != "" || w.End != ""):
+		return fmt.Errorf("set either start and end, or schedule and duration")
+	case w.Schedule != "":
+		if _, err := cron.Parse(w.Schedule); err != nil {
+			return fmt.Errorf("schedule: %w", err)
+		}
+		if w.Duration <= 0 {
+			return fmt.Errorf("duration must be positive")
+		}
+	default:
+		start, errStart := time.Parse(time.RFC3339, w.Start)
+		end, errEnd := time.Parse(time.RFC3339, w.End)
+		if errStart != nil || errEnd != nil {
+			return fmt.Errorf("start and end must be RFC 3339 timestamps")
+		}
+		if !end.After(start) {
+			return fmt.Errorf("end must be after start")
+		}
+	}
+	return nil
+}
+
 // Target represents a monitoring target
 type Target struct {
+	ID    string `mapstructure:"id" json:"id,omitempty"` // Stable storage id (default: derived from name)
 	Name  string `mapstructure:"name" json:"name"`
 	Host  string `mapstructure:"host" json:"host"`
 	Port  int    `mapstructure:"port" json:"port,omitempty"`
 	Probe string `mapstructure:"probe" json:"probe_type"`
+	Group string `mapstructure:"group" json:"group,omitempty"`
+
+	// Tags label the target for maintenance windows and silences
+	Tags []string `mapstructure:"tags" json:"tags,omitempty"`
+
+	// DependsOn names the target this one is reached through, e.g. a branch
+	// router. While it is down, this target's alerts are attributed to it.
+	DependsOn string `mapstructure:"depends_on" json:"depends_on,omitempty"`
+
+	// Members, Mode and Quorum define a composite target (probe: composite),
+	// whose result is derived from the results of other targets each round
+	Members []string `mapstructure:"members" json:"members,omitempty"`
+	Mode    string   `mapstructure:"mode" json:"mode,omitempty"`     // any (default), all, quorum, best or worst
+	Quorum  int      `mapstructure:"quorum" json:"quorum,omitempty"` // Members that must respond in quorum mode
+
+	// Agents lists the agents that also probe this target ("*" = all agents)
+	Agents []string `mapstructure:"agents" json:"agents,omitempty"`
+
+	// Agent is set on series reported by an agent rather than probed locally
+	Agent string `mapstructure:"-" json:"agent,omitempty"`
+}
+
+// ProbeComposite is the probe type of targets derived from other targets
+const ProbeComposite = "composite"
+
+// Composite modes
+const (
+	CompositeAny    = "any"    // Up if any member responds; median latency of those that do
+	CompositeAll    = "all"    // Up if every member responds; median latency
+	CompositeQuorum = "quorum" // Up if Quorum members respond; median latency of those that do
+	CompositeBest   = "best"   // Up if any member responds; lowest latency
+	CompositeWorst  = "worst"  // Up if every member responds; highest latency
+)
+
+// IsComposite reports whether the target is derived from other targets
+func (t Target) IsComposite() bool {
+	return t.Probe == ProbeComposite
+}
+
+// CompositeMode returns the composite mode, defaulting to any
+func (t Target) CompositeMode() string {
+	if t.Mode == "" {
+		return CompositeAny
+	}
+	return t.Mode
+}
+
+// Endpoint describes what the target probes: host and port, or the members
+// of a composite target
+func (t Target) Endpoint() string {
+	switch {
+	case t.IsComposite():
+		mode := t.CompositeMode()
+		if mode == CompositeQuorum {
+			mode += " " + strconv.Itoa(t.Quorum)
+		}
+		return mode + " of " + strings.Join(t.Members, ", ")
+	case t.Port > 0:
+		return t.Host + ":" + strconv.Itoa(t.Port)
+	default:
+		return t.Host
+	}
+}
+
+// StorageKey returns the stable id under which the target's history is stored.
+// Without an explicit id it is derived from the name, matching the file names
+// used before ids existed.
+func (t Target) StorageKey() string {
+	if t.ID != "" {
+		return t.ID
+	}
+	return paths.SanitizeFilename(t.Name)
+}
+
+// BaseName returns the name of the configured target, without the agent
+// suffix of series reported by agents
+func (t Target) BaseName() string {
+	if t.Agent != "" {
+		return strings.TrimSuffix(t.Name, AgentSeparator+t.Agent)
+	}
+	return t.Name
+}
+
+// HasTag reports whether the target carries a tag
+func (t Target) HasTag(tag string) bool {
+	return slices.Contains(t.Tags, tag)
+}
+
+// AgentSeparator joins a target name and an agent name in the name of the
+// series reported by that agent, e.g. "Google DNS@sg"
+const AgentSeparator = "@"
+
+// ProbedBy reports whether an agent is assigned to probe the target
+func (t Target) ProbedBy(agent string) bool {
+	for _, a := range t.Agents {
+		if a == "*" || a == agent {
+			return true
+		}
+	}
+	return false
+}
+
+// AgentSeries returns the targets under which results reported by agents are
+// stored: one per (agent, target) pair, named "<target>@<agent>".
+func (c *Config) AgentSeries() []Target {
+	var series []Target
+	for _, agent := range c.Master.Agents {
+		probed := make(map[string]bool)
+		for _, t := range c.Targets {
+			if t.Agent == "" && t.ProbedBy(agent) {
+				probed[t.Name] = true
+			}
+		}
+
+		for _, t := range c.Targets {
+			if !probed[t.Name] {
+				continue
+			}
+			// An agent series depends on the agent's series of the parent,
+			// which is reached along the same path
+			dependsOn := ""
+			if probed[t.DependsOn] {
+				dependsOn = t.DependsOn + AgentSeparator + agent
+			}
+			series = append(series, Target{
+				ID:        t.StorageKey() + AgentSeparator + agent,
+				Name:      t.Name + AgentSeparator + agent,
+				Host:      t.Host,
+				Port:      t.Port,
+				Probe:     t.Probe,
+				Group:     t.Group,
+				Tags:      t.Tags,
+				DependsOn: dependsOn,
+				Agent:     agent,
+			})
+		}
+	}
+	return series
 }
 
 // Load reads configuration from the specified file
@@ -53,6 +496,7 @@ func Load(configPath string) (*Config, error) {
 	// Set defaults
 	v.SetDefault("server.address", ":8080")
 	v.SetDefault("server.enable_tui", true)
+	v.SetDefault("server.dashboard", true)
 	v.SetDefault("global.interval", "10s")
 	v.SetDefault("global.timeout", "5s")
 	v.SetDefault("global.data_dir", "./data")
@@ -60,6 +504,26 @@ func Load(configPath string) (*Config, error) {
 	v.SetDefault("storage.retention", "10s:1d,1m:7d,1h:90d")
 	v.SetDefault("storage.aggregation", "average")
 	v.SetDefault("storage.xff", 0.5)
+	v.SetDefault("reports.formats", []string{"json", "csv", "html"})
+	v.SetDefault("reports.loss_threshold_pct", 10.0)
+	v.SetDefault("reports.slo", 99.9)
+	v.SetDefault("reports.worst_incidents", 5)
+	v.SetDefault("alerts.loss_threshold_pct", 100.0)
+	v.SetDefault("alerts.failures", 3)
+	v.SetDefault("alerts.recoveries", 3)
+	v.SetDefault("anomaly.sensitivity", 5.0)
+	v.SetDefault("anomaly.min_deviation_pct", 20.0)
+	v.SetDefault("anomaly.loss_margin_pct", 10.0)
+	v.SetDefault("anomaly.consecutive", 3)
+	v.SetDefault("anomaly.window", "168h")
+	v.SetDefault("anomaly.min_samples", 30)
+	v.SetDefault("shifts.min_shift_ms", 5.0)
+	v.SetDefault("shifts.min_shift_pct", 25.0)
+	v.SetDefault("shifts.sensitivity", 4.0)
+	v.SetDefault("shifts.min_duration", "30m")
+	v.SetDefault("shifts.window", "6h")
+	v.SetDefault("shifts.interval", "5m")
+	v.SetDefault("events.max_age", "2160h")
 
 	// Set config file
 	v.SetConfigFile(configPath)
@@ -84,19 +548,39 @@ func Load(configPath string) (*Config, error) {
 
 // Validate checks configuration for required fields and valid values
 func (c *Config) Validate() error {
-	if len(c.Targets) == 0 {
+	if len(c.Targets) == 0 && !c.Agent.Enabled() {
 		return fmt.Errorf("at least one target is required")
 	}
 
+	names := make(map[string]bool)
+	keys := make(map[string]string) // storage key -> target name
 	for i, target := range c.Targets {
 		if target.Name == "" {
 			return fmt.Errorf("target[%d]: name is required", i)
 		}
+		if names[target.Name] {
+			return fmt.Errorf("target[%d] %q: duplicate target name", i, target.Name)
+		}
+		names[target.Name] = true
+		if target.ID != "" && target.ID != paths.SanitizeFilename(target.ID) {
+			return fmt.Errorf("target[%d] %q: id %q must be lowercase and contain no spaces or path characters", i, target.Name, target.ID)
+		}
+		key := target.StorageKey()
+		if other, dup := keys[key]; dup {
+			return fmt.Errorf("target[%d] %q: storage id %q is already used by target %q (set a unique id)", i, target.Name, key, other)
+		}
+		keys[key] = target.Name
+		if target.IsComposite() {
+			if len(target.Agents) > 0 {
+				return fmt.Errorf("target[%d] %q: composite targets cannot be probed by agents", i, target.Name)
+			}
+			continue // Members are checked once all names are known
+		}
 		if target.Host == "" {
 			return fmt.Errorf("target[%d] %q: host is required", i, target.Name)
 		}
 		if target.Probe != "icmp" && target.Probe != "tcp" {
-			return fmt.Errorf("target[%d] %q: probe must be 'icmp' or 'tcp', got %q", i, target.Name, target.Probe)
+			return fmt.Errorf("target[%d] %q: probe must be 'icmp', 'tcp' or 'composite', got %q", i, target.Name, target.Probe)
 		}
 		if target.Probe == "tcp" && target.Port == 0 {
 			return fmt.Errorf("target[%d] %q: port is required for TCP probe", i, target.Name)
@@ -104,6 +588,23 @@ func (c *Config) Validate() error {
 		if target.Port < 0 || target.Port > 65535 {
 			return fmt.Errorf("target[%d] %q: port must be between 0 and 65535", i, target.Name)
 		}
+		for _, agent := range target.Agents {
+			if agent != "*" && !slices.Contains(c.Master.Agents, agent) {
+				return fmt.Errorf("target[%d] %q: unknown agent %q (add it to master.agents)", i, target.Name, agent)
+			}
+		}
+	}
+
+	if err := c.validateComposites(); err != nil {
+		return err
+	}
+
+	if err := c.validateDependencies(); err != nil {
+		return err
+	}
+
+	if err := c.validateMaster(keys); err != nil {
+		return fmt.Errorf("master.%w", err)
 	}
 
 	if c.Global.Interval <= 0 {
@@ -138,6 +639,179 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("storage.retention: %w", err)
 	}
 
+	for i, t := range c.Server.Auth.Tokens {
+		if err := t.Validate(); err != nil {
+			return fmt.Errorf("server.auth.tokens[%d] %q: %w", i, t.Name, err)
+		}
+	}
+
+	if err := c.Server.TLS.validate(); err != nil {
+		return fmt.Errorf("server.tls.%w", err)
+	}
+
+	if err := c.Server.validateRemote(); err != nil {
+		return fmt.Errorf("server.remote: %w", err)
+	}
+
+	if err := c.Reports.validate(); err != nil {
+		return fmt.Errorf("reports.%w", err)
+	}
+
+	if err := c.Alerts.validate(); err != nil {
+		return fmt.Errorf("alerts.%w", err)
+	}
+	if err := c.Anomaly.validate(); err != nil {
+		return fmt.Errorf("anomaly.%w", err)
+	}
+	if err := c.Shifts.validate(); err != nil {
+		return fmt.Errorf("shifts.%w", err)
+	}
+	if c.Events.MaxAge < 0 {
+		return fmt.Errorf("events.max_age must not be negative")
+	}
+
+	windows := make(map[string]bool)
+	for i, w := range c.Maintenance {
+		if err := w.validate(names); err != nil {
+			return fmt.Errorf("maintenance[%d] %q: %w", i, w.Name, err)
+		}
+		if windows[w.Name] {
+			return fmt.Errorf("maintenance[%d] %q: duplicate window name", i, w.Name)
+		}
+		windows[w.Name] = true
+	}
+
+	if err := c.Agent.validate(); err != nil {
+		return fmt.Errorf("agent.%w", err)
+	}
+
+	return nil
+}
+
+// validateComposites checks the members and mode of composite targets.
+// Members must be probed targets, so every member result of a round is known
+// when the composite is derived.
+func (c *Config) validateComposites() error {
+	probed := make(map[string]bool, len(c.Targets))
+	for _, t := range c.Targets {
+		probed[t.Name] = !t.IsComposite()
+	}
+
+	for i, t := range c.Targets {
+		if !t.IsComposite() {
+			continue
+		}
+		if len(t.Members) == 0 {
+			return fmt.Errorf("target[%d] %q: composite targets need members", i, t.Name)
+		}
+		seen := make(map[string]bool, len(t.Members))
+		for _, m := range t.Members {
+			isProbed, ok := probed[m]
+			switch {
+			case !ok:
+				return fmt.Errorf("target[%d] %q: unknown member %q", i, t.Name, m)
+			case !isProbed:
+				return fmt.Errorf("target[%d] %q: member %q is a composite target", i, t.Name, m)
+			case seen[m]:
+				return fmt.Errorf("target[%d] %q: duplicate member %q", i, t.Name, m)
+			}
+			seen[m] = true
+		}
+
+		switch t.CompositeMode() {
+		case CompositeAny, CompositeAll, CompositeBest, CompositeWorst:
+			if t.Quorum != 0 {
+				return fmt.Errorf("target[%d] %q: quorum requires mode 'quorum'", i, t.Name)
+			}
+		case CompositeQuorum:
+			if t.Quorum < 1 || t.Quorum > len(t.Members) {
+				return fmt.Errorf("target[%d] %q: quorum must be between 1 and the number of members (%d)", i, t.Name, len(t.Members))
+			}
+		default:
+			return fmt.Errorf("target[%d] %q: mode must be any, all, quorum, best or worst, got %q", i, t.Name, t.Mode)
+		}
+	}
+	return nil
+}
+
+// validateDependencies checks that depends_on names another target and that
+// dependencies do not form a cycle
+func (c *Config) validateDependencies() error {
+	parents := make(map[string]string, len(c.Targets))
+	for _, t := range c.Targets {
+		parents[t.Name] = t.DependsOn
+	}
+
+	for i, t := range c.Targets {
+		if t.DependsOn == "" {
+			continue
+		}
+		if _, ok := parents[t.DependsOn]; !ok {
+			return fmt.Errorf("target[%d] %q: depends_on names unknown target %q", i, t.Name, t.DependsOn)
+		}
+		// Following the parents from a target must not lead back to it
+		for p, n := t.DependsOn, 0; p != ""; p, n = parents[p], n+1 {
+			if p == t.Name || n > len(parents) {
+				return fmt.Errorf("target[%d] %q: depends_on forms a cycle", i, t.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// validateMaster checks agent names and that agent series do not collide
+// with the storage ids of local targets
+func (c *Config) validateMaster(keys map[string]string) error {
+	if len(c.Master.Agents) == 0 {
+		return nil
+	}
+	if len(c.Server.Auth.Tokens) == 0 && c.Server.Auth.TokenFile == "" {
+		return fmt.Errorf("agents require API tokens (server.auth) to authenticate")
+	}
+
+	seen := make(map[string]bool)
+	for _, agent := range c.Master.Agents {
+		if agent == "*" || agent != paths.SanitizeFilename(agent) || strings.Contains(agent, AgentSeparator) {
+			return fmt.Errorf("agents: invalid agent name %q (use lowercase letters, digits, '-' and '_')", agent)
+		}
+		if seen[agent] {
+			return fmt.Errorf("agents: duplicate agent %q", agent)
+		}
+		seen[agent] = true
+	}
+
+	for _, series := range c.AgentSeries() {
+		if other, dup := keys[series.StorageKey()]; dup {
+			return fmt.Errorf("agents: storage id %q of %q is already used by target %q", series.StorageKey(), series.Name, other)
+		}
+	}
+	return nil
+}
+
+// validate checks report settings; zero values fall back to defaults
+func (r *ReportConfig) validate() error {
+	switch r.Schedule {
+	case "", "daily", "weekly", "monthly":
+	default:
+		return fmt.Errorf("schedule must be one of: daily, weekly, monthly")
+	}
+	for _, f := range r.Formats {
+		if f != "json" && f != "csv" && f != "html" {
+			return fmt.Errorf("formats: unsupported format %q (use json, csv, html)", f)
+		}
+	}
+	if r.LatencyThreshold < 0 {
+		return fmt.Errorf("latency_threshold_ms must not be negative")
+	}
+	if r.LossThreshold < 0 || r.LossThreshold > 100 {
+		return fmt.Errorf("loss_threshold_pct must be between 0 and 100")
+	}
+	if r.SLO < 0 || r.SLO >= 100 {
+		return fmt.Errorf("slo must be between 0 and 100 (exclusive)")
+	}
+	if r.WorstIncidents < 0 {
+		return fmt.Errorf("worst_incidents must not be negative")
+	}
 	return nil
 }
 
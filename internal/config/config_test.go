@@ -197,6 +197,580 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "invalid report schedule",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Reports: ReportConfig{Schedule: "hourly"},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid report slo",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Reports: ReportConfig{Schedule: "monthly", SLO: 100},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid alert loss threshold",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Alerts:  AlertConfig{LossThreshold: 150},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid anomaly loss margin",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Anomaly: AnomalyConfig{LossMargin: -5},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "shift window shorter than both levels",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Shifts:  ShiftConfig{MinDuration: time.Hour, Window: 90 * time.Minute},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "maintenance window with invalid schedule",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Maintenance: []MaintenanceWindow{{Name: "isp", Groups: []string{"wan"}, Schedule: "0 25 * * 0", Duration: time.Hour}},
+				Targets:     []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid dependency",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "router", Host: "10.1.0.1", Probe: "icmp"},
+					{Name: "host", Host: "10.1.0.5", Probe: "icmp", DependsOn: "router"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "depends_on unknown target",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{{Name: "host", Host: "10.1.0.5", Probe: "icmp", DependsOn: "router"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "depends_on cycle",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "router", Host: "10.1.0.1", Probe: "icmp", DependsOn: "host"},
+					{Name: "host", Host: "10.1.0.5", Probe: "icmp", DependsOn: "router"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid composite",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "google", Host: "8.8.8.8", Probe: "icmp"},
+					{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+					{Name: "Internet", Probe: "composite", Members: []string{"google", "cloudflare"}, Mode: "quorum", Quorum: 2},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "composite with unknown member",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "google", Host: "8.8.8.8", Probe: "icmp"},
+					{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+					{Name: "Internet", Probe: "composite", Members: []string{"google", "quad9"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "composite quorum above member count",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "google", Host: "8.8.8.8", Probe: "icmp"},
+					{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+					{Name: "Internet", Probe: "composite", Members: []string{"google", "cloudflare"}, Mode: "quorum", Quorum: 3},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "composite of a composite",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "google", Host: "8.8.8.8", Probe: "icmp"},
+					{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+					{Name: "Internet", Probe: "composite", Members: []string{"google", "cloudflare"}},
+					{Name: "Everything", Probe: "composite", Members: []string{"Internet", "google"}},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "storage id collision",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp"},
+					{Name: "google_dns", Host: "8.8.4.4", Probe: "icmp"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "collision resolved by id",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp"},
+					{ID: "google-dns-2", Name: "google_dns", Host: "8.8.4.4", Probe: "icmp"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "duplicate target name",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{ID: "a", Name: "Test", Host: "8.8.8.8", Probe: "icmp"},
+					{ID: "b", Name: "Test", Host: "8.8.4.4", Probe: "icmp"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid target id",
+			config: Config{
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{ID: "../gw", Name: "Gateway", Host: "192.168.1.1", Probe: "icmp"},
+					validTarget,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "tls cert without key",
+			config: Config{
+				Server: ServerConfig{TLS: TLSConfig{CertFile: "cert.pem"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "mtls without cert",
+			config: Config{
+				Server: ServerConfig{TLS: TLSConfig{ClientCAFile: "ca.pem"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "valid mtls",
+			config: Config{
+				Server: ServerConfig{TLS: TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem", ClientCAFile: "ca.pem"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: false,
+		},
+		{
+			name: "remote without tls",
+			config: Config{
+				Server: ServerConfig{Remote: RemoteConfig{Address: ":7443"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remote without credentials",
+			config: Config{
+				Server: ServerConfig{TLS: TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}, Remote: RemoteConfig{Address: ":7443"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: true,
+		},
+		{
+			name: "remote with tokens",
+			config: Config{
+				Server: ServerConfig{
+					Auth:   AuthConfig{Tokens: []TokenConfig{{Name: "tui", Token: "0123456789abcdef", Role: "read"}}},
+					Remote: RemoteConfig{Address: ":7443", TLS: TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}},
+				},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{validTarget},
+			},
+			wantErr: false,
+		},
+		{
+			name: "master with agents",
+			config: Config{
+				Server: ServerConfig{Auth: AuthConfig{Tokens: []TokenConfig{{Name: "sg", Token: "0123456789abcdef", Role: "agent"}}}},
+				Master: MasterConfig{Agents: []string{"sg", "fra"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"*"}}},
+			},
+			wantErr: false,
+		},
+		{
+			name: "master without tokens",
+			config: Config{
+				Master: MasterConfig{Agents: []string{"sg"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"*"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid agent name",
+			config: Config{
+				Server: ServerConfig{Auth: AuthConfig{Tokens: []TokenConfig{{Name: "sg", Token: "0123456789abcdef", Role: "agent"}}}},
+				Master: MasterConfig{Agents: []string{"Singapore Site"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"*"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "target with unknown agent",
+			config: Config{
+				Server: ServerConfig{Auth: AuthConfig{Tokens: []TokenConfig{{Name: "sg", Token: "0123456789abcdef", Role: "agent"}}}},
+				Master: MasterConfig{Agents: []string{"sg"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"fra"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agent series collides with target",
+			config: Config{
+				Server: ServerConfig{Auth: AuthConfig{Tokens: []TokenConfig{{Name: "sg", Token: "0123456789abcdef", Role: "agent"}}}},
+				Master: MasterConfig{Agents: []string{"sg"}},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: []Target{
+					{Name: "Google DNS", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"sg"}},
+					{Name: "dns", ID: "google_dns@sg", Host: "8.8.4.4", Probe: "icmp"},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "agent without targets",
+			config: Config{
+				Agent: AgentConfig{Master: "https://pulse.example.com:8080", Token: "0123456789abcdef"},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "agent with invalid master url",
+			config: Config{
+				Agent: AgentConfig{Master: "pulse.example.com", Token: "0123456789abcdef"},
+				Global: GlobalConfig{
+					Interval: 10 * time.Second,
+					Timeout:  5 * time.Second,
+					Pings:    20,
+				},
+				Storage: StorageConfig{
+					Retention:   "10s:1d",
+					Aggregation: "average",
+					XFF:         0.5,
+				},
+				Targets: nil,
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
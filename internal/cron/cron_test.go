@@ -0,0 +1,59 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday
+	base := time.Date(2026, 9, 16, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr string
+		from time.Time
+		want time.Time
+	}{
+		{"* * * * *", base, base.Add(time.Minute)},
+		{"*/15 * * * *", base, time.Date(2026, 9, 16, 10, 45, 0, 0, time.UTC)},
+		{"0 2 * * *", base, time.Date(2026, 9, 17, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 0", base, time.Date(2026, 9, 20, 2, 0, 0, 0, time.UTC)},
+		{"0 2 * * 7", base, time.Date(2026, 9, 20, 2, 0, 0, 0, time.UTC)},
+		{"30 1 1 * *", base, time.Date(2026, 10, 1, 1, 30, 0, 0, time.UTC)},
+		{"0 0 1,15 * 1-5", base, time.Date(2026, 9, 17, 0, 0, 0, 0, time.UTC)}, // Day of month or weekday
+		{"0 3 29 2 *", base, time.Date(2028, 2, 29, 3, 0, 0, 0, time.UTC)},
+		{"@monthly", base, time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 30 2 *", base, time.Time{}}, // Never
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			s, err := Parse(tt.expr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := s.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+	} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", expr)
+		}
+	}
+}
@@ -0,0 +1,484 @@
+// Package graph renders SmokePing-style latency graphs as PNG or SVG images:
+// the latency spread of each time slot is drawn as grey "smoke" and the
+// median as a line colored by packet loss.
+package graph
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Format is an image format
+type Format string
+
+// Supported image formats
+const (
+	FormatPNG Format = "png"
+	FormatSVG Format = "svg"
+)
+
+// Size limits and defaults in pixels
+const (
+	DefaultWidth  = 800
+	DefaultHeight = 300
+	MinWidth      = 200
+	MaxWidth      = 4000
+	MinHeight     = 100
+	MaxHeight     = 2000
+)
+
+// Layout in pixels
+const (
+	columnWidth  = 2  // Width of one time slot
+	marginLeft   = 64 // Y axis labels
+	marginRight  = 16
+	marginTop    = 28 // Title
+	marginBottom = 44 // X axis labels and summary
+	charWidth    = 7  // Approximate width of a label character
+	xLabelGap    = 90 // Minimum distance between x axis labels
+)
+
+// Options describe the graph to render
+type Options struct {
+	Title    string
+	From, To time.Time
+	Width    int            // Pixels (0 = DefaultWidth)
+	Height   int            // Pixels (0 = DefaultHeight)
+	Location *time.Location // For time labels (nil = local time)
+}
+
+// color is an RGB color with opacity
+type color struct {
+	R, G, B uint8
+	A       float64 // 0-1
+}
+
+// Palette (light background, so graphs embed well in mail, wikis and chat)
+var (
+	colorBackground = color{0xFF, 0xFF, 0xFF, 1}
+	colorGrid       = color{0xE5, 0xE7, 0xEB, 1}
+	colorText       = color{0x37, 0x41, 0x51, 1}
+	colorMuted      = color{0x6B, 0x72, 0x80, 1}
+	colorSmoke      = color{0x1F, 0x29, 0x37, 1} // Used with band opacity
+)
+
+// lossColors color the median by packet loss ratio, as in the web dashboard
+var lossColors = []struct {
+	limit float64
+	label string
+	color color
+}{
+	{0, "0%", color{0x10, 0xB9, 0x81, 1}},
+	{0.05, "5%", color{0x06, 0xB6, 0xD4, 1}},
+	{0.10, "10%", color{0x7C, 0x3A, 0xED, 1}},
+	{0.25, "25%", color{0xF5, 0x9E, 0x0B, 1}},
+	{0.50, "50%", color{0xF9, 0x73, 0x16, 1}},
+	{1, ">50%", color{0xEF, 0x44, 0x44, 1}},
+}
+
+// smokeBands are the quantile ranges drawn as smoke, lightest first
+var smokeBands = []struct {
+	lo, hi, alpha float64
+}{
+	{0, 1, 0.10},
+	{0.10, 0.90, 0.16},
+	{0.25, 0.75, 0.26},
+}
+
+// anchor is the horizontal alignment of a text
+type anchor int
+
+const (
+	anchorStart anchor = iota
+	anchorMiddle
+	anchorEnd
+)
+
+// shape is a filled rectangle, or a text when text is set. Texts are
+// positioned by their baseline.
+type shape struct {
+	x, y, w, h float64
+	color      color
+	text       string
+	anchor     anchor
+}
+
+// column is the data of one time slot
+type column struct {
+	values []float64 // Latencies, sorted
+	loss   float64   // Sum of loss ratios; divided by n once complete
+	n      int       // Data points, including lost ones
+}
+
+// Render draws the data points as a graph image
+func Render(w io.Writer, format Format, points []storage.DataPoint, opts Options) error {
+	shapes, width, height := layout(points, opts)
+	switch format {
+	case FormatPNG:
+		return renderPNG(w, shapes, width, height)
+	case FormatSVG:
+		return renderSVG(w, shapes, width, height)
+	}
+	return fmt.Errorf("unsupported format: %s", format)
+}
+
+// layout turns the data points into shapes on a canvas of the returned size
+func layout(points []storage.DataPoint, opts Options) ([]shape, int, int) {
+	width := clamp(opts.Width, DefaultWidth, MinWidth, MaxWidth)
+	height := clamp(opts.Height, DefaultHeight, MinHeight, MaxHeight)
+	loc := opts.Location
+	if loc == nil {
+		loc = time.Local
+	}
+
+	plotX := float64(marginLeft)
+	plotY := float64(marginTop)
+	plotW := float64(width - marginLeft - marginRight)
+	plotH := float64(height - marginTop - marginBottom)
+
+	shapes := []shape{{w: float64(width), h: float64(height), color: colorBackground}}
+	if opts.Title != "" {
+		shapes = append(shapes, shape{x: plotX, y: 18, text: opts.Title, color: colorText})
+	}
+
+	cols := bucket(points, opts.From, opts.To, int(plotW)/columnWidth)
+	top, step := scale(cols)
+	y := func(v float64) float64 {
+		return plotY + plotH - math.Min(v, top)/top*plotH
+	}
+
+	// Horizontal grid with latency labels
+	for v := 0.0; v <= top+step/2; v += step {
+		yy := math.Round(y(v))
+		shapes = append(shapes,
+			shape{x: plotX, y: yy, w: plotW, h: 1, color: colorGrid},
+			shape{x: plotX - 6, y: yy + 4, text: formatMs(v, step), anchor: anchorEnd, color: colorMuted},
+		)
+	}
+
+	// Vertical grid with time labels
+	for _, t := range timeTicks(opts.From, opts.To, plotW, loc) {
+		x := math.Round(plotX + float64(t.Sub(opts.From))/float64(opts.To.Sub(opts.From))*plotW)
+		shapes = append(shapes,
+			shape{x: x, y: plotY, w: 1, h: plotH, color: colorGrid},
+			shape{x: x, y: plotY + plotH + 16, text: timeLabel(t, opts.To.Sub(opts.From), loc), anchor: anchorMiddle, color: colorMuted},
+		)
+	}
+
+	// Smoke, darker towards the median
+	for i, c := range cols {
+		if len(c.values) == 0 {
+			continue
+		}
+		x := plotX + float64(i*columnWidth)
+		for _, band := range smokeBands {
+			upper := y(quantile(c.values, band.hi))
+			lower := y(quantile(c.values, band.lo))
+			smoke := colorSmoke
+			smoke.A = band.alpha
+			shapes = append(shapes, shape{x: x, y: upper, w: columnWidth, h: math.Max(1, lower-upper), color: smoke})
+		}
+	}
+
+	// Median colored by loss; slots where every probe was lost are shaded
+	for i, c := range cols {
+		if c.n == 0 {
+			continue
+		}
+		x := plotX + float64(i*columnWidth)
+		lc := lossColor(c.loss)
+		if len(c.values) > 0 {
+			shapes = append(shapes, shape{x: x, y: y(quantile(c.values, 0.5)) - 1, w: columnWidth, h: 2, color: lc})
+		} else {
+			lc.A = 0.35
+			shapes = append(shapes, shape{x: x, y: plotY, w: columnWidth, h: plotH, color: lc})
+		}
+	}
+
+	// Loss legend, right-aligned, if there is room next to the summary
+	bottom := float64(height - 10)
+	x := plotX + plotW
+	if width >= 560 {
+		for i := len(lossColors) - 1; i >= 0; i-- {
+			lc := lossColors[i]
+			x -= float64(len(lc.label) * charWidth)
+			shapes = append(shapes, shape{x: x, y: bottom, text: lc.label, color: colorMuted})
+			x -= 12
+			shapes = append(shapes, shape{x: x, y: bottom - 5, w: 9, h: 3, color: lc.color})
+			x -= 8
+		}
+		label := "loss:"
+		x -= float64(len(label) * charWidth)
+		shapes = append(shapes, shape{x: x, y: bottom, text: label, color: colorMuted})
+	}
+
+	// Summary, with as many figures as fit
+	parts := summary(points)
+	if len(parts) == 0 {
+		shapes = append(shapes, shape{x: plotX + plotW/2, y: plotY + plotH/2, text: "No data in this range", anchor: anchorMiddle, color: colorMuted})
+	}
+	text := ""
+	for _, part := range parts {
+		next := part
+		if text != "" {
+			next = text + "   " + part
+		}
+		if float64(len(next)*charWidth) > x-plotX-16 {
+			break
+		}
+		text = next
+	}
+	if text != "" {
+		shapes = append(shapes, shape{x: plotX, y: bottom, text: text, color: colorText})
+	}
+
+	return shapes, width, height
+}
+
+// bucket sorts the data points into count time slots between from and to
+func bucket(points []storage.DataPoint, from, to time.Time, count int) []column {
+	cols := make([]column, max(count, 1))
+	span := to.Sub(from)
+	if span <= 0 {
+		return cols
+	}
+
+	for _, p := range points {
+		if math.IsNaN(p.Loss) && math.IsNaN(p.Value) {
+			continue // No data
+		}
+		i := int(float64(p.Timestamp.Sub(from)) / float64(span) * float64(len(cols)))
+		if i < 0 || i >= len(cols) {
+			continue
+		}
+		c := &cols[i]
+		c.n++
+		if !math.IsNaN(p.Loss) {
+			c.loss += p.Loss
+		}
+		if !math.IsNaN(p.Value) {
+			c.values = append(c.values, p.Value)
+		}
+	}
+
+	for i := range cols {
+		sort.Float64s(cols[i].values)
+		if cols[i].n > 0 {
+			cols[i].loss /= float64(cols[i].n)
+		}
+	}
+	return cols
+}
+
+// scale returns the top of the latency axis and its tick step. It follows the
+// upper spread of the data rather than the maximum, so a single spike does
+// not flatten the graph.
+func scale(cols []column) (top, step float64) {
+	for _, c := range cols {
+		if len(c.values) > 0 {
+			top = math.Max(top, quantile(c.values, 0.9))
+		}
+	}
+	top = math.Max(1, top*1.15)
+	step = niceStep(top / 4)
+	return math.Ceil(top/step) * step, step
+}
+
+// niceStep rounds a tick step up to 1, 2 or 5 times a power of ten
+func niceStep(x float64) float64 {
+	pow := math.Pow(10, math.Floor(math.Log10(x)))
+	switch f := x / pow; {
+	case f <= 1:
+		return pow
+	case f <= 2:
+		return 2 * pow
+	case f <= 5:
+		return 5 * pow
+	}
+	return 10 * pow
+}
+
+// formatMs formats a latency label with the precision the step needs
+func formatMs(v, step float64) string {
+	decimals := 0
+	if step < 1 {
+		decimals = int(math.Ceil(-math.Log10(step)))
+	}
+	return fmt.Sprintf("%.*f ms", decimals, v)
+}
+
+// tickSteps are the candidate distances between time labels
+var tickSteps = []time.Duration{
+	time.Minute, 2 * time.Minute, 5 * time.Minute, 10 * time.Minute, 15 * time.Minute, 30 * time.Minute,
+	time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour,
+	24 * time.Hour, 2 * 24 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour, 28 * 24 * time.Hour,
+}
+
+// monthSteps are the candidate distances between time labels, in months, for
+// spans too long for tickSteps. Longer spans double the last step.
+var monthSteps = []int{3, 6, 12, 24, 60, 120}
+
+// timeTicks returns label times between from and to, spaced to fit the width.
+// Day steps are aligned to local midnight.
+func timeTicks(from, to time.Time, width float64, loc *time.Location) []time.Time {
+	span := to.Sub(from)
+	if span <= 0 {
+		return nil
+	}
+	maxTicks := max(1, int(width/xLabelGap))
+	step := time.Duration(0)
+	for _, s := range tickSteps {
+		if int(span/s) <= maxTicks {
+			step = s
+			break
+		}
+	}
+	if step == 0 {
+		return monthTicks(from, to, maxTicks, loc)
+	}
+
+	var t time.Time
+	if step >= 24*time.Hour {
+		l := from.In(loc)
+		t = time.Date(l.Year(), l.Month(), l.Day(), 0, 0, 0, 0, loc)
+	} else {
+		_, offset := from.In(loc).Zone()
+		shift := time.Duration(offset) * time.Second
+		t = from.Add(shift).Truncate(step).Add(-shift)
+	}
+
+	var ticks []time.Time
+	for ; !t.After(to); t = t.Add(step) {
+		if !t.Before(from) {
+			ticks = append(ticks, t)
+		}
+	}
+	return ticks
+}
+
+// monthTicks returns label times on the first of every few months, or of
+// every few years, between from and to
+func monthTicks(from, to time.Time, maxTicks int, loc *time.Location) []time.Time {
+	// Counted in the shortest months, so there are never too many labels
+	month := 28 * 24 * time.Hour
+	span := to.Sub(from)
+	months := 0
+	for _, m := range monthSteps {
+		if int(span/(time.Duration(m)*month)) <= maxTicks {
+			months = m
+			break
+		}
+	}
+	if months == 0 {
+		months = monthSteps[len(monthSteps)-1]
+		for int(span/(time.Duration(months)*month)) > maxTicks {
+			months *= 2
+		}
+	}
+
+	// Aligned to a multiple of the step, counted from January
+	l := from.In(loc)
+	year, m := l.Year(), int(l.Month())-1
+	if months >= 12 {
+		year -= year % (months / 12)
+		m = 0
+	} else {
+		m -= m % months
+	}
+	first := time.Date(year, time.Month(m+1), 1, 0, 0, 0, 0, loc)
+
+	var ticks []time.Time
+	for i := 0; ; i++ {
+		t := first.AddDate(0, i*months, 0)
+		if t.After(to) {
+			break
+		}
+		if !t.Before(from) {
+			ticks = append(ticks, t)
+		}
+	}
+	return ticks
+}
+
+// timeLabel formats a time label for a graph spanning span
+func timeLabel(t time.Time, span time.Duration, loc *time.Location) string {
+	t = t.In(loc)
+	switch {
+	case span <= 24*time.Hour:
+		return t.Format("15:04")
+	case span <= 7*24*time.Hour:
+		return t.Format("Jan 2 15:04")
+	case span <= 365*24*time.Hour:
+		return t.Format("Jan 2")
+	}
+	return t.Format("Jan 2006")
+}
+
+// summary describes all data points, most important figure first. It is
+// empty without data.
+func summary(points []storage.DataPoint) []string {
+	var values []float64
+	var loss float64
+	n := 0
+	for _, p := range points {
+		if math.IsNaN(p.Loss) && math.IsNaN(p.Value) {
+			continue
+		}
+		n++
+		if !math.IsNaN(p.Loss) {
+			loss += p.Loss
+		}
+		if !math.IsNaN(p.Value) {
+			values = append(values, p.Value)
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+
+	lossText := fmt.Sprintf("loss %.1f%%", loss/float64(n)*100)
+	if len(values) == 0 {
+		return []string{lossText}
+	}
+	sort.Float64s(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return []string{
+		fmt.Sprintf("median %.1f ms", quantile(values, 0.5)),
+		lossText,
+		fmt.Sprintf("avg %.1f ms", sum/float64(len(values))),
+		fmt.Sprintf("max %.1f ms", values[len(values)-1]),
+	}
+}
+
+// lossColor returns the median color for a loss ratio
+func lossColor(loss float64) color {
+	for _, lc := range lossColors {
+		if loss <= lc.limit {
+			return lc.color
+		}
+	}
+	return lossColors[len(lossColors)-1].color
+}
+
+// quantile interpolates the q-quantile of sorted values
+func quantile(sorted []float64, q float64) float64 {
+	pos := float64(len(sorted)-1) * q
+	lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(pos-float64(lo))
+}
+
+// clamp returns v limited to [lo, hi], or def if v is zero
+func clamp(v, def, lo, hi int) int {
+	if v == 0 {
+		return def
+	}
+	return min(max(v, lo), hi)
+}
@@ -0,0 +1,184 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/png"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestNiceStep(t *testing.T) {
+	tests := []struct {
+		in, want float64
+	}{
+		{0.3, 0.5},
+		{1, 1},
+		{1.2, 2},
+		{3, 5},
+		{7, 10},
+		{25, 50},
+		{120, 200},
+	}
+
+	for _, tt := range tests {
+		if got := niceStep(tt.in); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("niceStep(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTimeTicks(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		span  time.Duration
+		width float64
+		first time.Time
+		step  time.Duration
+	}{
+		{"hour", time.Hour, 720, time.Date(2024, 1, 15, 10, 10, 0, 0, time.UTC), 10 * time.Minute},
+		{"day", 24 * time.Hour, 720, time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC), 3 * time.Hour},
+		{"week", 7 * 24 * time.Hour, 720, time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), 24 * time.Hour},
+		{"narrow hour", time.Hour, 200, time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC), 30 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks := timeTicks(from, from.Add(tt.span), tt.width, time.UTC)
+			if len(ticks) == 0 {
+				t.Fatal("no ticks")
+			}
+			if !ticks[0].Equal(tt.first) {
+				t.Errorf("first tick = %v, want %v", ticks[0], tt.first)
+			}
+			if len(ticks) > 1 && ticks[1].Sub(ticks[0]) != tt.step {
+				t.Errorf("step = %v, want %v", ticks[1].Sub(ticks[0]), tt.step)
+			}
+			if max := int(tt.width / xLabelGap); len(ticks) > max {
+				t.Errorf("%d ticks, want at most %d", len(ticks), max)
+			}
+		})
+	}
+}
+
+func TestTimeTicksMonths(t *testing.T) {
+	from := time.Date(2024, 2, 15, 10, 7, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		span   time.Duration
+		width  float64
+		first  time.Time
+		months int
+	}{
+		{"year", 365 * day, 720, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), 3},
+		{"narrow year", 365 * day, 270, time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC), 6},
+		{"ten years", 3650 * day, 720, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), 24},
+		{"century", 100 * 365 * day, 720, time.Date(2040, 1, 1, 0, 0, 0, 0, time.UTC), 240},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticks := timeTicks(from, from.Add(tt.span), tt.width, time.UTC)
+			if len(ticks) < 2 {
+				t.Fatalf("%d ticks, want at least 2", len(ticks))
+			}
+			if !ticks[0].Equal(tt.first) {
+				t.Errorf("first tick = %v, want %v", ticks[0], tt.first)
+			}
+			if want := ticks[0].AddDate(0, tt.months, 0); !ticks[1].Equal(want) {
+				t.Errorf("second tick = %v, want %v", ticks[1], want)
+			}
+			if max := int(tt.width / xLabelGap); len(ticks) > max {
+				t.Errorf("%d ticks, want at most %d", len(ticks), max)
+			}
+		})
+	}
+}
+
+func TestBucket(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	points := []storage.DataPoint{
+		{Timestamp: from, Value: 30, Loss: 0},
+		{Timestamp: from.Add(time.Minute), Value: 10, Loss: 0},
+		{Timestamp: from.Add(2 * time.Minute), Value: math.NaN(), Loss: 1},
+		{Timestamp: from.Add(4 * time.Minute), Value: math.NaN(), Loss: 1},
+		{Timestamp: from.Add(7 * time.Minute), Value: math.NaN(), Loss: math.NaN()}, // No data
+		{Timestamp: from.Add(time.Hour), Value: 5, Loss: 0},                         // Out of range
+	}
+
+	cols := bucket(points, from, from.Add(10*time.Minute), 2)
+
+	if cols[0].n != 4 || len(cols[0].values) != 2 || cols[0].loss != 0.5 {
+		t.Errorf("first column = %+v, want 4 points, 2 values, loss 0.5", cols[0])
+	}
+	if cols[0].values[0] != 10 || cols[0].values[1] != 30 {
+		t.Errorf("values = %v, want sorted [10 30]", cols[0].values)
+	}
+	if cols[1].n != 0 {
+		t.Errorf("second column has %d points, want 0", cols[1].n)
+	}
+}
+
+func TestRender(t *testing.T) {
+	from := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	var points []storage.DataPoint
+	for i := 0; i < 360; i++ {
+		p := storage.DataPoint{Timestamp: from.Add(time.Duration(i) * 10 * time.Second), Value: 10 + float64(i%7), Loss: 0}
+		if i%50 == 0 {
+			p.Value, p.Loss = math.NaN(), 1
+		}
+		points = append(points, p)
+	}
+	opts := Options{Title: "Google DNS <1h>", From: from, To: from.Add(time.Hour), Width: 640, Height: 240, Location: time.UTC}
+
+	tests := []struct {
+		name   string
+		points []storage.DataPoint
+	}{
+		{"data", points},
+		{"empty", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Render(&buf, FormatPNG, tt.points, opts); err != nil {
+				t.Fatalf("PNG: %v", err)
+			}
+			img, err := png.Decode(&buf)
+			if err != nil {
+				t.Fatalf("PNG decode: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != 640 || b.Dy() != 240 {
+				t.Errorf("PNG size = %dx%d, want 640x240", b.Dx(), b.Dy())
+			}
+
+			buf.Reset()
+			if err := Render(&buf, FormatSVG, tt.points, opts); err != nil {
+				t.Fatalf("SVG: %v", err)
+			}
+			var svg struct {
+				XMLName xml.Name `xml:"svg"`
+				Width   int      `xml:"width,attr"`
+				Texts   []string `xml:"text"`
+			}
+			if err := xml.Unmarshal(buf.Bytes(), &svg); err != nil {
+				t.Fatalf("SVG parse: %v", err)
+			}
+			if svg.Width != 640 || len(svg.Texts) == 0 || svg.Texts[0] != opts.Title {
+				t.Errorf("SVG width %d, texts %q: want width 640 and the title first", svg.Width, svg.Texts)
+			}
+		})
+	}
+
+	if err := Render(&bytes.Buffer{}, Format("gif"), points, opts); err == nil {
+		t.Error("gif: want an error")
+	}
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"image"
+	imagecolor "image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// renderPNG rasterizes the shapes and writes them as a PNG image
+func renderPNG(w io.Writer, shapes []shape, width, height int) error {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	face := basicfont.Face7x13
+
+	for _, s := range shapes {
+		src := image.NewUniform(s.color.nrgba())
+		if s.text != "" {
+			d := &font.Drawer{Dst: img, Src: src, Face: face}
+			x := s.x
+			switch s.anchor {
+			case anchorMiddle:
+				x -= float64(d.MeasureString(s.text).Round()) / 2
+			case anchorEnd:
+				x -= float64(d.MeasureString(s.text).Round())
+			}
+			d.Dot = fixed.P(int(math.Round(x)), int(math.Round(s.y)))
+			d.DrawString(s.text)
+			continue
+		}
+
+		r := image.Rect(
+			int(math.Round(s.x)), int(math.Round(s.y)),
+			int(math.Round(s.x+s.w)), int(math.Round(s.y+s.h)),
+		)
+		draw.Draw(img, r, src, image.Point{}, draw.Over)
+	}
+
+	return png.Encode(w, img)
+}
+
+// nrgba converts the color for the image package
+func (c color) nrgba() imagecolor.NRGBA {
+	return imagecolor.NRGBA{R: c.R, G: c.G, B: c.B, A: uint8(math.Round(c.A * 255))}
+}
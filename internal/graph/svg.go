@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+)
+
+// renderSVG writes the shapes as an SVG document
+func renderSVG(w io.Writer, shapes []shape, width, height int) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="11">`+"\n",
+		width, height, width, height)
+
+	for _, s := range shapes {
+		if s.text != "" {
+			fmt.Fprintf(bw, `<text x="%.1f" y="%.1f" fill="%s"%s%s>%s</text>`+"\n",
+				s.x, s.y, s.color.hex(), textAnchor(s.anchor), opacity(s.color), html.EscapeString(s.text))
+			continue
+		}
+		fmt.Fprintf(bw, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"%s/>`+"\n",
+			s.x, s.y, s.w, s.h, s.color.hex(), opacity(s.color))
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// hex returns the color in #rrggbb notation
+func (c color) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// opacity returns the fill-opacity attribute of translucent colors
+func opacity(c color) string {
+	if c.A >= 1 {
+		return ""
+	}
+	return fmt.Sprintf(` fill-opacity="%.2f"`, c.A)
+}
+
+// textAnchor returns the text-anchor attribute for an alignment
+func textAnchor(a anchor) string {
+	switch a {
+	case anchorMiddle:
+		return ` text-anchor="middle"`
+	case anchorEnd:
+		return ` text-anchor="end"`
+	}
+	return ""
+}
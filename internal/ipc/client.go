@@ -3,18 +3,29 @@ package ipc
 import (
 	"bufio"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math"
 	"net"
+	"slices"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/wellsgz/pulse/internal/collector"
 	"github.com/wellsgz/pulse/internal/config"
 	"github.com/wellsgz/pulse/internal/storage"
+	"github.com/wellsgz/pulse/internal/tlsutil"
 )
 
+// dialTimeout bounds connecting to a remote daemon
+const dialTimeout = 10 * time.Second
+
+// clientName identifies this client in hello requests
+const clientName = "pulse"
+
 // Client connects to the IPC server
 type Client struct {
 	conn    net.Conn
@@ -23,6 +34,10 @@ type Client struct {
 
 	resultCh chan ProbeResultData
 
+	// Negotiated in the hello exchange
+	version      int
+	capabilities []string
+
 	// Pending requests waiting for responses, keyed by request ID
 	pending   map[string]chan Response
 	pendingMu sync.Mutex
@@ -40,6 +55,42 @@ func generateRequestID() string {
 	return hex.EncodeToString(b)
 }
 
+// RemoteOptions configures a TLS connection to a remote daemon
+type RemoteOptions struct {
+	Token              string // API token (server.auth)
+	CAFile             string // CA that signed the daemon certificate (default: system roots)
+	CertFile           string // Client certificate for mutual TLS
+	KeyFile            string // Client key for mutual TLS
+	ServerName         string // Overrides the name verified in the daemon certificate
+	InsecureSkipVerify bool   // Skip daemon certificate verification (testing only)
+}
+
+// Dial connects to a daemon by address. Addresses of the form
+// "tls://host:port" or "host:port" use the remote TLS transport; "unix://path"
+// or a plain file path use the local socket.
+func Dial(address string, opts RemoteOptions) (*Client, error) {
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		return Connect(strings.TrimPrefix(address, "unix://"))
+	case strings.HasPrefix(address, "tls://"):
+		return ConnectRemote(strings.TrimPrefix(address, "tls://"), opts)
+	case isRemoteAddress(address):
+		return ConnectRemote(address, opts)
+	default:
+		return Connect(address)
+	}
+}
+
+// isRemoteAddress reports whether an address without a scheme is host:port
+// rather than a socket path
+func isRemoteAddress(address string) bool {
+	if strings.ContainsRune(address, '/') {
+		return false
+	}
+	_, port, err := net.SplitHostPort(address)
+	return err == nil && port != ""
+}
+
 // Connect connects to the IPC server
 func Connect(socketPath string) (*Client, error) {
 	conn, err := net.Dial("unix", socketPath)
@@ -47,6 +98,45 @@ func Connect(socketPath string) (*Client, error) {
 		return nil, fmt.Errorf("failed to connect to daemon: %w", err)
 	}
 
+	client := newClient(conn)
+	if err := client.hello(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// ConnectRemote connects to a daemon's remote IPC listener over TLS and
+// authenticates with the configured token
+func ConnectRemote(address string, opts RemoteOptions) (*Client, error) {
+	tlsCfg, err := tlsutil.ClientConfig(opts.CAFile, opts.CertFile, opts.KeyFile, opts.ServerName, opts.InsecureSkipVerify)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	conn, err := tls.DialWithDialer(dialer, "tcp", address, tlsCfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to daemon at %s: %w", address, err)
+	}
+
+	client := newClient(conn)
+	if opts.Token != "" {
+		// Daemons before protocol version 2 require auth as the first message
+		if err := client.authenticate(opts.Token); err != nil {
+			client.Close()
+			return nil, err
+		}
+	}
+	if err := client.hello(); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// newClient wraps a connection and starts reading responses
+func newClient(conn net.Conn) *Client {
 	client := &Client{
 		conn:     conn,
 		encoder:  json.NewEncoder(conn),
@@ -57,13 +147,73 @@ func Connect(socketPath string) (*Client, error) {
 	}
 
 	// Set up scanner buffer
-	client.scanner.Buffer(make([]byte, 1024*1024), 1024*1024)
+	client.scanner.Buffer(make([]byte, 1024*1024), maxMessageSize)
 
 	// Start reading responses
 	client.wg.Add(1)
 	go client.readLoop()
 
-	return client, nil
+	return client
+}
+
+// authenticate sends the API token to a remote daemon
+func (c *Client) authenticate(token string) error {
+	respCh, reqID, err := c.sendRequest(MsgTypeAuth, AuthRequest{Token: token})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("authentication failed: %w", err)
+		}
+	case <-time.After(dialTimeout):
+		return fmt.Errorf("authentication timeout")
+	}
+
+	return nil
+}
+
+// hello negotiates the protocol version and learns the daemon's capabilities.
+// Daemons that predate the handshake are assumed to speak version 1.
+func (c *Client) hello() error {
+	respCh, reqID, err := c.sendRequest(MsgTypeHello, HelloRequest{Version: ProtocolVersion, Client: clientName})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			if resp.Code == "" && strings.HasPrefix(resp.Error, "unknown request type") {
+				c.version, c.capabilities = MinProtocolVersion, legacyCapabilities
+				return nil
+			}
+			return fmt.Errorf("protocol negotiation failed: %w", err)
+		}
+
+		var hello HelloResponse
+		if err := decodeData(resp.Data, &hello); err != nil {
+			return fmt.Errorf("invalid hello response: %w", err)
+		}
+		c.version, c.capabilities = hello.Version, hello.Capabilities
+		return nil
+	case <-time.After(dialTimeout):
+		return fmt.Errorf("protocol negotiation timeout")
+	}
+}
+
+// Version returns the protocol version negotiated with the daemon
+func (c *Client) Version() int {
+	return c.version
+}
+
+// Supports reports whether the daemon supports a request type or feature
+func (c *Client) Supports(capability string) bool {
+	return slices.Contains(c.capabilities, capability)
 }
 
 // readLoop reads responses from the server
@@ -78,14 +228,14 @@ func (c *Client) readLoop() {
 
 		switch resp.Type {
 		case MsgTypeProbeResult:
-			// Parse probe result and send to results channel
-			if data, ok := resp.Data.(map[string]interface{}); ok {
-				result := parseProbeResult(data)
-				select {
-				case c.resultCh <- result:
-				default:
-					// Channel full, skip
-				}
+			var result ProbeResultData
+			if err := decodeData(resp.Data, &result); err != nil {
+				continue
+			}
+			select {
+			case c.resultCh <- result:
+			default:
+				// Channel full, skip
 			}
 		default:
 			// Route response to waiting request by ID
@@ -108,32 +258,16 @@ func (c *Client) readLoop() {
 	close(c.resultCh)
 }
 
-// parseProbeResult parses a probe result from a map
-func parseProbeResult(data map[string]interface{}) ProbeResultData {
-	result := ProbeResultData{}
-	if target, ok := data["target"].(string); ok {
-		result.Target = target
-	}
-	if ts, ok := data["timestamp"].(string); ok {
-		result.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
-	}
-	if latency, ok := data["latency_ms"].(float64); ok {
-		result.LatencyMs = latency
-	}
-	if success, ok := data["success"].(bool); ok {
-		result.Success = success
-	}
-	if errStr, ok := data["error"].(string); ok {
-		result.Error = errStr
-	}
-	return result
-}
-
 // sendRequest sends a request and returns a channel to receive the response
 func (c *Client) sendRequest(reqType string, data any) (chan Response, string, error) {
 	reqID := generateRequestID()
 	respCh := make(chan Response, 1)
 
+	raw, err := encodeData(data)
+	if err != nil {
+		return nil, "", err
+	}
+
 	// Register pending request
 	c.pendingMu.Lock()
 	c.pending[reqID] = respCh
@@ -141,7 +275,7 @@ func (c *Client) sendRequest(reqType string, data any) (chan Response, string, e
 
 	// Send request
 	c.mu.Lock()
-	err := c.encoder.Encode(Request{ID: reqID, Type: reqType, Data: data})
+	err = c.encoder.Encode(Request{ID: reqID, Type: reqType, Data: raw})
 	c.mu.Unlock()
 
 	if err != nil {
@@ -172,8 +306,8 @@ func (c *Client) Subscribe() error {
 
 	select {
 	case resp := <-respCh:
-		if resp.Type == MsgTypeError {
-			return fmt.Errorf("subscribe failed: %s", resp.Error)
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("subscribe failed: %w", err)
 		}
 	case <-time.After(5 * time.Second):
 		return fmt.Errorf("subscribe timeout")
@@ -197,35 +331,15 @@ func (c *Client) GetTargets() ([]config.Target, error) {
 
 	select {
 	case resp := <-respCh:
-		if resp.Type == MsgTypeError {
-			return nil, fmt.Errorf("get targets failed: %s", resp.Error)
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get targets failed: %w", err)
 		}
 		if resp.Type == MsgTypeTargets {
-			// Parse targets from response
-			if data, ok := resp.Data.(map[string]interface{}); ok {
-				if targetsRaw, ok := data["targets"].([]interface{}); ok {
-					targets := make([]config.Target, 0, len(targetsRaw))
-					for _, t := range targetsRaw {
-						if tmap, ok := t.(map[string]interface{}); ok {
-							target := config.Target{}
-							if name, ok := tmap["name"].(string); ok {
-								target.Name = name
-							}
-							if host, ok := tmap["host"].(string); ok {
-								target.Host = host
-							}
-							if port, ok := tmap["port"].(float64); ok {
-								target.Port = int(port)
-							}
-							if probe, ok := tmap["probe_type"].(string); ok {
-								target.Probe = probe
-							}
-							targets = append(targets, target)
-						}
-					}
-					return targets, nil
-				}
+			var targets TargetsResponse
+			if err := decodeData(resp.Data, &targets); err != nil {
+				return nil, fmt.Errorf("invalid targets: %w", err)
 			}
+			return targets.Targets, nil
 		}
 		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
 	case <-time.After(5 * time.Second):
@@ -233,8 +347,18 @@ func (c *Client) GetTargets() ([]config.Target, error) {
 	}
 }
 
-// GetStats retrieves statistics for a target
+// GetStats retrieves statistics for a target (nil before its first sample)
 func (c *Client) GetStats(targetName string) (*storage.Stats, error) {
+	resp, err := c.GetTargetStats(targetName)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+// GetTargetStats retrieves statistics for a target along with its active
+// anomaly, if the daemon reports anomalies
+func (c *Client) GetTargetStats(targetName string) (*StatsResponse, error) {
 	respCh, reqID, err := c.sendRequest(MsgTypeGetStats, GetStatsRequest{Target: targetName})
 	if err != nil {
 		return nil, err
@@ -243,47 +367,15 @@ func (c *Client) GetStats(targetName string) (*storage.Stats, error) {
 
 	select {
 	case resp := <-respCh:
-		if resp.Type == MsgTypeError {
-			return nil, fmt.Errorf("get stats failed: %s", resp.Error)
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get stats failed: %w", err)
 		}
 		if resp.Type == MsgTypeStats {
-			// Parse stats from response
-			if data, ok := resp.Data.(map[string]interface{}); ok {
-				if statsRaw, ok := data["stats"].(map[string]interface{}); ok {
-					stats := &storage.Stats{}
-					if v, ok := statsRaw["target"].(string); ok {
-						stats.Target = v
-					}
-					if v, ok := statsRaw["min_ms"].(float64); ok {
-						stats.MinMs = v
-					}
-					if v, ok := statsRaw["max_ms"].(float64); ok {
-						stats.MaxMs = v
-					}
-					if v, ok := statsRaw["avg_ms"].(float64); ok {
-						stats.AvgMs = v
-					}
-					if v, ok := statsRaw["median_ms"].(float64); ok {
-						stats.MedianMs = v
-					}
-					if v, ok := statsRaw["p95_ms"].(float64); ok {
-						stats.P95Ms = v
-					}
-					if v, ok := statsRaw["stddev_ms"].(float64); ok {
-						stats.StdDevMs = v
-					}
-					if v, ok := statsRaw["loss_pct"].(float64); ok {
-						stats.LossPct = v
-					}
-					if v, ok := statsRaw["sample_count"].(float64); ok {
-						stats.SampleCount = int(v)
-					}
-					if v, ok := statsRaw["last_ms"].(float64); ok {
-						stats.LastMs = v
-					}
-					return stats, nil
-				}
+			var stats StatsResponse
+			if err := decodeData(resp.Data, &stats); err != nil {
+				return nil, fmt.Errorf("invalid stats: %w", err)
 			}
+			return &stats, nil
 		}
 		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
 	case <-time.After(5 * time.Second):
@@ -293,10 +385,10 @@ func (c *Client) GetStats(targetName string) (*storage.Stats, error) {
 
 // GetHistory retrieves historical data for a target
 func (c *Client) GetHistory(targetName string, from, to time.Time) ([]storage.DataPoint, error) {
-	respCh, reqID, err := c.sendRequest(MsgTypeGetHistory, map[string]interface{}{
-		"target": targetName,
-		"from":   from.Format(time.RFC3339),
-		"to":     to.Format(time.RFC3339),
+	respCh, reqID, err := c.sendRequest(MsgTypeGetHistory, GetHistoryRequest{
+		Target: targetName,
+		From:   from,
+		To:     to,
 	})
 	if err != nil {
 		return nil, err
@@ -305,40 +397,27 @@ func (c *Client) GetHistory(targetName string, from, to time.Time) ([]storage.Da
 
 	select {
 	case resp := <-respCh:
-		if resp.Type == MsgTypeError {
-			return nil, fmt.Errorf("get history failed: %s", resp.Error)
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get history failed: %w", err)
 		}
 		if resp.Type == MsgTypeHistory {
-			// Parse data points from response
-			if data, ok := resp.Data.(map[string]interface{}); ok {
-				if pointsRaw, ok := data["data_points"].([]interface{}); ok {
-					points := make([]storage.DataPoint, 0, len(pointsRaw))
-					for _, p := range pointsRaw {
-						if pmap, ok := p.(map[string]interface{}); ok {
-							point := storage.DataPoint{}
-							if ts, ok := pmap["timestamp"].(string); ok {
-								point.Timestamp, _ = time.Parse(time.RFC3339Nano, ts)
-							}
-							// Handle null values (packet loss) as NaN
-							if v, ok := pmap["value"].(float64); ok {
-								point.Value = v
-							} else {
-								// value is null or missing - treat as packet loss
-								point.Value = math.NaN()
-							}
-							// Handle loss field (nil = no data, 0 = success, 1 = failure)
-							if l, ok := pmap["loss"].(float64); ok {
-								point.Loss = l
-							} else {
-								// loss is null or missing - means no data collected
-								point.Loss = math.NaN()
-							}
-							points = append(points, point)
-						}
-					}
-					return points, nil
+			var history HistoryResponse
+			if err := decodeData(resp.Data, &history); err != nil {
+				return nil, fmt.Errorf("invalid history: %w", err)
+			}
+
+			// Missing values (packet loss) and missing loss (no data) become NaN
+			points := make([]storage.DataPoint, len(history.DataPoints))
+			for i, p := range history.DataPoints {
+				points[i] = storage.DataPoint{Timestamp: p.Timestamp, Value: math.NaN(), Loss: math.NaN()}
+				if p.Value != nil {
+					points[i].Value = *p.Value
+				}
+				if p.Loss != nil {
+					points[i].Loss = *p.Loss
 				}
 			}
+			return points, nil
 		}
 		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
 	case <-time.After(10 * time.Second):
@@ -346,6 +425,218 @@ func (c *Client) GetHistory(targetName string, from, to time.Time) ([]storage.Da
 	}
 }
 
+// ExportHistory retrieves a target's complete multi-resolution history
+func (c *Client) ExportHistory(targetName string) (*storage.Archive, error) {
+	respCh, reqID, err := c.sendRequest(MsgTypeExport, ExportRequest{Target: targetName})
+	if err != nil {
+		return nil, err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("export history failed: %w", err)
+		}
+		if resp.Type == MsgTypeArchive {
+			var archive storage.Archive
+			if err := decodeData(resp.Data, &archive); err != nil {
+				return nil, fmt.Errorf("invalid archive: %w", err)
+			}
+			return &archive, nil
+		}
+		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
+	case <-time.After(60 * time.Second):
+		return nil, fmt.Errorf("export history timeout")
+	}
+}
+
+// ImportHistory writes an archive into a target's history on the daemon
+func (c *Client) ImportHistory(targetName string, archive *storage.Archive, mode storage.ImportMode) error {
+	respCh, reqID, err := c.sendRequest(MsgTypeImport, ImportRequest{
+		Target:  targetName,
+		Mode:    mode,
+		Archive: archive,
+	})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("import history failed: %w", err)
+		}
+		return nil
+	case <-time.After(5 * time.Minute):
+		return fmt.Errorf("import history timeout")
+	}
+}
+
+// RenameTarget renames a target on the daemon, moving its history along
+func (c *Client) RenameTarget(oldName, newName string) error {
+	respCh, reqID, err := c.sendRequest(MsgTypeRename, RenameRequest{Target: oldName, NewName: newName})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("rename target failed: %w", err)
+		}
+		return nil
+	case <-time.After(30 * time.Second):
+		return fmt.Errorf("rename target timeout")
+	}
+}
+
+// GetStorageUsage retrieves disk usage per data file, including orphans
+func (c *Client) GetStorageUsage() (*storage.Usage, error) {
+	respCh, reqID, err := c.sendRequest(MsgTypeGetStorage, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get storage usage failed: %w", err)
+		}
+		if resp.Type == MsgTypeStorage {
+			var usage storage.Usage
+			if err := decodeData(resp.Data, &usage); err != nil {
+				return nil, fmt.Errorf("invalid storage usage: %w", err)
+			}
+			return &usage, nil
+		}
+		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
+	case <-time.After(10 * time.Second):
+		return nil, fmt.Errorf("get storage usage timeout")
+	}
+}
+
+// GetEvents retrieves incidents from the daemon's incident log, newest first
+func (c *Client) GetEvents(req GetEventsRequest) ([]storage.Incident, error) {
+	respCh, reqID, err := c.sendRequest(MsgTypeGetEvents, req)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get events failed: %w", err)
+		}
+		if resp.Type == MsgTypeEvents {
+			var events EventsResponse
+			if err := decodeData(resp.Data, &events); err != nil {
+				return nil, fmt.Errorf("invalid events: %w", err)
+			}
+			return events.Events, nil
+		}
+		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("get events timeout")
+	}
+}
+
+// GetSilences retrieves the ad-hoc silences and the targets currently in
+// maintenance
+func (c *Client) GetSilences() (*SilencesResponse, error) {
+	respCh, reqID, err := c.sendRequest(MsgTypeGetSilences, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("get silences failed: %w", err)
+		}
+		if resp.Type == MsgTypeSilences {
+			var silences SilencesResponse
+			if err := decodeData(resp.Data, &silences); err != nil {
+				return nil, fmt.Errorf("invalid silences: %w", err)
+			}
+			return &silences, nil
+		}
+		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("get silences timeout")
+	}
+}
+
+// AddSilence suppresses alerts of the matching targets, returning the
+// created silence
+func (c *Client) AddSilence(req AddSilenceRequest) (*collector.Silence, error) {
+	respCh, reqID, err := c.sendRequest(MsgTypeAddSilence, req)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return nil, fmt.Errorf("add silence failed: %w", err)
+		}
+		if resp.Type == MsgTypeSilence {
+			var silence collector.Silence
+			if err := decodeData(resp.Data, &silence); err != nil {
+				return nil, fmt.Errorf("invalid silence: %w", err)
+			}
+			return &silence, nil
+		}
+		return nil, fmt.Errorf("unexpected response type: %s", resp.Type)
+	case <-time.After(5 * time.Second):
+		return nil, fmt.Errorf("add silence timeout")
+	}
+}
+
+// RemoveSilence deletes an ad-hoc silence
+func (c *Client) RemoveSilence(id string) error {
+	respCh, reqID, err := c.sendRequest(MsgTypeRmSilence, RemoveSilenceRequest{ID: id})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("remove silence failed: %w", err)
+		}
+		return nil
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("remove silence timeout")
+	}
+}
+
+// RemoveOrphan deletes an orphaned data file, or archives it when archive is true
+func (c *Client) RemoveOrphan(id string, archive bool) error {
+	respCh, reqID, err := c.sendRequest(MsgTypeRemove, RemoveOrphanRequest{ID: id, Archive: archive})
+	if err != nil {
+		return err
+	}
+	defer c.cleanupRequest(reqID)
+
+	select {
+	case resp := <-respCh:
+		if err := resp.err(); err != nil {
+			return fmt.Errorf("remove orphan failed: %w", err)
+		}
+		return nil
+	case <-time.After(10 * time.Second):
+		return fmt.Errorf("remove orphan timeout")
+	}
+}
+
 // Close closes the connection
 func (c *Client) Close() error {
 	c.mu.Lock()
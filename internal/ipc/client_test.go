@@ -0,0 +1,26 @@
+package ipc
+
+import "testing"
+
+func TestIsRemoteAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    bool
+	}{
+		{"/tmp/pulse.sock", false},
+		{"pulse.sock", false},
+		{"./run/pulse:1.sock", false},
+		{"probe-01:7443", true},
+		{"10.0.0.5:7443", true},
+		{"[::1]:7443", true},
+		{":7443", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			if got := isRemoteAddress(tt.address); got != tt.want {
+				t.Errorf("isRemoteAddress(%q) = %v, want %v", tt.address, got, tt.want)
+			}
+		})
+	}
+}
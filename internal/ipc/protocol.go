@@ -1,49 +1,237 @@
 package ipc
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 
+	"github.com/wellsgz/pulse/internal/collector"
 	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
 	"github.com/wellsgz/pulse/internal/storage"
 )
 
+// ProtocolVersion is the IPC protocol version spoken by this build. Servers
+// accept clients speaking any version from MinProtocolVersion up; clients that
+// never send a hello are treated as MinProtocolVersion.
+//
+// Version history:
+//
+//	1: untyped requests, plain error strings (no hello)
+//	2: hello handshake, capabilities, error codes, strict request decoding
+const (
+	ProtocolVersion    = 2
+	MinProtocolVersion = 1
+)
+
 // Message types for IPC protocol
 const (
+	MsgTypeHello       = "hello"
 	MsgTypeSubscribe   = "subscribe"
 	MsgTypeUnsubscribe = "unsubscribe"
 	MsgTypeGetTargets  = "get_targets"
 	MsgTypeGetStats    = "get_stats"
 	MsgTypeGetHistory  = "get_history"
+	MsgTypeExport      = "export_history"
+	MsgTypeImport      = "import_history"
+	MsgTypeRename      = "rename_target"
+	MsgTypeGetStorage  = "get_storage"
+	MsgTypeRemove      = "remove_orphan"
+	MsgTypeGetEvents   = "get_events"
+	MsgTypeGetSilences = "get_silences"
+	MsgTypeAddSilence  = "add_silence"
+	MsgTypeRmSilence   = "remove_silence"
+	MsgTypeAuth        = "auth"
 	MsgTypeProbeResult = "probe_result"
 	MsgTypeTargets     = "targets"
 	MsgTypeStats       = "stats"
 	MsgTypeHistory     = "history"
+	MsgTypeArchive     = "archive"
+	MsgTypeStorage     = "storage"
+	MsgTypeEvents      = "events"
+	MsgTypeSilences    = "silences"
+	MsgTypeSilence     = "silence"
 	MsgTypeError       = "error"
 	MsgTypeOK          = "ok"
 )
 
-// Request is the base request structure
+// legacyCapabilities is what a version 1 daemon (one that does not know
+// hello) supports
+var legacyCapabilities = []string{
+	MsgTypeAuth,
+	MsgTypeSubscribe,
+	MsgTypeUnsubscribe,
+	MsgTypeGetTargets,
+	MsgTypeGetStats,
+	MsgTypeGetHistory,
+	MsgTypeExport,
+	MsgTypeImport,
+	MsgTypeRename,
+	MsgTypeGetStorage,
+	MsgTypeRemove,
+}
+
+// Optional features reported as capabilities
+const (
+	// CapBurstStats: probe_result messages carry the full burst statistics
+	// and individual RTTs
+	CapBurstStats = "burst_stats"
+
+	// CapAnomalies: stats responses carry the target's active anomaly
+	CapAnomalies = "anomalies"
+)
+
+// capabilities lists what this server supports, reported in the hello
+// response. Every request type is a capability; optional features are added
+// under their own names.
+var capabilities = append(slices.Clone(legacyCapabilities),
+	MsgTypeHello,
+	MsgTypeGetEvents,
+	MsgTypeGetSilences,
+	MsgTypeAddSilence,
+	MsgTypeRmSilence,
+	CapBurstStats,
+	CapAnomalies,
+)
+
+// ErrorCode classifies an error response so clients can react without
+// parsing the message
+type ErrorCode string
+
+// Error codes sent in Response.Code
+const (
+	CodeInvalidRequest     ErrorCode = "invalid_request"     // Malformed message, or unknown or mistyped fields
+	CodeUnknownType        ErrorCode = "unknown_type"        // Request type not supported by this server
+	CodeUnsupportedVersion ErrorCode = "unsupported_version" // No protocol version in common
+	CodeUnauthenticated    ErrorCode = "unauthenticated"     // Missing or invalid token
+	CodeForbidden          ErrorCode = "forbidden"           // Token role does not allow the request
+	CodeNotFound           ErrorCode = "not_found"           // Unknown target or file
+	CodeConflict           ErrorCode = "conflict"            // Target already has history, or name taken
+	CodeAgentManaged       ErrorCode = "agent_managed"       // Target is probed by agents and changed in the config file
+	CodeUnavailable        ErrorCode = "unavailable"         // Collector or storage not available
+	CodeFailed             ErrorCode = "failed"              // The operation itself failed
+)
+
+// Error is an error response from the server
+type Error struct {
+	Code    ErrorCode
+	Message string
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// maxMessageSize is the largest newline-delimited message accepted on the socket
+// (history archives can be several megabytes)
+const maxMessageSize = 64 * 1024 * 1024
+
+// maxRequestSize is the largest request accepted before authentication, and
+// from clients that cannot import history
+const maxRequestSize = 64 * 1024
+
+// Request is the base request structure. Data holds the request struct for
+// the type (e.g. GetStatsRequest for get_stats) and may be omitted when the
+// type takes no parameters.
 type Request struct {
-	ID   string `json:"id,omitempty"` // Unique request ID for response correlation
-	Type string `json:"type"`
-	Data any    `json:"data,omitempty"`
+	ID   string          `json:"id,omitempty"` // Unique request ID for response correlation
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data,omitempty"`
 }
 
-// Response is the base response structure
+// Response is the base response structure. Errors have type "error", a
+// human-readable Error and, from protocol version 2, a Code.
 type Response struct {
-	ID    string `json:"id,omitempty"` // Echo of request ID for correlation
-	Type  string `json:"type"`
-	Data  any    `json:"data,omitempty"`
-	Error string `json:"error,omitempty"`
+	ID    string          `json:"id,omitempty"` // Echo of request ID for correlation
+	Type  string          `json:"type"`
+	Data  json.RawMessage `json:"data,omitempty"`
+	Error string          `json:"error,omitempty"`
+	Code  ErrorCode       `json:"code,omitempty"`
+}
+
+// err returns the error carried by an error response, or nil
+func (r *Response) err() error {
+	if r.Type != MsgTypeError {
+		return nil
+	}
+	return &Error{Code: r.Code, Message: r.Error}
 }
 
-// ProbeResultData represents a probe result for IPC
+// HelloRequest opens a session by announcing the client's protocol version.
+// It is optional, and may be sent before a remote client authenticates.
+type HelloRequest struct {
+	Version int    `json:"version"`          // Highest protocol version the client speaks
+	Client  string `json:"client,omitempty"` // Client name and version, for logs
+}
+
+// HelloResponse reports the negotiated version and the server's capabilities
+type HelloResponse struct {
+	Version      int      `json:"version"`       // Version used for the rest of the session
+	MinVersion   int      `json:"min_version"`   // Oldest version the server accepts
+	MaxVersion   int      `json:"max_version"`   // Newest version the server speaks
+	Capabilities []string `json:"capabilities"`  // Supported request types and features
+	AuthRequired bool     `json:"auth_required"` // An auth request must follow before anything else
+}
+
+// ProbeResultData represents a probe result for IPC. The burst fields are
+// sent by daemons with the burst_stats capability and zero otherwise.
 type ProbeResultData struct {
 	Target    string    `json:"target"`
 	Timestamp time.Time `json:"timestamp"`
-	LatencyMs float64   `json:"latency_ms"`
+	LatencyMs float64   `json:"latency_ms"` // Median of the burst, -1 for total loss
 	Success   bool      `json:"success"`
 	Error     string    `json:"error,omitempty"`
+
+	MinMs     float64   `json:"min_ms"`
+	MaxMs     float64   `json:"max_ms"`
+	AvgMs     float64   `json:"avg_ms"`
+	JitterMs  float64   `json:"jitter_ms"`
+	LossPct   float64   `json:"loss_pct"`
+	PingsSent int       `json:"pings_sent"`
+	PingsRecv int       `json:"pings_recv"`
+	RTTsMs    []float64 `json:"rtts_ms"` // Individual round-trip times
+}
+
+// newProbeResultData converts a probe result to its IPC form
+func newProbeResultData(r probe.ProbeResult) ProbeResultData {
+	return ProbeResultData{
+		Target:    r.Target,
+		Timestamp: r.Timestamp,
+		LatencyMs: r.LatencyMs,
+		Success:   r.Success,
+		Error:     r.Error,
+		MinMs:     r.MinMs,
+		MaxMs:     r.MaxMs,
+		AvgMs:     r.AvgMs,
+		JitterMs:  r.JitterMs,
+		LossPct:   r.LossPct,
+		PingsSent: r.PingsSent,
+		PingsRecv: r.PingsRecv,
+		RTTsMs:    r.RTTsMs,
+	}
+}
+
+// ProbeResult converts the IPC form back to a probe result
+func (d ProbeResultData) ProbeResult() probe.ProbeResult {
+	return probe.ProbeResult{
+		Target:    d.Target,
+		Timestamp: d.Timestamp,
+		Latency:   time.Duration(d.LatencyMs * float64(time.Millisecond)),
+		LatencyMs: d.LatencyMs,
+		Success:   d.Success,
+		Error:     d.Error,
+		MinMs:     d.MinMs,
+		MaxMs:     d.MaxMs,
+		AvgMs:     d.AvgMs,
+		JitterMs:  d.JitterMs,
+		LossPct:   d.LossPct,
+		PingsSent: d.PingsSent,
+		PingsRecv: d.PingsRecv,
+		RTTsMs:    d.RTTsMs,
+	}
 }
 
 // GetStatsRequest is the request for stats
@@ -58,6 +246,62 @@ type GetHistoryRequest struct {
 	To     time.Time `json:"to"`
 }
 
+// ExportRequest is the request for a target's complete history
+type ExportRequest struct {
+	Target string `json:"target"`
+}
+
+// ImportRequest imports an archive into a target's history
+type ImportRequest struct {
+	Target  string             `json:"target"`
+	Mode    storage.ImportMode `json:"mode"`
+	Archive *storage.Archive   `json:"archive"`
+}
+
+// RenameRequest renames a target, keeping its history under its storage id
+type RenameRequest struct {
+	Target  string `json:"target"`
+	NewName string `json:"new_name"`
+}
+
+// AuthRequest authenticates a network client; it must be its first message
+type AuthRequest struct {
+	Token string `json:"token"`
+}
+
+// GetEventsRequest selects incidents from the incident log. Zero fields do
+// not filter.
+type GetEventsRequest struct {
+	Target  string    `json:"target,omitempty"`
+	Kind    string    `json:"kind,omitempty"`
+	From    time.Time `json:"from,omitzero"`
+	To      time.Time `json:"to,omitzero"`
+	Ongoing bool      `json:"ongoing,omitempty"`
+	Limit   int       `json:"limit,omitempty"` // Newest first (default: 100)
+}
+
+// AddSilenceRequest suppresses the alerts of the targets matching exactly one
+// of target, group and tag until End. A zero Start means now.
+type AddSilenceRequest struct {
+	Target  string    `json:"target,omitempty"`
+	Group   string    `json:"group,omitempty"`
+	Tag     string    `json:"tag,omitempty"`
+	Start   time.Time `json:"start,omitzero"`
+	End     time.Time `json:"end"`
+	Comment string    `json:"comment,omitempty"`
+}
+
+// RemoveSilenceRequest deletes an ad-hoc silence
+type RemoveSilenceRequest struct {
+	ID string `json:"id"`
+}
+
+// RemoveOrphanRequest deletes or archives an orphaned data file
+type RemoveOrphanRequest struct {
+	ID      string `json:"id"`
+	Archive bool   `json:"archive"`
+}
+
 // TargetsResponse contains target configurations
 type TargetsResponse struct {
 	Targets []config.Target `json:"targets"`
@@ -65,14 +309,30 @@ type TargetsResponse struct {
 
 // StatsResponse contains statistics for a target
 type StatsResponse struct {
-	Target string         `json:"target"`
-	Stats  *storage.Stats `json:"stats"`
+	Target  string             `json:"target"`
+	Stats   *storage.Stats     `json:"stats"`
+	Anomaly *collector.Anomaly `json:"anomaly,omitempty"` // Active deviation from the baseline
+
+	Maintenance *collector.Maintenance `json:"maintenance,omitempty"` // Alerts suppressed until its end
+	Dependency  *collector.Dependency  `json:"dependency,omitempty"`  // State of the target it depends on
+}
+
+// SilencesResponse contains the ad-hoc silences and the targets currently in
+// maintenance
+type SilencesResponse struct {
+	Silences []collector.Silence     `json:"silences"`
+	Active   []collector.Maintenance `json:"active"`
+}
+
+// EventsResponse contains incidents, newest first
+type EventsResponse struct {
+	Events []storage.Incident `json:"events"`
 }
 
 // HistoryResponse contains historical data points
 type HistoryResponse struct {
-	Target     string              `json:"target"`
-	DataPoints []storage.DataPoint `json:"data_points"`
+	Target     string         `json:"target"`
+	DataPoints []IPCDataPoint `json:"data_points"`
 }
 
 // IPCDataPoint is a JSON-safe data point that handles NaN values
@@ -82,3 +342,38 @@ type IPCDataPoint struct {
 	Value     *float64  `json:"value"` // nil for NaN/missing values
 	Loss      *float64  `json:"loss"`  // nil for no data, 0=success, 1=failure (or 0.0-1.0 for aggregated)
 }
+
+// encodeData marshals message data; nil data is omitted from the message
+func encodeData(data any) (json.RawMessage, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return json.Marshal(data)
+}
+
+// decodeRequest strictly decodes request data into a typed struct: unknown
+// fields and trailing data are rejected so mistakes in third-party clients
+// surface as errors instead of being ignored. Empty data leaves v unchanged.
+func decodeRequest(data json.RawMessage, v any) error {
+	if len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("unexpected data after request")
+	}
+	return nil
+}
+
+// decodeData decodes response data into a typed struct. Unknown fields are
+// ignored so clients keep working against newer servers.
+func decodeData(data json.RawMessage, v any) error {
+	if len(data) == 0 {
+		return fmt.Errorf("response has no data")
+	}
+	return json.Unmarshal(data, v)
+}
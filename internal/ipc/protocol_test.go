@@ -0,0 +1,178 @@
+package ipc
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/wellsgz/pulse/internal/auth"
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestDecodeRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{"valid", `{"target":"Google DNS"}`, "Google DNS", false},
+		{"empty", ``, "", false},
+		{"null", `null`, "", false},
+		{"unknown field", `{"target":"Google DNS","name":"x"}`, "", true},
+		{"wrong type", `{"target":5}`, "", true},
+		{"trailing data", `{"target":"a"}{"target":"b"}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req GetStatsRequest
+			err := decodeRequest([]byte(tt.data), &req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeRequest(%s) error = %v, wantErr %v", tt.data, err, tt.wantErr)
+			}
+			if !tt.wantErr && req.Target != tt.want {
+				t.Errorf("Target = %q, want %q", req.Target, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiateVersion(t *testing.T) {
+	tests := []struct {
+		client int
+		want   int
+		ok     bool
+	}{
+		{0, 0, false},
+		{MinProtocolVersion, MinProtocolVersion, true},
+		{ProtocolVersion, ProtocolVersion, true},
+		{ProtocolVersion + 5, ProtocolVersion, true},
+	}
+
+	for _, tt := range tests {
+		got, ok := negotiateVersion(tt.client)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("negotiateVersion(%d) = %d, %v, want %d, %v", tt.client, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestHelloAndErrorCodes(t *testing.T) {
+	server := NewServer(filepath.Join(t.TempDir(), "pulse.sock"))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer server.Stop()
+
+	client, err := Connect(server.socketPath)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	if client.Version() != ProtocolVersion {
+		t.Errorf("Version() = %d, want %d", client.Version(), ProtocolVersion)
+	}
+	if !client.Supports(MsgTypeHello) || client.Supports("no_such_feature") {
+		t.Errorf("unexpected capabilities: %v", client.capabilities)
+	}
+
+	// No collector: requests fail with a typed error
+	_, err = client.GetTargets()
+	var ipcErr *Error
+	if !errors.As(err, &ipcErr) || ipcErr.Code != CodeUnavailable {
+		t.Errorf("GetTargets() error = %v, want code %s", err, CodeUnavailable)
+	}
+}
+
+func TestCollectorErrorCodes(t *testing.T) {
+	cfg := &config.Config{
+		Master: config.MasterConfig{Agents: []string{"tokyo"}},
+		Targets: []config.Target{
+			{Name: "google", Host: "8.8.8.8", Probe: "icmp", Agents: []string{"tokyo"}},
+			{Name: "cloudflare", Host: "1.1.1.1", Probe: "icmp"},
+			{Name: "quad9", Host: "9.9.9.9", Probe: "icmp"},
+		},
+	}
+	server := NewServer(filepath.Join(t.TempDir(), "pulse.sock"))
+	server.SetCollector(collector.NewCollector(cfg, nil, storage.NewMemoryBuffer(10)))
+	if err := server.Start(); err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	defer server.Stop()
+
+	client, err := Connect(server.socketPath)
+	if err != nil {
+		t.Fatalf("Connect: %v", err)
+	}
+	defer client.Close()
+
+	tests := []struct {
+		name string
+		call func() error
+		want ErrorCode
+	}{
+		{
+			name: "export without storage",
+			call: func() error { _, err := client.ExportHistory("cloudflare"); return err },
+			want: CodeUnavailable,
+		},
+		{
+			name: "rename to a taken name",
+			call: func() error { return client.RenameTarget("cloudflare", "quad9") },
+			want: CodeConflict,
+		},
+		{
+			name: "rename an agent series",
+			call: func() error { return client.RenameTarget("google@tokyo", "google-tokyo") },
+			want: CodeAgentManaged,
+		},
+		{
+			name: "rename a target probed by agents",
+			call: func() error { return client.RenameTarget("google", "google-dns") },
+			want: CodeAgentManaged,
+		},
+		{
+			name: "rename without a new name",
+			call: func() error { return client.RenameTarget("cloudflare", "") },
+			want: CodeInvalidRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ipcErr *Error
+			if err := tt.call(); !errors.As(err, &ipcErr) || ipcErr.Code != tt.want {
+				t.Errorf("error = %v, want code %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		reqType string
+		want    auth.Role
+	}{
+		{MsgTypeHello, auth.RoleRead},
+		{MsgTypeGetHistory, auth.RoleRead},
+		{MsgTypeExport, auth.RoleRead},
+		{MsgTypeGetSilences, auth.RoleRead},
+		{MsgTypeImport, auth.RoleAdmin},
+		{MsgTypeRename, auth.RoleAdmin},
+		{MsgTypeRemove, auth.RoleAdmin},
+		{MsgTypeAddSilence, auth.RoleAdmin},
+		{"no_such_type", auth.RoleAdmin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.reqType, func(t *testing.T) {
+			if got := requiredRole(tt.reqType); got != tt.want {
+				t.Errorf("requiredRole(%q) = %s, want %s", tt.reqType, got, tt.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package ipc
 
 import (
 	"bufio"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"math"
@@ -11,15 +13,34 @@ import (
 	"sync"
 	"time"
 
+	"github.com/wellsgz/pulse/internal/auth"
 	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/config"
 	"github.com/wellsgz/pulse/internal/probe"
+	"github.com/wellsgz/pulse/internal/storage"
+	"github.com/wellsgz/pulse/internal/tlsutil"
 )
 
-// Server handles Unix socket connections from TUI clients
+// authTimeout is how long a network client has to authenticate
+const authTimeout = 10 * time.Second
+
+// defaultEventLimit is how many incidents get_events returns without a limit
+const defaultEventLimit = 100
+
+// clientSendBuffer is how many messages may wait for a slow client before it
+// is disconnected
+const clientSendBuffer = 256
+
+// writeTimeout is how long a write to a network client may block
+const writeTimeout = 10 * time.Second
+
+// Server handles Unix socket and remote TLS connections from TUI clients
 type Server struct {
-	socketPath string
-	listener   net.Listener
-	collector  *collector.Collector
+	socketPath     string
+	listener       net.Listener
+	remoteListener net.Listener
+	authenticator  *auth.Authenticator
+	collector      *collector.Collector
 
 	clients   map[*serverClient]struct{}
 	clientsMu sync.RWMutex
@@ -30,12 +51,19 @@ type Server struct {
 	mu     sync.Mutex
 }
 
-// serverClient represents a connected client
+// serverClient represents a connected client. Messages are queued on send
+// and written by the client's own writer goroutine, so a stalled client
+// cannot hold up others.
 type serverClient struct {
 	conn       net.Conn
 	server     *Server
 	encoder    *json.Encoder
+	send       chan Response
+	done       chan struct{} // closed when the client is disconnected
 	subscribed bool
+	remote     bool
+	role       auth.Role // Empty until a remote client authenticates
+	name       string    // Token name of an authenticated remote client
 	mu         sync.Mutex
 }
 
@@ -82,17 +110,63 @@ func (s *Server) Start() error {
 
 	// Accept connections
 	s.wg.Add(1)
-	go s.acceptLoop()
+	go s.acceptLoop(s.listener, false)
+
+	return nil
+}
+
+// StartRemote begins listening for TLS connections from remote TUIs on
+// server.remote.address. Clients authenticate with an API token in their
+// first message, or with a client certificate when mutual TLS is configured
+// and no tokens are. It does nothing when no address is configured.
+// Must be called after Start.
+func (s *Server) StartRemote(cfg *config.ServerConfig) error {
+	if cfg.Remote.Address == "" {
+		return nil
+	}
+
+	tlsCfg := cfg.RemoteTLS()
+	if !tlsCfg.Enabled() {
+		return fmt.Errorf("remote IPC requires TLS")
+	}
+	reloader, err := tlsutil.NewReloader(tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile, tlsCfg.ReloadInterval)
+	if err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
+	authenticator, err := auth.New(cfg.Auth)
+	if err != nil {
+		return fmt.Errorf("auth: %w", err)
+	}
+	if !authenticator.Enabled() && !reloader.MutualTLS() {
+		return fmt.Errorf("remote IPC requires API tokens or client certificates")
+	}
+	s.authenticator = authenticator
+
+	listener, err := tls.Listen("tcp", cfg.Remote.Address, reloader.ServerConfig())
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.Remote.Address, err)
+	}
+	s.remoteListener = listener
+
+	mode := "TLS"
+	if reloader.MutualTLS() {
+		mode = "mutual TLS"
+	}
+	log.Printf("[IPC] Remote server listening on %s (%s)", cfg.Remote.Address, mode)
+
+	s.wg.Add(1)
+	go s.acceptLoop(listener, true)
 
 	return nil
 }
 
 // acceptLoop accepts new connections
-func (s *Server) acceptLoop() {
+func (s *Server) acceptLoop(listener net.Listener, remote bool) {
 	defer s.wg.Done()
 
 	for {
-		conn, err := s.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
 			select {
 			case <-s.ctx:
@@ -107,6 +181,17 @@ func (s *Server) acceptLoop() {
 			conn:    conn,
 			server:  s,
 			encoder: json.NewEncoder(conn),
+			send:    make(chan Response, clientSendBuffer),
+			done:    make(chan struct{}),
+			remote:  remote,
+			role:    auth.RoleAdmin, // Local socket access is controlled by file permissions
+		}
+		if remote {
+			client.role = ""
+			if !s.authenticator.Enabled() {
+				// Mutual TLS only: the verified client certificate is the credential
+				client.role = auth.RoleAdmin
+			}
 		}
 
 		s.clientsMu.Lock()
@@ -121,20 +206,61 @@ func (s *Server) acceptLoop() {
 // handleClient handles a client connection
 func (s *Server) handleClient(client *serverClient) {
 	defer s.wg.Done()
+
+	written := make(chan struct{})
+	go func() {
+		client.writeLoop()
+		close(written)
+	}()
 	defer func() {
 		s.clientsMu.Lock()
 		delete(s.clients, client)
 		s.clientsMu.Unlock()
+
+		// Let the writer flush queued responses (e.g. an auth error) first
+		close(client.done)
+		<-written
 		client.conn.Close()
 	}()
 
+	// Only admin sessions may send large requests (archive imports). The
+	// limit is raised once a remote client authenticates as admin.
+	limit := maxRequestSize
+	if client.role == auth.RoleAdmin {
+		limit = maxMessageSize
+	}
 	scanner := bufio.NewScanner(client.conn)
-	scanner.Buffer(make([]byte, 1024*1024), 1024*1024) // 1MB buffer
+	scanner.Buffer(make([]byte, 4096), maxMessageSize)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if len(token) > limit || (token == nil && len(data) > limit) {
+			return 0, nil, bufio.ErrTooLong
+		}
+		return advance, token, err
+	})
+
+	if client.role == "" {
+		client.conn.SetReadDeadline(time.Now().Add(authTimeout))
+	}
 
 	for scanner.Scan() {
 		var req Request
-		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
-			client.sendError("", fmt.Sprintf("invalid request: %v", err))
+		if err := decodeRequest(scanner.Bytes(), &req); err != nil {
+			client.sendError("", CodeInvalidRequest, fmt.Sprintf("invalid request: %v", err))
+			continue
+		}
+
+		if client.role == "" {
+			if req.Type == MsgTypeHello {
+				s.hello(client, &req)
+				continue
+			}
+			if !s.authenticate(client, &req) {
+				return
+			}
+			if client.role == auth.RoleAdmin {
+				limit = maxMessageSize
+			}
 			continue
 		}
 
@@ -146,9 +272,123 @@ func (s *Server) handleClient(client *serverClient) {
 	}
 }
 
+// authenticate handles the first message from a remote client, which must be
+// an auth request with a valid token
+func (s *Server) authenticate(client *serverClient, req *Request) bool {
+	if req.Type != MsgTypeAuth {
+		client.sendError(req.ID, CodeUnauthenticated, "authentication required")
+		return false
+	}
+
+	var authReq AuthRequest
+	if err := decodeRequest(req.Data, &authReq); err != nil {
+		client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid auth request: %v", err))
+		return false
+	}
+
+	identity, ok := s.authenticator.Authenticate(authReq.Token)
+	if !ok {
+		log.Printf("[IPC] Rejected remote client %s: invalid token", client.conn.RemoteAddr())
+		client.sendError(req.ID, CodeUnauthenticated, "invalid token")
+		return false
+	}
+
+	client.role = identity.Role
+	client.name = identity.Name
+	client.conn.SetReadDeadline(time.Time{})
+
+	log.Printf("[IPC] Remote client %s authenticated as %s (%s)", client.conn.RemoteAddr(), identity.Name, identity.Role)
+	client.sendOK(req.ID)
+	return true
+}
+
+// hello negotiates the protocol version and reports the server's capabilities
+func (s *Server) hello(client *serverClient, req *Request) {
+	var helloReq HelloRequest
+	if err := decodeRequest(req.Data, &helloReq); err != nil {
+		client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid hello request: %v", err))
+		return
+	}
+
+	version, ok := negotiateVersion(helloReq.Version)
+	if !ok {
+		client.sendError(req.ID, CodeUnsupportedVersion, fmt.Sprintf(
+			"protocol version %d is not supported (server speaks %d to %d)",
+			helloReq.Version, MinProtocolVersion, ProtocolVersion))
+		return
+	}
+
+	client.sendResponse(req.ID, MsgTypeHello, HelloResponse{
+		Version:      version,
+		MinVersion:   MinProtocolVersion,
+		MaxVersion:   ProtocolVersion,
+		Capabilities: capabilities,
+		AuthRequired: client.role == "",
+	})
+}
+
+// negotiateVersion returns the protocol version to use with a client that
+// speaks up to clientVersion, or false if there is none in common
+func negotiateVersion(clientVersion int) (int, bool) {
+	if clientVersion < MinProtocolVersion {
+		return 0, false
+	}
+	return min(clientVersion, ProtocolVersion), true
+}
+
+// hasTarget reports whether a target or agent series is configured
+func (s *Server) hasTarget(name string) bool {
+	for _, t := range s.collector.GetTargets() {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// requiredRole returns the role needed for a request type. Only the request
+// types listed as read-only are open to read tokens, so new requests are
+// admin-only until listed.
+func requiredRole(reqType string) auth.Role {
+	switch reqType {
+	case MsgTypeHello, MsgTypeAuth, MsgTypeSubscribe, MsgTypeUnsubscribe,
+		MsgTypeGetTargets, MsgTypeGetStats, MsgTypeGetHistory, MsgTypeExport,
+		MsgTypeGetStorage, MsgTypeGetEvents, MsgTypeGetSilences:
+		return auth.RoleRead
+	default:
+		return auth.RoleAdmin
+	}
+}
+
+// errorCode classifies an error returned by the collector
+func errorCode(err error) ErrorCode {
+	switch {
+	case errors.Is(err, collector.ErrStorageUnavailable):
+		return CodeUnavailable
+	case errors.Is(err, collector.ErrTargetNotFound), errors.Is(err, storage.ErrNoHistory):
+		return CodeNotFound
+	case errors.Is(err, collector.ErrTargetExists), errors.Is(err, storage.ErrTargetExists):
+		return CodeConflict
+	case errors.Is(err, collector.ErrAgentManaged):
+		return CodeAgentManaged
+	}
+	return CodeFailed
+}
+
 // handleRequest processes a client request
 func (s *Server) handleRequest(client *serverClient, req *Request) {
+	if !client.role.Allows(requiredRole(req.Type)) {
+		client.sendError(req.ID, CodeForbidden, fmt.Sprintf("%s requires the %s role", req.Type, requiredRole(req.Type)))
+		return
+	}
+
 	switch req.Type {
+	case MsgTypeHello:
+		s.hello(client, req)
+
+	case MsgTypeAuth:
+		client.sendOK(req.ID) // Already authenticated
+
 	case MsgTypeSubscribe:
 		client.mu.Lock()
 		client.subscribed = true
@@ -163,7 +403,7 @@ func (s *Server) handleRequest(client *serverClient, req *Request) {
 
 	case MsgTypeGetTargets:
 		if s.collector == nil {
-			client.sendError(req.ID, "collector not available")
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
 			return
 		}
 		targets := s.collector.GetTargets()
@@ -171,47 +411,49 @@ func (s *Server) handleRequest(client *serverClient, req *Request) {
 
 	case MsgTypeGetStats:
 		if s.collector == nil {
-			client.sendError(req.ID, "collector not available")
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
 			return
 		}
 
-		// Parse request data
 		var statsReq GetStatsRequest
-		if data, ok := req.Data.(map[string]interface{}); ok {
-			if target, ok := data["target"].(string); ok {
-				statsReq.Target = target
-			}
+		if err := decodeRequest(req.Data, &statsReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid stats request: %v", err))
+			return
+		}
+		if !s.hasTarget(statsReq.Target) {
+			client.sendError(req.ID, CodeNotFound, fmt.Sprintf("target not found: %s", statsReq.Target))
+			return
 		}
 
 		stats := s.collector.GetStats(statsReq.Target)
 		client.sendResponse(req.ID, MsgTypeStats, StatsResponse{
-			Target: statsReq.Target,
-			Stats:  stats,
+			Target:  statsReq.Target,
+			Stats:   stats,
+			Anomaly: s.collector.Anomaly(statsReq.Target),
+
+			Maintenance: s.collector.Maintenance(statsReq.Target),
+			Dependency:  s.collector.Dependency(statsReq.Target),
 		})
 
 	case MsgTypeGetHistory:
 		if s.collector == nil {
-			client.sendError(req.ID, "collector not available")
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
 			return
 		}
 
-		// Parse request data
 		var histReq GetHistoryRequest
-		if data, ok := req.Data.(map[string]interface{}); ok {
-			if target, ok := data["target"].(string); ok {
-				histReq.Target = target
-			}
-			if from, ok := data["from"].(string); ok {
-				histReq.From, _ = time.Parse(time.RFC3339, from)
-			}
-			if to, ok := data["to"].(string); ok {
-				histReq.To, _ = time.Parse(time.RFC3339, to)
-			}
+		if err := decodeRequest(req.Data, &histReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid history request: %v", err))
+			return
+		}
+		if !s.hasTarget(histReq.Target) {
+			client.sendError(req.ID, CodeNotFound, fmt.Sprintf("target not found: %s", histReq.Target))
+			return
 		}
 
 		points, err := s.collector.FetchHistory(histReq.Target, histReq.From, histReq.To)
 		if err != nil {
-			client.sendError(req.ID, fmt.Sprintf("failed to fetch history: %v", err))
+			client.sendError(req.ID, CodeFailed, fmt.Sprintf("failed to fetch history: %v", err))
 			return
 		}
 
@@ -229,13 +471,194 @@ func (s *Server) handleRequest(client *serverClient, req *Request) {
 			}
 		}
 
-		client.sendResponse(req.ID, MsgTypeHistory, map[string]any{
-			"target":      histReq.Target,
-			"data_points": safePoints,
+		client.sendResponse(req.ID, MsgTypeHistory, HistoryResponse{
+			Target:     histReq.Target,
+			DataPoints: safePoints,
 		})
 
+	case MsgTypeExport:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var exportReq ExportRequest
+		if err := decodeRequest(req.Data, &exportReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid export request: %v", err))
+			return
+		}
+
+		archive, err := s.collector.ExportHistory(exportReq.Target)
+		if err != nil {
+			client.sendError(req.ID, errorCode(err), fmt.Sprintf("failed to export history: %v", err))
+			return
+		}
+		client.sendResponse(req.ID, MsgTypeArchive, archive)
+
+	case MsgTypeImport:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var importReq ImportRequest
+		if err := decodeRequest(req.Data, &importReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid import request: %v", err))
+			return
+		}
+		if importReq.Archive == nil {
+			client.sendError(req.ID, CodeInvalidRequest, "import request has no archive")
+			return
+		}
+		if importReq.Mode == "" {
+			importReq.Mode = storage.ImportCreate
+		}
+
+		if err := s.collector.ImportHistory(importReq.Target, importReq.Archive, importReq.Mode); err != nil {
+			client.sendError(req.ID, errorCode(err), fmt.Sprintf("failed to import history: %v", err))
+			return
+		}
+		client.sendOK(req.ID)
+
+	case MsgTypeRename:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var renameReq RenameRequest
+		if err := decodeRequest(req.Data, &renameReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid rename request: %v", err))
+			return
+		}
+		if renameReq.NewName == "" {
+			client.sendError(req.ID, CodeInvalidRequest, "rename request has no new name")
+			return
+		}
+		if !s.hasTarget(renameReq.Target) {
+			client.sendError(req.ID, CodeNotFound, fmt.Sprintf("target not found: %s", renameReq.Target))
+			return
+		}
+
+		if err := s.collector.RenameTarget(renameReq.Target, renameReq.NewName); err != nil {
+			client.sendError(req.ID, errorCode(err), fmt.Sprintf("failed to rename target: %v", err))
+			return
+		}
+		client.sendOK(req.ID)
+
+	case MsgTypeGetStorage:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		usage, err := s.collector.StorageUsage()
+		if err != nil {
+			client.sendError(req.ID, CodeFailed, fmt.Sprintf("failed to get storage usage: %v", err))
+			return
+		}
+		client.sendResponse(req.ID, MsgTypeStorage, usage)
+
+	case MsgTypeGetEvents:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var eventsReq GetEventsRequest
+		if err := decodeRequest(req.Data, &eventsReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid events request: %v", err))
+			return
+		}
+		if eventsReq.Limit <= 0 {
+			eventsReq.Limit = defaultEventLimit
+		}
+
+		events := s.collector.Incidents(storage.IncidentQuery{
+			Target:  eventsReq.Target,
+			Kind:    storage.IncidentKind(eventsReq.Kind),
+			From:    eventsReq.From,
+			To:      eventsReq.To,
+			Ongoing: eventsReq.Ongoing,
+			Limit:   eventsReq.Limit,
+		})
+		client.sendResponse(req.ID, MsgTypeEvents, EventsResponse{Events: events})
+
+	case MsgTypeGetSilences:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+		client.sendResponse(req.ID, MsgTypeSilences, SilencesResponse{
+			Silences: s.collector.Silences(),
+			Active:   s.collector.MaintenanceActive(),
+		})
+
+	case MsgTypeAddSilence:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var silenceReq AddSilenceRequest
+		if err := decodeRequest(req.Data, &silenceReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid silence request: %v", err))
+			return
+		}
+
+		silence, err := s.collector.AddSilence(collector.Silence{
+			Target:    silenceReq.Target,
+			Group:     silenceReq.Group,
+			Tag:       silenceReq.Tag,
+			Start:     silenceReq.Start,
+			End:       silenceReq.End,
+			Comment:   silenceReq.Comment,
+			CreatedBy: client.name,
+		})
+		if err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("failed to add silence: %v", err))
+			return
+		}
+		client.sendResponse(req.ID, MsgTypeSilence, silence)
+
+	case MsgTypeRmSilence:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var removeReq RemoveSilenceRequest
+		if err := decodeRequest(req.Data, &removeReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid remove request: %v", err))
+			return
+		}
+
+		if err := s.collector.RemoveSilence(removeReq.ID); err != nil {
+			client.sendError(req.ID, CodeNotFound, err.Error())
+			return
+		}
+		client.sendOK(req.ID)
+
+	case MsgTypeRemove:
+		if s.collector == nil {
+			client.sendError(req.ID, CodeUnavailable, "collector not available")
+			return
+		}
+
+		var removeReq RemoveOrphanRequest
+		if err := decodeRequest(req.Data, &removeReq); err != nil {
+			client.sendError(req.ID, CodeInvalidRequest, fmt.Sprintf("invalid remove request: %v", err))
+			return
+		}
+
+		if _, err := s.collector.RemoveOrphan(removeReq.ID, removeReq.Archive); err != nil {
+			client.sendError(req.ID, CodeFailed, fmt.Sprintf("failed to remove orphan: %v", err))
+			return
+		}
+		client.sendOK(req.ID)
+
 	default:
-		client.sendError(req.ID, fmt.Sprintf("unknown request type: %s", req.Type))
+		client.sendError(req.ID, CodeUnknownType, fmt.Sprintf("unknown request type: %s", req.Type))
 	}
 }
 
@@ -252,28 +675,24 @@ func (s *Server) broadcastResults(ch <-chan probe.ProbeResult) {
 				return
 			}
 
-			data := ProbeResultData{
-				Target:    result.Target,
-				Timestamp: result.Timestamp,
-				LatencyMs: result.LatencyMs,
-				Success:   result.Success,
-				Error:     result.Error,
+			raw, err := encodeData(newProbeResultData(result))
+			if err != nil {
+				log.Printf("[IPC] Failed to encode probe result: %v", err)
+				continue
 			}
-
 			resp := Response{
 				Type: MsgTypeProbeResult,
-				Data: data,
+				Data: raw,
 			}
 
 			s.clientsMu.RLock()
 			for client := range s.clients {
 				client.mu.Lock()
-				if client.subscribed {
-					if err := client.encoder.Encode(resp); err != nil {
-						log.Printf("[IPC] Failed to send result to client: %v", err)
-					}
-				}
+				subscribed := client.subscribed
 				client.mu.Unlock()
+				if subscribed {
+					client.queue(resp)
+				}
 			}
 			s.clientsMu.RUnlock()
 		}
@@ -295,13 +714,21 @@ func (s *Server) Stop() error {
 	if s.listener != nil {
 		s.listener.Close()
 	}
+	if s.remoteListener != nil {
+		s.remoteListener.Close()
+	}
 
-	// Close all client connections
-	s.clientsMu.Lock()
+	// Close all client connections. Closing a TLS connection writes to it,
+	// so it is done outside clientsMu.
+	s.clientsMu.RLock()
+	clients := make([]*serverClient, 0, len(s.clients))
 	for client := range s.clients {
+		clients = append(clients, client)
+	}
+	s.clientsMu.RUnlock()
+	for _, client := range clients {
 		client.conn.Close()
 	}
-	s.clientsMu.Unlock()
 
 	s.wg.Wait()
 
@@ -312,25 +739,73 @@ func (s *Server) Stop() error {
 	return nil
 }
 
+// writeLoop writes queued messages to the client until it is disconnected,
+// then flushes what is still queued
+func (c *serverClient) writeLoop() {
+	for {
+		select {
+		case resp := <-c.send:
+			if !c.write(resp) {
+				return
+			}
+		case <-c.done:
+			c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+			for {
+				select {
+				case resp := <-c.send:
+					if !c.write(resp) {
+						return
+					}
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// write writes a message to the client, closing the connection on failure
+func (c *serverClient) write(resp Response) bool {
+	if c.remote {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	}
+	if err := c.encoder.Encode(resp); err != nil {
+		log.Printf("[IPC] Failed to send %s to client: %v", resp.Type, err)
+		c.conn.Close()
+		return false
+	}
+	return true
+}
+
+// queue queues a message for the client. A client too slow to keep up is
+// disconnected.
+func (c *serverClient) queue(resp Response) {
+	select {
+	case c.send <- resp:
+	default:
+		log.Printf("[IPC] Client %s is not keeping up, disconnecting", c.conn.RemoteAddr())
+		c.conn.Close()
+	}
+}
+
 // sendOK sends an OK response
 func (c *serverClient) sendOK(reqID string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.encoder.Encode(Response{ID: reqID, Type: MsgTypeOK})
+	c.queue(Response{ID: reqID, Type: MsgTypeOK})
 }
 
 // sendError sends an error response
-func (c *serverClient) sendError(reqID string, msg string) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.encoder.Encode(Response{ID: reqID, Type: MsgTypeError, Error: msg})
+func (c *serverClient) sendError(reqID string, code ErrorCode, msg string) {
+	c.queue(Response{ID: reqID, Type: MsgTypeError, Error: msg, Code: code})
 }
 
 // sendResponse sends a response with data
 func (c *serverClient) sendResponse(reqID string, msgType string, data any) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	if err := c.encoder.Encode(Response{ID: reqID, Type: msgType, Data: data}); err != nil {
+	raw, err := encodeData(data)
+	if err != nil {
 		log.Printf("[IPC] Failed to encode response (type=%s): %v", msgType, err)
+		c.sendError(reqID, CodeFailed, fmt.Sprintf("failed to encode response: %v", err))
+		return
 	}
+
+	c.queue(Response{ID: reqID, Type: msgType, Data: raw})
 }
@@ -0,0 +1,157 @@
+package ipc
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/auth"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/probe"
+)
+
+func TestStalledClientDoesNotBlockBroadcast(t *testing.T) {
+	s := NewServer(filepath.Join(t.TempDir(), "pulse.sock"))
+	connect := func() (*serverClient, net.Conn) {
+		conn, peer := net.Pipe()
+		client := &serverClient{
+			conn:       conn,
+			server:     s,
+			encoder:    json.NewEncoder(conn),
+			send:       make(chan Response, clientSendBuffer),
+			done:       make(chan struct{}),
+			subscribed: true,
+			remote:     true,
+			role:       auth.RoleRead,
+		}
+		s.clients[client] = struct{}{}
+		s.wg.Add(1)
+		go s.handleClient(client)
+		return client, peer
+	}
+
+	stalled, _ := connect() // Never reads
+	_, peer := connect()
+
+	received := make(chan struct{})
+	go func() {
+		dec := json.NewDecoder(peer)
+		for {
+			var resp Response
+			if err := dec.Decode(&resp); err != nil {
+				return
+			}
+			received <- struct{}{}
+		}
+	}()
+
+	// More results than the stalled client's queue holds, each delivered to
+	// the reading client without waiting on the stalled one
+	results := make(chan probe.ProbeResult)
+	s.wg.Add(1)
+	go s.broadcastResults(results)
+	for i := 0; i < clientSendBuffer+10; i++ {
+		results <- probe.ProbeResult{Target: "google", Timestamp: time.Now(), Success: true}
+		select {
+		case <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("result %d not delivered: broadcast blocked by a stalled client", i)
+		}
+	}
+
+	// The stalled client fell behind and was disconnected
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		s.clientsMu.RLock()
+		_, connected := s.clients[stalled]
+		s.clientsMu.RUnlock()
+		if !connected {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("stalled client still connected")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Stop() blocked")
+	}
+}
+
+func TestRequestSizeLimit(t *testing.T) {
+	// An unknown request type padded past the pre-auth limit
+	large := fmt.Sprintf(`{"id":"1","type":"no_such_type","data":{"padding":%q}}`+"\n", strings.Repeat("x", 2*maxRequestSize))
+
+	authenticator, err := auth.New(config.AuthConfig{Tokens: []config.TokenConfig{
+		{Name: "ops", Token: "admin-token-0123456789abcdef", Role: "admin"},
+	}})
+	if err != nil {
+		t.Fatalf("auth.New: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		role     auth.Role
+		token    string    // Sent in an auth request first
+		wantCode ErrorCode // Empty when the client is disconnected
+	}{
+		{name: "unauthenticated", role: ""},
+		{name: "read", role: auth.RoleRead},
+		{name: "admin", role: auth.RoleAdmin, wantCode: CodeUnknownType},
+		{name: "authenticated as admin", role: "", token: "admin-token-0123456789abcdef", wantCode: CodeUnknownType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewServer(filepath.Join(t.TempDir(), "pulse.sock"))
+			s.authenticator = authenticator
+			conn, peer := net.Pipe()
+			defer peer.Close()
+			client := &serverClient{
+				conn:    conn,
+				server:  s,
+				encoder: json.NewEncoder(conn),
+				send:    make(chan Response, clientSendBuffer),
+				done:    make(chan struct{}),
+				remote:  true,
+				role:    tt.role,
+			}
+			s.wg.Add(1)
+			go s.handleClient(client)
+
+			peer.SetDeadline(time.Now().Add(5 * time.Second))
+			dec := json.NewDecoder(peer)
+			var resp Response
+			if tt.token != "" {
+				fmt.Fprintf(peer, `{"id":"0","type":"auth","data":{"token":%q}}`+"\n", tt.token)
+				if err := dec.Decode(&resp); err != nil || resp.Type != MsgTypeOK {
+					t.Fatalf("auth response = %+v, %v", resp, err)
+				}
+			}
+			go peer.Write([]byte(large))
+
+			err := dec.Decode(&resp)
+			if tt.wantCode == "" {
+				if err == nil {
+					t.Errorf("got response %+v, want the connection closed", resp)
+				}
+				return
+			}
+			if err != nil || resp.Code != tt.wantCode {
+				t.Errorf("response = %+v, %v, want code %s", resp, err, tt.wantCode)
+			}
+		})
+	}
+}
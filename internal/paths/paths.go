@@ -5,6 +5,8 @@ import (
 	"os"
 	"os/user"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 // Paths holds the resolved paths for config, data, and socket
@@ -133,3 +135,34 @@ targets:
 
 	return true, nil
 }
+
+// unsafeFilenameChars matches characters that are unsafe for filenames on various filesystems
+var unsafeFilenameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
+
+// repeatedUnderscores matches runs of underscores
+var repeatedUnderscores = regexp.MustCompile(`_+`)
+
+// SanitizeFilename converts a target name or id into a safe, lowercase file name
+// (without extension). Different names can map to the same result, so callers
+// that need unique files must check for collisions.
+func SanitizeFilename(name string) string {
+	// Replace spaces with underscores
+	safe := strings.ReplaceAll(name, " ", "_")
+	// Remove or replace all filesystem-unsafe characters (Windows + Unix)
+	safe = unsafeFilenameChars.ReplaceAllString(safe, "_")
+	// Convert to lowercase for consistency
+	safe = strings.ToLower(safe)
+	// Collapse multiple underscores
+	safe = repeatedUnderscores.ReplaceAllString(safe, "_")
+	// Trim leading/trailing underscores
+	safe = strings.Trim(safe, "_")
+	// Truncate to reasonable length (200 chars max)
+	if len(safe) > 200 {
+		safe = safe[:200]
+	}
+	// Ensure non-empty filename
+	if safe == "" {
+		safe = "unnamed"
+	}
+	return safe
+}
@@ -23,6 +23,12 @@ type ProbeResult struct {
 	LossPct    float64 `json:"loss_pct"`              // Packet loss percentage (0-100)
 	PingsSent  int     `json:"pings_sent,omitempty"`  // Number of pings sent
 	PingsRecv  int     `json:"pings_recv,omitempty"`  // Number of pings received
+
+	// Individual round-trip times of the received pings, in the order received
+	RTTsMs []float64 `json:"rtts_ms,omitempty"`
+
+	// Position in the order results are recorded, set by the collector
+	Seq uint64 `json:"seq,omitempty"`
 }
 
 // Probe defines the interface for all probe types
@@ -73,6 +79,7 @@ func (b *BaseProbe) NewResult(latency time.Duration, success bool, err error) Pr
 		result.MinMs = result.LatencyMs
 		result.MaxMs = result.LatencyMs
 		result.AvgMs = result.LatencyMs
+		result.RTTsMs = []float64{result.LatencyMs}
 		result.PingsRecv = 1
 		result.LossPct = 0
 	} else {
@@ -140,6 +147,11 @@ func (b *BaseProbe) NewBurstResult(stats BurstStats, err error) ProbeResult {
 	result.AvgMs = float64(stats.AvgRtt.Microseconds()) / 1000.0
 	result.JitterMs = float64(stats.StdDevRtt.Microseconds()) / 1000.0
 
+	result.RTTsMs = make([]float64, len(stats.Rtts))
+	for i, rtt := range stats.Rtts {
+		result.RTTsMs[i] = float64(rtt.Microseconds()) / 1000.0
+	}
+
 	return result
 }
 
@@ -146,6 +146,9 @@ func TestNewBurstResult(t *testing.T) {
 			if result.Success != tt.wantSuccess {
 				t.Errorf("NewBurstResult() Success = %v, want %v", result.Success, tt.wantSuccess)
 			}
+			if len(result.RTTsMs) != len(tt.stats.Rtts) {
+				t.Errorf("NewBurstResult() got %d RTTs, want %d", len(result.RTTsMs), len(tt.stats.Rtts))
+			}
 		})
 	}
 }
@@ -0,0 +1,87 @@
+package report
+
+import (
+	"fmt"
+	"time"
+)
+
+// Period is the time span covered by a report
+type Period struct {
+	From  time.Time
+	To    time.Time
+	Label string
+}
+
+// MonthPeriod returns the calendar month containing t
+func MonthPeriod(t time.Time) Period {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{
+		From:  from,
+		To:    from.AddDate(0, 1, 0),
+		Label: from.Format("2006-01"),
+	}
+}
+
+// WeekPeriod returns the ISO week (Monday to Monday) containing t
+func WeekPeriod(t time.Time) Period {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := (int(day.Weekday()) + 6) % 7 // Days since Monday
+	from := day.AddDate(0, 0, -offset)
+	year, week := from.ISOWeek()
+	return Period{
+		From:  from,
+		To:    from.AddDate(0, 0, 7),
+		Label: fmt.Sprintf("%d-W%02d", year, week),
+	}
+}
+
+// DayPeriod returns the calendar day containing t
+func DayPeriod(t time.Time) Period {
+	from := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return Period{
+		From:  from,
+		To:    from.AddDate(0, 0, 1),
+		Label: from.Format("2006-01-02"),
+	}
+}
+
+// ParseMonth parses a "YYYY-MM" string into a monthly period in local time
+func ParseMonth(s string) (Period, error) {
+	t, err := time.ParseInLocation("2006-01", s, time.Local)
+	if err != nil {
+		return Period{}, fmt.Errorf("invalid month %q (use YYYY-MM)", s)
+	}
+	return MonthPeriod(t), nil
+}
+
+// CustomPeriod returns a period for an explicit time range
+func CustomPeriod(from, to time.Time) Period {
+	return Period{
+		From:  from,
+		To:    to,
+		Label: from.Format("20060102T1504") + "-" + to.Format("20060102T1504"),
+	}
+}
+
+// periodFor returns the period of the given schedule that contains t
+func periodFor(schedule string, t time.Time) Period {
+	switch schedule {
+	case "daily":
+		return DayPeriod(t)
+	case "weekly":
+		return WeekPeriod(t)
+	default:
+		return MonthPeriod(t)
+	}
+}
+
+// PreviousPeriod returns the last complete period of the schedule before t
+func PreviousPeriod(schedule string, t time.Time) Period {
+	current := periodFor(schedule, t)
+	return periodFor(schedule, current.From.Add(-time.Second))
+}
+
+// NextBoundary returns the start of the next period of the schedule after t
+func NextBoundary(schedule string, t time.Time) time.Time {
+	return periodFor(schedule, t).To
+}
@@ -0,0 +1,187 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ContentType returns the MIME type for a report format
+func ContentType(format string) string {
+	switch format {
+	case "csv":
+		return "text/csv; charset=utf-8"
+	case "html":
+		return "text/html; charset=utf-8"
+	default:
+		return "application/json; charset=utf-8"
+	}
+}
+
+// Render writes the report in the given format (json, csv or html)
+func Render(w io.Writer, r *Report, format string) error {
+	switch format {
+	case "json", "":
+		return RenderJSON(w, r)
+	case "csv":
+		return RenderCSV(w, r)
+	case "html":
+		return RenderHTML(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// RenderJSON writes the report as indented JSON
+func RenderJSON(w io.Writer, r *Report) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// RenderCSV writes one row per target and per group
+func RenderCSV(w io.Writer, r *Report) error {
+	cw := csv.NewWriter(w)
+
+	header := []string{
+		"type", "name", "group", "from", "to",
+		"availability_pct", "measured_secs", "downtime_secs", "no_data_secs", "maintenance_secs",
+		"error_budget_allowed_secs", "error_budget_burn_pct",
+		"avg_latency_ms", "avg_loss_pct", "incidents", "error",
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	from := r.From.Format(time.RFC3339)
+	to := r.To.Format(time.RFC3339)
+
+	for _, t := range r.Targets {
+		row := []string{
+			"target", t.Target, t.Group, from, to,
+			formatFloat(t.AvailabilityPct, 4),
+			formatFloat(t.MeasuredSecs, 0),
+			formatFloat(t.DowntimeSecs, 0),
+			formatFloat(t.NoDataSecs, 0),
+			formatFloat(t.MaintenanceSecs, 0),
+			formatFloat(t.ErrorBudget.AllowedSecs, 0),
+			formatFloat(t.ErrorBudget.BurnPct, 2),
+			formatFloat(t.AvgLatencyMs, 2),
+			formatFloat(t.AvgLossPct, 2),
+			strconv.Itoa(t.IncidentCount),
+			t.Error,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	for _, g := range r.Groups {
+		row := []string{
+			"group", g.Group, g.Group, from, to,
+			formatFloat(g.AvailabilityPct, 4),
+			formatFloat(g.MeasuredSecs, 0),
+			formatFloat(g.DowntimeSecs, 0),
+			"",
+			"",
+			formatFloat(g.ErrorBudget.AllowedSecs, 0),
+			formatFloat(g.ErrorBudget.BurnPct, 2),
+			"", "", "", "",
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// RenderHTML writes a self-contained HTML page (inline CSS, no external assets)
+func RenderHTML(w io.Writer, r *Report) error {
+	return htmlTemplate.Execute(w, r)
+}
+
+func formatFloat(f float64, decimals int) string {
+	return strconv.FormatFloat(f, 'f', decimals, 64)
+}
+
+// formatSecs formats a number of seconds as a compact duration
+func formatSecs(secs float64) string {
+	return time.Duration(secs * float64(time.Second)).Round(time.Second).String()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct":  func(f float64) string { return formatFloat(f, 3) + "%" },
+	"ms":   func(f float64) string { return formatFloat(f, 1) + " ms" },
+	"dur":  formatSecs,
+	"time": func(t time.Time) string { return t.Format("2006-01-02 15:04") },
+	"ok":   func(availability, slo float64) bool { return availability >= slo },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Pulse availability report {{.Label}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; color: #1F2937; margin: 2em; }
+h1 { color: #7C3AED; margin-bottom: 0.2em; }
+h2 { color: #06B6D4; margin-top: 1.5em; }
+.meta { color: #6B7280; }
+table { border-collapse: collapse; width: 100%; margin-top: 0.5em; }
+th, td { text-align: left; padding: 0.35em 0.7em; border-bottom: 1px solid #E5E7EB; }
+th { background: #F3F4F6; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.good { color: #10B981; font-weight: bold; }
+.bad { color: #EF4444; font-weight: bold; }
+.error { color: #EF4444; }
+</style>
+</head>
+<body>
+<h1>Availability report {{.Label}}</h1>
+<p class="meta">{{time .From}} &ndash; {{time .To}} &middot; SLO {{pct .SLO}} &middot;
+loss threshold {{pct .LossThresholdPct}}{{if gt .LatencyThresholdMs 0.0}} &middot; latency threshold {{ms .LatencyThresholdMs}}{{end}}
+&middot; generated {{time .GeneratedAt}}</p>
+{{$slo := .SLO}}
+{{if .Groups}}
+<h2>Groups</h2>
+<table>
+<tr><th>Group</th><th>Targets</th><th>Availability</th><th>Downtime</th><th>Budget burn</th></tr>
+{{range .Groups}}
+<tr><td>{{.Group}}</td><td>{{len .Targets}}</td>
+<td class="num {{if ok .AvailabilityPct $slo}}good{{else}}bad{{end}}">{{pct .AvailabilityPct}}</td>
+<td class="num">{{dur .DowntimeSecs}}</td><td class="num">{{pct .ErrorBudget.BurnPct}}</td></tr>
+{{end}}
+</table>
+{{end}}
+<h2>Targets</h2>
+<table>
+<tr><th>Target</th><th>Group</th><th>Availability</th><th>Downtime</th><th>No data</th><th>Maintenance</th><th>Budget burn</th><th>Avg latency</th><th>Avg loss</th><th>Incidents</th></tr>
+{{range .Targets}}
+{{if .Error}}
+<tr><td>{{.Target}}</td><td>{{.Group}}</td><td colspan="8" class="error">{{.Error}}</td></tr>
+{{else}}
+<tr><td>{{.Target}}</td><td>{{.Group}}</td>
+<td class="num {{if ok .AvailabilityPct $slo}}good{{else}}bad{{end}}">{{pct .AvailabilityPct}}</td>
+<td class="num">{{dur .DowntimeSecs}}</td><td class="num">{{dur .NoDataSecs}}</td><td class="num">{{dur .MaintenanceSecs}}</td>
+<td class="num">{{pct .ErrorBudget.BurnPct}}</td><td class="num">{{ms .AvgLatencyMs}}</td>
+<td class="num">{{pct .AvgLossPct}}</td><td class="num">{{.IncidentCount}}</td></tr>
+{{end}}
+{{end}}
+</table>
+{{range .Targets}}{{if .WorstIncidents}}
+<h2>Worst incidents: {{.Target}}</h2>
+<table>
+<tr><th>Start</th><th>End</th><th>Duration</th><th>Max loss</th><th>Max latency</th></tr>
+{{range .WorstIncidents}}
+<tr><td>{{time .Start}}</td><td>{{time .End}}</td><td class="num">{{dur .DurationSecs}}</td>
+<td class="num">{{pct .MaxLossPct}}</td><td class="num">{{ms .MaxLatencyMs}}</td></tr>
+{{end}}
+</table>
+{{end}}{{end}}
+</body>
+</html>
+`))
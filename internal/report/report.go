@@ -0,0 +1,371 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Source provides target configuration, historical data and maintenance
+// periods for reports
+type Source interface {
+	GetTargets() []config.Target
+	FetchHistory(targetName string, from, to time.Time) ([]storage.DataPoint, error)
+	MaintenancePeriods(targetName string, from, to time.Time) []Period
+}
+
+// Report is an SLA / availability report for a period
+type Report struct {
+	Label              string         `json:"label"`
+	From               time.Time      `json:"from"`
+	To                 time.Time      `json:"to"`
+	GeneratedAt        time.Time      `json:"generated_at"`
+	SLO                float64        `json:"slo_pct"`
+	LatencyThresholdMs float64        `json:"latency_threshold_ms"`
+	LossThresholdPct   float64        `json:"loss_threshold_pct"`
+	Targets            []TargetReport `json:"targets"`
+	Groups             []GroupReport  `json:"groups,omitempty"`
+}
+
+// TargetReport holds availability figures for a single target
+type TargetReport struct {
+	Target          string      `json:"target"`
+	Group           string      `json:"group,omitempty"`
+	AvailabilityPct float64     `json:"availability_pct"`
+	MeasuredSecs    float64     `json:"measured_secs"`
+	DowntimeSecs    float64     `json:"downtime_secs"`
+	NoDataSecs      float64     `json:"no_data_secs"`
+	MaintenanceSecs float64     `json:"maintenance_secs"` // Excluded from availability
+	Intervals       int         `json:"intervals"`
+	BadIntervals    int         `json:"bad_intervals"`
+	AvgLatencyMs    float64     `json:"avg_latency_ms"`
+	AvgLossPct      float64     `json:"avg_loss_pct"`
+	ErrorBudget     ErrorBudget `json:"error_budget"`
+	IncidentCount   int         `json:"incident_count"`
+	WorstIncidents  []Incident  `json:"worst_incidents"`
+	Error           string      `json:"error,omitempty"`
+}
+
+// GroupReport aggregates availability over all targets of a group
+type GroupReport struct {
+	Group           string      `json:"group"`
+	Targets         []string    `json:"targets"`
+	AvailabilityPct float64     `json:"availability_pct"`
+	MeasuredSecs    float64     `json:"measured_secs"`
+	DowntimeSecs    float64     `json:"downtime_secs"`
+	ErrorBudget     ErrorBudget `json:"error_budget"`
+}
+
+// ErrorBudget describes how much of the allowed downtime was consumed
+type ErrorBudget struct {
+	AllowedSecs   float64 `json:"allowed_secs"`
+	ConsumedSecs  float64 `json:"consumed_secs"`
+	RemainingSecs float64 `json:"remaining_secs"`
+	BurnPct       float64 `json:"burn_pct"` // Consumed / allowed * 100, >100 means the SLO was missed
+}
+
+// Incident is a run of consecutive intervals that violated the thresholds
+type Incident struct {
+	Start        time.Time `json:"start"`
+	End          time.Time `json:"end"`
+	DurationSecs float64   `json:"duration_secs"`
+	MaxLossPct   float64   `json:"max_loss_pct"`
+	MaxLatencyMs float64   `json:"max_latency_ms"`
+}
+
+// Filter restricts a report to a single target or group (empty = everything)
+type Filter struct {
+	Target string
+	Group  string
+}
+
+// Thresholds decide whether an interval counts as available
+type Thresholds struct {
+	LatencyMs float64 // 0 = latency is not considered
+	LossPct   float64
+	SLO       float64
+	Worst     int
+}
+
+// Generator builds reports from historical data
+type Generator struct {
+	source     Source
+	thresholds Thresholds
+	interval   time.Duration
+
+	schedule  string
+	outputDir string
+	formats   []string
+}
+
+// NewGenerator creates a report generator for the given configuration
+func NewGenerator(cfg *config.Config, source Source) *Generator {
+	outputDir := cfg.Reports.OutputDir
+	if outputDir == "" {
+		outputDir = filepath.Join(cfg.Global.DataDir, "reports")
+	}
+
+	formats := cfg.Reports.Formats
+	if len(formats) == 0 {
+		formats = []string{"json", "csv", "html"}
+	}
+
+	return &Generator{
+		source: source,
+		thresholds: Thresholds{
+			LatencyMs: cfg.Reports.LatencyThreshold,
+			LossPct:   cfg.Reports.LossThreshold,
+			SLO:       cfg.Reports.SLO,
+			Worst:     cfg.Reports.WorstIncidents,
+		},
+		interval:  cfg.Global.Interval,
+		schedule:  cfg.Reports.Schedule,
+		outputDir: outputDir,
+		formats:   formats,
+	}
+}
+
+// Generate builds a report for the given period
+func (g *Generator) Generate(period Period, filter Filter) (*Report, error) {
+	to := period.To
+	if now := time.Now(); to.After(now) {
+		to = now // Current period: only report what has happened so far
+	}
+	if !to.After(period.From) {
+		return nil, fmt.Errorf("report period %s has not started yet", period.Label)
+	}
+
+	report := &Report{
+		Label:              period.Label,
+		From:               period.From,
+		To:                 to,
+		GeneratedAt:        time.Now(),
+		SLO:                g.thresholds.SLO,
+		LatencyThresholdMs: g.thresholds.LatencyMs,
+		LossThresholdPct:   g.thresholds.LossPct,
+		Targets:            []TargetReport{},
+	}
+
+	found := false
+	for _, t := range g.source.GetTargets() {
+		if filter.Target != "" && t.Name != filter.Target {
+			continue
+		}
+		if filter.Group != "" && t.Group != filter.Group {
+			continue
+		}
+		found = true
+
+		points, err := g.source.FetchHistory(t.Name, period.From, to)
+		if err != nil {
+			report.Targets = append(report.Targets, TargetReport{
+				Target: t.Name,
+				Group:  t.Group,
+				Error:  err.Error(),
+			})
+			continue
+		}
+
+		excluded := g.source.MaintenancePeriods(t.Name, period.From, to)
+		tr := computeTargetReport(points, period.From, to, g.interval, g.thresholds, excluded)
+		tr.Target = t.Name
+		tr.Group = t.Group
+		report.Targets = append(report.Targets, tr)
+	}
+
+	if !found {
+		switch {
+		case filter.Target != "":
+			return nil, fmt.Errorf("target not found: %s", filter.Target)
+		case filter.Group != "":
+			return nil, fmt.Errorf("group not found: %s", filter.Group)
+		}
+	}
+
+	report.Groups = computeGroupReports(report.Targets, g.thresholds.SLO)
+	return report, nil
+}
+
+// computeTargetReport classifies every interval in the series and derives
+// availability, error budget and incidents from it. Intervals starting within
+// an excluded period (maintenance) count neither as measured nor as downtime.
+func computeTargetReport(points []storage.DataPoint, from, to time.Time, fallbackStep time.Duration, th Thresholds, excluded []Period) TargetReport {
+	tr := TargetReport{WorstIncidents: []Incident{}}
+
+	var incidents []Incident
+	var current *Incident
+	var latencySum, lossSum float64
+	var latencyCount, lossCount int
+
+	closeIncident := func() {
+		if current != nil {
+			current.DurationSecs = current.End.Sub(current.Start).Seconds()
+			incidents = append(incidents, *current)
+			current = nil
+		}
+	}
+
+	for i, p := range points {
+		if p.Timestamp.Before(from) || !p.Timestamp.Before(to) {
+			continue
+		}
+
+		// Interval length is the distance to the next row (RRD rows are evenly spaced)
+		step := fallbackStep
+		if i+1 < len(points) {
+			step = points[i+1].Timestamp.Sub(p.Timestamp)
+		} else if i > 0 {
+			step = p.Timestamp.Sub(points[i-1].Timestamp)
+		}
+		end := p.Timestamp.Add(step)
+		if end.After(to) {
+			end = to
+		}
+		secs := end.Sub(p.Timestamp).Seconds()
+
+		if inPeriods(p.Timestamp, excluded) {
+			tr.MaintenanceSecs += secs
+			closeIncident()
+			continue
+		}
+
+		if math.IsNaN(p.Loss) {
+			tr.NoDataSecs += secs
+			closeIncident()
+			continue
+		}
+
+		tr.Intervals++
+		tr.MeasuredSecs += secs
+		lossPct := p.Loss * 100
+		lossSum += lossPct
+		lossCount++
+		if !math.IsNaN(p.Value) {
+			latencySum += p.Value
+			latencyCount++
+		}
+
+		if !isBad(p, th) {
+			closeIncident()
+			continue
+		}
+
+		tr.BadIntervals++
+		tr.DowntimeSecs += secs
+		if current == nil {
+			current = &Incident{Start: p.Timestamp}
+		}
+		current.End = end
+		if lossPct > current.MaxLossPct {
+			current.MaxLossPct = lossPct
+		}
+		if !math.IsNaN(p.Value) && p.Value > current.MaxLatencyMs {
+			current.MaxLatencyMs = p.Value
+		}
+	}
+	closeIncident()
+
+	if tr.MeasuredSecs > 0 {
+		tr.AvailabilityPct = (tr.MeasuredSecs - tr.DowntimeSecs) / tr.MeasuredSecs * 100
+	}
+	if latencyCount > 0 {
+		tr.AvgLatencyMs = latencySum / float64(latencyCount)
+	}
+	if lossCount > 0 {
+		tr.AvgLossPct = lossSum / float64(lossCount)
+	}
+	tr.ErrorBudget = errorBudget(tr.MeasuredSecs, tr.DowntimeSecs, th.SLO)
+
+	// Worst incidents first: longest, then highest loss
+	sort.SliceStable(incidents, func(i, j int) bool {
+		if incidents[i].DurationSecs != incidents[j].DurationSecs {
+			return incidents[i].DurationSecs > incidents[j].DurationSecs
+		}
+		return incidents[i].MaxLossPct > incidents[j].MaxLossPct
+	})
+	tr.IncidentCount = len(incidents)
+	if th.Worst > 0 && len(incidents) > th.Worst {
+		incidents = incidents[:th.Worst]
+	}
+	if len(incidents) > 0 {
+		tr.WorstIncidents = incidents
+	}
+
+	return tr
+}
+
+// inPeriods reports whether t falls within one of the periods
+func inPeriods(t time.Time, periods []Period) bool {
+	for _, p := range periods {
+		if !t.Before(p.From) && t.Before(p.To) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBad reports whether an interval violates the loss or latency threshold
+func isBad(p storage.DataPoint, th Thresholds) bool {
+	if p.Loss*100 > th.LossPct {
+		return true
+	}
+	if th.LatencyMs > 0 && !math.IsNaN(p.Value) && p.Value > th.LatencyMs {
+		return true
+	}
+	return false
+}
+
+// errorBudget calculates the downtime budget allowed by the SLO and how much of it was used
+func errorBudget(measuredSecs, downtimeSecs, slo float64) ErrorBudget {
+	allowed := measuredSecs * (100 - slo) / 100
+	budget := ErrorBudget{
+		AllowedSecs:   allowed,
+		ConsumedSecs:  downtimeSecs,
+		RemainingSecs: allowed - downtimeSecs,
+	}
+	switch {
+	case allowed > 0:
+		budget.BurnPct = downtimeSecs / allowed * 100
+	case downtimeSecs > 0:
+		budget.BurnPct = 100 // No budget at all: any downtime burns it completely
+	}
+	return budget
+}
+
+// computeGroupReports aggregates target reports by group (ungrouped targets are skipped)
+func computeGroupReports(targets []TargetReport, slo float64) []GroupReport {
+	byGroup := make(map[string]*GroupReport)
+	var order []string
+
+	for _, t := range targets {
+		if t.Group == "" || t.Error != "" {
+			continue
+		}
+		gr, ok := byGroup[t.Group]
+		if !ok {
+			gr = &GroupReport{Group: t.Group}
+			byGroup[t.Group] = gr
+			order = append(order, t.Group)
+		}
+		gr.Targets = append(gr.Targets, t.Target)
+		gr.MeasuredSecs += t.MeasuredSecs
+		gr.DowntimeSecs += t.DowntimeSecs
+	}
+
+	sort.Strings(order)
+	groups := make([]GroupReport, 0, len(order))
+	for _, name := range order {
+		gr := byGroup[name]
+		if gr.MeasuredSecs > 0 {
+			gr.AvailabilityPct = (gr.MeasuredSecs - gr.DowntimeSecs) / gr.MeasuredSecs * 100
+		}
+		gr.ErrorBudget = errorBudget(gr.MeasuredSecs, gr.DowntimeSecs, slo)
+		groups = append(groups, *gr)
+	}
+	return groups
+}
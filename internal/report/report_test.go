@@ -0,0 +1,216 @@
+package report
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// hourly builds hourly data points from (latency, loss) pairs starting at base
+func hourly(base time.Time, values ...[2]float64) []storage.DataPoint {
+	points := make([]storage.DataPoint, len(values))
+	for i, v := range values {
+		points[i] = storage.DataPoint{
+			Timestamp: base.Add(time.Duration(i) * time.Hour),
+			Value:     v[0],
+			Loss:      v[1],
+		}
+	}
+	return points
+}
+
+func TestComputeTargetReport(t *testing.T) {
+	base := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	nan := math.NaN()
+
+	tests := []struct {
+		name          string
+		points        []storage.DataPoint
+		th            Thresholds
+		excluded      []Period
+		wantAvail     float64
+		wantDowntime  float64
+		wantNoData    float64
+		wantMaint     float64
+		wantIncidents int
+		wantWorstSecs float64
+	}{
+		{
+			name:      "all good",
+			points:    hourly(base, [2]float64{10, 0}, [2]float64{11, 0}, [2]float64{12, 0}, [2]float64{10, 0}),
+			th:        Thresholds{LossPct: 10, SLO: 99.9},
+			wantAvail: 100,
+		},
+		{
+			name:          "one lossy hour",
+			points:        hourly(base, [2]float64{10, 0}, [2]float64{nan, 1}, [2]float64{12, 0}, [2]float64{10, 0}),
+			th:            Thresholds{LossPct: 10, SLO: 99.9},
+			wantAvail:     75,
+			wantDowntime:  3600,
+			wantIncidents: 1,
+			wantWorstSecs: 3600,
+		},
+		{
+			name:          "latency threshold",
+			points:        hourly(base, [2]float64{10, 0}, [2]float64{150, 0}, [2]float64{160, 0}, [2]float64{10, 0}),
+			th:            Thresholds{LatencyMs: 100, LossPct: 10, SLO: 99.9},
+			wantAvail:     50,
+			wantDowntime:  7200,
+			wantIncidents: 1,
+			wantWorstSecs: 7200,
+		},
+		{
+			name:          "latency ignored without threshold",
+			points:        hourly(base, [2]float64{10, 0}, [2]float64{150, 0}, [2]float64{160, 0}, [2]float64{10, 0}),
+			th:            Thresholds{LossPct: 10, SLO: 99.9},
+			wantAvail:     100,
+			wantIncidents: 0,
+		},
+		{
+			name:      "outage during maintenance excluded",
+			points:    hourly(base, [2]float64{10, 0}, [2]float64{nan, 1}, [2]float64{nan, 1}, [2]float64{10, 0}),
+			th:        Thresholds{LossPct: 10, SLO: 99.9},
+			excluded:  []Period{{From: base.Add(time.Hour), To: base.Add(3 * time.Hour)}},
+			wantAvail: 100,
+			wantMaint: 7200,
+		},
+		{
+			name:       "no data excluded",
+			points:     hourly(base, [2]float64{10, 0}, [2]float64{nan, nan}, [2]float64{12, 0}, [2]float64{10, 0}),
+			th:         Thresholds{LossPct: 10, SLO: 99.9},
+			wantAvail:  100,
+			wantNoData: 3600,
+		},
+		{
+			name:          "loss below threshold",
+			points:        hourly(base, [2]float64{10, 0.05}, [2]float64{10, 0.5}, [2]float64{12, 0}, [2]float64{10, 0.2}),
+			th:            Thresholds{LossPct: 10, SLO: 99.9},
+			wantAvail:     50,
+			wantDowntime:  7200,
+			wantIncidents: 2,
+			wantWorstSecs: 3600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := base.Add(time.Duration(len(tt.points)) * time.Hour)
+			got := computeTargetReport(tt.points, base, to, time.Hour, tt.th, tt.excluded)
+
+			if math.Abs(got.AvailabilityPct-tt.wantAvail) > 0.001 {
+				t.Errorf("AvailabilityPct = %v, want %v", got.AvailabilityPct, tt.wantAvail)
+			}
+			if got.DowntimeSecs != tt.wantDowntime {
+				t.Errorf("DowntimeSecs = %v, want %v", got.DowntimeSecs, tt.wantDowntime)
+			}
+			if got.NoDataSecs != tt.wantNoData {
+				t.Errorf("NoDataSecs = %v, want %v", got.NoDataSecs, tt.wantNoData)
+			}
+			if got.MaintenanceSecs != tt.wantMaint {
+				t.Errorf("MaintenanceSecs = %v, want %v", got.MaintenanceSecs, tt.wantMaint)
+			}
+			if got.IncidentCount != tt.wantIncidents {
+				t.Errorf("IncidentCount = %d, want %d", got.IncidentCount, tt.wantIncidents)
+			}
+			if tt.wantIncidents > 0 && got.WorstIncidents[0].DurationSecs != tt.wantWorstSecs {
+				t.Errorf("worst incident = %vs, want %vs", got.WorstIncidents[0].DurationSecs, tt.wantWorstSecs)
+			}
+		})
+	}
+}
+
+func TestErrorBudget(t *testing.T) {
+	tests := []struct {
+		name        string
+		measured    float64
+		downtime    float64
+		slo         float64
+		wantAllowed float64
+		wantBurn    float64
+	}{
+		{"unused", 1000000, 0, 99.9, 1000, 0},
+		{"half burned", 1000000, 500, 99.9, 1000, 50},
+		{"exceeded", 1000000, 2000, 99.9, 1000, 200},
+		{"no measurement", 0, 0, 99.9, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := errorBudget(tt.measured, tt.downtime, tt.slo)
+			if math.Abs(got.AllowedSecs-tt.wantAllowed) > 0.001 {
+				t.Errorf("AllowedSecs = %v, want %v", got.AllowedSecs, tt.wantAllowed)
+			}
+			if math.Abs(got.BurnPct-tt.wantBurn) > 0.001 {
+				t.Errorf("BurnPct = %v, want %v", got.BurnPct, tt.wantBurn)
+			}
+		})
+	}
+}
+
+func TestComputeGroupReports(t *testing.T) {
+	targets := []TargetReport{
+		{Target: "a", Group: "isp", MeasuredSecs: 1000, DowntimeSecs: 10},
+		{Target: "b", Group: "isp", MeasuredSecs: 1000, DowntimeSecs: 30},
+		{Target: "c", Group: "", MeasuredSecs: 1000, DowntimeSecs: 500},
+		{Target: "d", Group: "lan", MeasuredSecs: 1000},
+	}
+
+	groups := computeGroupReports(targets, 99)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2", len(groups))
+	}
+	if groups[0].Group != "isp" || groups[1].Group != "lan" {
+		t.Errorf("groups = %q, %q, want isp, lan", groups[0].Group, groups[1].Group)
+	}
+	if math.Abs(groups[0].AvailabilityPct-98) > 0.001 {
+		t.Errorf("isp availability = %v, want 98", groups[0].AvailabilityPct)
+	}
+	if groups[1].AvailabilityPct != 100 {
+		t.Errorf("lan availability = %v, want 100", groups[1].AvailabilityPct)
+	}
+}
+
+func TestPeriods(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.UTC) // Sunday
+
+	tests := []struct {
+		schedule  string
+		wantLabel string
+		wantFrom  time.Time
+		wantNext  time.Time
+	}{
+		{"monthly", "2026-09", time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{"weekly", "2026-W41", time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+		{"daily", "2026-10-17", time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.schedule, func(t *testing.T) {
+			prev := PreviousPeriod(tt.schedule, now)
+			if prev.Label != tt.wantLabel {
+				t.Errorf("PreviousPeriod label = %q, want %q", prev.Label, tt.wantLabel)
+			}
+			if !prev.From.Equal(tt.wantFrom) {
+				t.Errorf("PreviousPeriod from = %v, want %v", prev.From, tt.wantFrom)
+			}
+			if next := NextBoundary(tt.schedule, now); !next.Equal(tt.wantNext) {
+				t.Errorf("NextBoundary = %v, want %v", next, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestParseMonth(t *testing.T) {
+	if _, err := ParseMonth("2026-13"); err == nil {
+		t.Error("ParseMonth(2026-13) expected error")
+	}
+	p, err := ParseMonth("2026-02")
+	if err != nil {
+		t.Fatalf("ParseMonth(2026-02) error = %v", err)
+	}
+	if got := p.To.Sub(p.From); got != 28*24*time.Hour {
+		t.Errorf("February 2026 length = %v, want 672h", got)
+	}
+}
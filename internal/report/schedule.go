@@ -0,0 +1,169 @@
+package report
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FileInfo describes a report file written by the scheduler
+type FileInfo struct {
+	Name    string    `json:"name"`
+	Format  string    `json:"format"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Schedule returns the configured report schedule ("" = on demand only)
+func (g *Generator) Schedule() string {
+	return g.schedule
+}
+
+// Run generates reports at every period boundary until ctx is cancelled.
+// On startup it also catches up on the last complete period if its report is missing.
+func (g *Generator) Run(ctx context.Context) {
+	if g.schedule == "" {
+		return
+	}
+
+	log.Printf("[Reports] Scheduling %s reports in %s", g.schedule, g.outputDir)
+
+	if prev := PreviousPeriod(g.schedule, time.Now()); !g.exists(prev) {
+		g.generateAndSave(prev)
+	}
+
+	for {
+		// Small delay after the boundary so the last interval has been written
+		next := NextBoundary(g.schedule, time.Now()).Add(time.Minute)
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			g.generateAndSave(PreviousPeriod(g.schedule, time.Now()))
+		}
+	}
+}
+
+// generateAndSave builds a report for the period and writes all configured formats
+func (g *Generator) generateAndSave(period Period) {
+	r, err := g.Generate(period, Filter{})
+	if err != nil {
+		log.Printf("[Reports] Failed to generate report %s: %v", period.Label, err)
+		return
+	}
+
+	files, err := g.Save(r)
+	if err != nil {
+		log.Printf("[Reports] Failed to save report %s: %v", period.Label, err)
+		return
+	}
+	log.Printf("[Reports] Wrote report %s (%s)", period.Label, strings.Join(files, ", "))
+}
+
+// Save writes the report to the output directory in every configured format
+// and returns the written file names
+func (g *Generator) Save(r *Report) ([]string, error) {
+	if err := os.MkdirAll(g.outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+
+	var written []string
+	for _, format := range g.formats {
+		name := fileName(r.Label, format)
+		path := filepath.Join(g.outputDir, name)
+
+		// Write to a temp file first so readers never see a partial report
+		tmp := path + ".tmp"
+		f, err := os.Create(tmp)
+		if err != nil {
+			return written, fmt.Errorf("failed to create %s: %w", name, err)
+		}
+		if err := Render(f, r, format); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return written, fmt.Errorf("failed to render %s: %w", name, err)
+		}
+		if err := f.Close(); err != nil {
+			os.Remove(tmp)
+			return written, fmt.Errorf("failed to write %s: %w", name, err)
+		}
+		if err := os.Rename(tmp, path); err != nil {
+			return written, fmt.Errorf("failed to save %s: %w", name, err)
+		}
+		written = append(written, name)
+	}
+	return written, nil
+}
+
+// ListFiles returns the saved report files, newest first
+func (g *Generator) ListFiles() ([]FileInfo, error) {
+	entries, err := os.ReadDir(g.outputDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []FileInfo{}, nil
+		}
+		return nil, fmt.Errorf("failed to read report directory: %w", err)
+	}
+
+	files := make([]FileInfo, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), "sla-") {
+			continue
+		}
+		format := strings.TrimPrefix(filepath.Ext(e.Name()), ".")
+		if format != "json" && format != "csv" && format != "html" {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, FileInfo{
+			Name:    e.Name(),
+			Format:  format,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name > files[j].Name
+	})
+	return files, nil
+}
+
+// FilePath returns the path of a saved report file, rejecting names that
+// would escape the output directory
+func (g *Generator) FilePath(name string) (string, error) {
+	if name != filepath.Base(name) || !strings.HasPrefix(name, "sla-") {
+		return "", fmt.Errorf("invalid report file name: %s", name)
+	}
+	path := filepath.Join(g.outputDir, name)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("report file not found: %s", name)
+	}
+	return path, nil
+}
+
+// exists reports whether the report for a period was already written in all formats
+func (g *Generator) exists(period Period) bool {
+	for _, format := range g.formats {
+		if _, err := os.Stat(filepath.Join(g.outputDir, fileName(period.Label, format))); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// fileName returns the file name for a report label and format
+func fileName(label, format string) string {
+	return "sla-" + label + "." + format
+}
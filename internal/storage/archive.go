@@ -0,0 +1,287 @@
+package storage
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// ArchiveVersion is the current version of the portable archive format
+const ArchiveVersion = 1
+
+// ErrTargetExists is returned when importing into a target that already has history
+var ErrTargetExists = errors.New("target already has history")
+
+// ErrNoHistory is returned when exporting a target that has no history
+var ErrNoHistory = errors.New("no history")
+
+// ImportMode controls how an archive is combined with existing history
+type ImportMode string
+
+const (
+	ImportCreate  ImportMode = "create"  // Fail if the target already has history
+	ImportReplace ImportMode = "replace" // Discard existing history
+	ImportMerge   ImportMode = "merge"   // Keep existing data, fill gaps from the archive (backfill)
+)
+
+// Archive is a portable copy of a target's complete multi-resolution history,
+// independent of the librrd binary format and the data file name
+type Archive struct {
+	Version    int          `json:"version"`
+	Target     string       `json:"target"`
+	StepSecs   int64        `json:"step_secs"`
+	ExportedAt time.Time    `json:"exported_at"`
+	RRAs       []ArchiveRRA `json:"rras"`
+}
+
+// ArchiveRRA holds all rows of one round robin archive
+type ArchiveRRA struct {
+	CF             string         `json:"cf"`
+	ResolutionSecs int64          `json:"resolution_secs"`
+	Rows           int            `json:"rows"`
+	Points         []ArchivePoint `json:"points"`
+}
+
+// ArchivePoint is a JSON-safe data point (nil = NaN/no data)
+type ArchivePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Latency   *float64  `json:"latency"`
+	Loss      *float64  `json:"loss"`
+}
+
+// NewArchivePoint converts latency/loss values to an ArchivePoint
+func NewArchivePoint(ts time.Time, latency, loss float64) ArchivePoint {
+	p := ArchivePoint{Timestamp: ts}
+	if !math.IsNaN(latency) {
+		p.Latency = &latency
+	}
+	if !math.IsNaN(loss) {
+		p.Loss = &loss
+	}
+	return p
+}
+
+// values returns the point's latency and loss with nil mapped to NaN
+func (p ArchivePoint) values() (latency, loss float64) {
+	latency, loss = math.NaN(), math.NaN()
+	if p.Latency != nil {
+		latency = *p.Latency
+	}
+	if p.Loss != nil {
+		loss = *p.Loss
+	}
+	return latency, loss
+}
+
+// Validate checks an archive for structural errors before import
+func (a *Archive) Validate() error {
+	if a.Version != ArchiveVersion {
+		return fmt.Errorf("unsupported archive version %d (expected %d)", a.Version, ArchiveVersion)
+	}
+	if len(a.RRAs) == 0 {
+		return fmt.Errorf("archive contains no RRAs")
+	}
+	for i, rra := range a.RRAs {
+		if rra.ResolutionSecs <= 0 {
+			return fmt.Errorf("rra %d: resolution must be positive", i)
+		}
+		for j := 1; j < len(rra.Points); j++ {
+			if !rra.Points[j].Timestamp.After(rra.Points[j-1].Timestamp) {
+				return fmt.Errorf("rra %d: timestamps must be strictly increasing", i)
+			}
+		}
+	}
+	return nil
+}
+
+// timelinePoint is a consolidated value covering [Timestamp, Timestamp+Resolution)
+type timelinePoint struct {
+	Timestamp  time.Time
+	Resolution time.Duration
+	Latency    float64
+	Loss       float64
+}
+
+// span is a half-open time interval [from, to)
+type span struct {
+	from, to time.Time
+}
+
+// mergeTimeline flattens archives into a single chronological series, using
+// the finest resolution available for every period. Earlier archives take
+// precedence over later ones where both have data.
+func mergeTimeline(archives ...*Archive) []timelinePoint {
+	var covered []span
+	var result []timelinePoint
+
+	for _, a := range archives {
+		if a == nil {
+			continue
+		}
+
+		rras := make([]ArchiveRRA, len(a.RRAs))
+		copy(rras, a.RRAs)
+		sort.SliceStable(rras, func(i, j int) bool {
+			return rras[i].ResolutionSecs < rras[j].ResolutionSecs
+		})
+
+		for _, rra := range rras {
+			res := time.Duration(rra.ResolutionSecs) * time.Second
+			var added []span
+
+			for _, p := range rra.Points {
+				latency, loss := p.values()
+				if math.IsNaN(loss) {
+					continue // No data: leave the period to coarser archives
+				}
+				s := span{from: p.Timestamp, to: p.Timestamp.Add(res)}
+				if overlaps(covered, s) {
+					continue
+				}
+				result = append(result, timelinePoint{
+					Timestamp:  p.Timestamp,
+					Resolution: res,
+					Latency:    latency,
+					Loss:       loss,
+				})
+				added = append(added, s)
+			}
+
+			covered = mergeSpans(append(covered, added...))
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Timestamp.Before(result[j].Timestamp)
+	})
+	return result
+}
+
+// overlaps reports whether s intersects any span in sorted, merged spans
+func overlaps(spans []span, s span) bool {
+	// First span ending after s starts
+	i := sort.Search(len(spans), func(i int) bool {
+		return spans[i].to.After(s.from)
+	})
+	return i < len(spans) && spans[i].from.Before(s.to)
+}
+
+// mergeSpans sorts spans and joins overlapping or adjacent ones
+func mergeSpans(spans []span) []span {
+	if len(spans) == 0 {
+		return spans
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].from.Before(spans[j].from)
+	})
+
+	merged := spans[:1]
+	for _, s := range spans[1:] {
+		last := &merged[len(merged)-1]
+		if !s.from.After(last.to) {
+			if s.to.After(last.to) {
+				last.to = s.to
+			}
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// WriteArchiveCSV writes an archive as CSV with one row per RRA row
+func WriteArchiveCSV(w io.Writer, a *Archive) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write([]string{"cf", "resolution_secs", "timestamp", "latency", "loss"}); err != nil {
+		return err
+	}
+
+	for _, rra := range a.RRAs {
+		res := strconv.FormatInt(rra.ResolutionSecs, 10)
+		for _, p := range rra.Points {
+			row := []string{rra.CF, res, p.Timestamp.UTC().Format(time.RFC3339), "", ""}
+			if p.Latency != nil {
+				row[3] = strconv.FormatFloat(*p.Latency, 'f', -1, 64)
+			}
+			if p.Loss != nil {
+				row[4] = strconv.FormatFloat(*p.Loss, 'f', -1, 64)
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// ReadArchiveCSV parses an archive written by WriteArchiveCSV
+func ReadArchiveCSV(r io.Reader, targetName string) (*Archive, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = 5
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("empty CSV")
+	}
+	if records[0][0] == "cf" {
+		records = records[1:] // Skip header
+	}
+
+	a := &Archive{
+		Version:    ArchiveVersion,
+		Target:     targetName,
+		ExportedAt: time.Now(),
+	}
+	index := make(map[string]int) // cf:resolution -> RRA index
+
+	for i, rec := range records {
+		line := i + 2
+		res, err := strconv.ParseInt(rec[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid resolution_secs %q", line, rec[1])
+		}
+		ts, err := time.Parse(time.RFC3339, rec[2])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid timestamp %q", line, rec[2])
+		}
+		latency, loss := math.NaN(), math.NaN()
+		if rec[3] != "" {
+			if latency, err = strconv.ParseFloat(rec[3], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid latency %q", line, rec[3])
+			}
+		}
+		if rec[4] != "" {
+			if loss, err = strconv.ParseFloat(rec[4], 64); err != nil {
+				return nil, fmt.Errorf("line %d: invalid loss %q", line, rec[4])
+			}
+		}
+
+		key := rec[0] + ":" + rec[1]
+		idx, ok := index[key]
+		if !ok {
+			idx = len(a.RRAs)
+			index[key] = idx
+			a.RRAs = append(a.RRAs, ArchiveRRA{CF: rec[0], ResolutionSecs: res})
+		}
+		a.RRAs[idx].Points = append(a.RRAs[idx].Points, NewArchivePoint(ts, latency, loss))
+		a.RRAs[idx].Rows++
+	}
+
+	for _, rra := range a.RRAs {
+		if a.StepSecs == 0 || rra.ResolutionSecs < a.StepSecs {
+			a.StepSecs = rra.ResolutionSecs
+		}
+	}
+
+	return a, nil
+}
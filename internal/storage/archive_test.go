@@ -0,0 +1,198 @@
+package storage
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+)
+
+// rra builds an RRA with consecutive points at the given resolution (NaN loss = no data)
+func rra(base time.Time, resSecs int64, values ...[2]float64) ArchiveRRA {
+	r := ArchiveRRA{CF: "AVERAGE", ResolutionSecs: resSecs, Rows: len(values)}
+	for i, v := range values {
+		ts := base.Add(time.Duration(int64(i)*resSecs) * time.Second)
+		r.Points = append(r.Points, NewArchivePoint(ts, v[0], v[1]))
+	}
+	return r
+}
+
+func TestMergeTimeline(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	nan := math.NaN()
+
+	tests := []struct {
+		name     string
+		archives []*Archive
+		wantRes  []int64 // resolution of each resulting point
+	}{
+		{
+			name: "fine overrides coarse",
+			archives: []*Archive{{RRAs: []ArchiveRRA{
+				rra(base, 600, [2]float64{10, 0}, [2]float64{20, 0}),
+				rra(base.Add(1200*time.Second), 300, [2]float64{1, 0}, [2]float64{2, 0}),
+			}}},
+			// Coarse points end at base+1200s, fine points start there
+			wantRes: []int64{600, 600, 300, 300},
+		},
+		{
+			name: "coarse dropped where fine exists",
+			archives: []*Archive{{RRAs: []ArchiveRRA{
+				rra(base, 3600, [2]float64{10, 0}, [2]float64{20, 0}),
+				rra(base, 300, [2]float64{1, 0}, [2]float64{2, 0}),
+			}}},
+			// The first hour overlaps the fine points, the second does not
+			wantRes: []int64{300, 300, 3600},
+		},
+		{
+			name: "no data left to coarser archive",
+			archives: []*Archive{{RRAs: []ArchiveRRA{
+				rra(base, 300, [2]float64{nan, nan}, [2]float64{nan, nan}),
+				rra(base, 600, [2]float64{5, 0}),
+			}}},
+			wantRes: []int64{600},
+		},
+		{
+			name: "earlier archive takes precedence",
+			archives: []*Archive{
+				{RRAs: []ArchiveRRA{rra(base, 600, [2]float64{10, 0})}},
+				{RRAs: []ArchiveRRA{rra(base, 300, [2]float64{1, 0}, [2]float64{2, 0}, [2]float64{3, 0})}},
+			},
+			// Only the third fine point lies outside the existing coarse point
+			wantRes: []int64{600, 300},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTimeline(tt.archives...)
+			if len(got) != len(tt.wantRes) {
+				t.Fatalf("got %d points, want %d", len(got), len(tt.wantRes))
+			}
+			for i, p := range got {
+				if want := time.Duration(tt.wantRes[i]) * time.Second; p.Resolution != want {
+					t.Errorf("point %d resolution = %v, want %v", i, p.Resolution, want)
+				}
+				if i > 0 && !p.Timestamp.After(got[i-1].Timestamp) {
+					t.Errorf("point %d not in chronological order", i)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeSpans(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	at := func(secs int) time.Time { return base.Add(time.Duration(secs) * time.Second) }
+
+	merged := mergeSpans([]span{
+		{at(600), at(900)},
+		{at(0), at(300)},
+		{at(300), at(400)},  // Adjacent
+		{at(850), at(1000)}, // Overlapping
+	})
+	want := []span{{at(0), at(400)}, {at(600), at(1000)}}
+	if len(merged) != len(want) {
+		t.Fatalf("got %d spans, want %d", len(merged), len(want))
+	}
+	for i := range want {
+		if !merged[i].from.Equal(want[i].from) || !merged[i].to.Equal(want[i].to) {
+			t.Errorf("span %d = %v-%v, want %v-%v", i, merged[i].from, merged[i].to, want[i].from, want[i].to)
+		}
+	}
+
+	tests := []struct {
+		name string
+		s    span
+		want bool
+	}{
+		{"inside", span{at(100), at(200)}, true},
+		{"gap", span{at(400), at(600)}, false},
+		{"touching end", span{at(1000), at(1100)}, false},
+		{"straddling", span{at(500), at(700)}, true},
+	}
+	for _, tt := range tests {
+		if got := overlaps(merged, tt.s); got != tt.want {
+			t.Errorf("overlaps(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestArchiveCSVRoundTrip(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	nan := math.NaN()
+
+	orig := &Archive{
+		Version:  ArchiveVersion,
+		Target:   "gw",
+		StepSecs: 300,
+		RRAs: []ArchiveRRA{
+			rra(base, 300, [2]float64{12.5, 0}, [2]float64{nan, 1}, [2]float64{nan, nan}),
+			rra(base, 3600, [2]float64{13.25, 0.01}),
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteArchiveCSV(&buf, orig); err != nil {
+		t.Fatalf("WriteArchiveCSV() error = %v", err)
+	}
+	got, err := ReadArchiveCSV(&buf, "gw")
+	if err != nil {
+		t.Fatalf("ReadArchiveCSV() error = %v", err)
+	}
+	if err := got.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	if got.StepSecs != 300 || len(got.RRAs) != 2 {
+		t.Fatalf("got step %d with %d RRAs, want 300 with 2", got.StepSecs, len(got.RRAs))
+	}
+	for i, r := range got.RRAs {
+		want := orig.RRAs[i]
+		if r.ResolutionSecs != want.ResolutionSecs || len(r.Points) != len(want.Points) {
+			t.Fatalf("rra %d = %ds/%d points, want %ds/%d points",
+				i, r.ResolutionSecs, len(r.Points), want.ResolutionSecs, len(want.Points))
+		}
+		for j, p := range r.Points {
+			gl, gs := p.values()
+			wl, ws := want.Points[j].values()
+			if !sameFloat(gl, wl) || !sameFloat(gs, ws) || !p.Timestamp.Equal(want.Points[j].Timestamp) {
+				t.Errorf("rra %d point %d = %v/%v, want %v/%v", i, j, gl, gs, wl, ws)
+			}
+		}
+	}
+}
+
+func TestArchiveValidate(t *testing.T) {
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	unordered := rra(base, 300, [2]float64{1, 0}, [2]float64{2, 0})
+	unordered.Points[1].Timestamp = base
+
+	tests := []struct {
+		name    string
+		archive Archive
+		wantErr bool
+	}{
+		{"valid", Archive{Version: ArchiveVersion, RRAs: []ArchiveRRA{rra(base, 300, [2]float64{1, 0})}}, false},
+		{"wrong version", Archive{Version: 99, RRAs: []ArchiveRRA{rra(base, 300)}}, true},
+		{"no rras", Archive{Version: ArchiveVersion}, true},
+		{"bad resolution", Archive{Version: ArchiveVersion, RRAs: []ArchiveRRA{{ResolutionSecs: 0}}}, true},
+		{"unordered", Archive{Version: ArchiveVersion, RRAs: []ArchiveRRA{unordered}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.archive.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// sameFloat compares floats treating NaN as equal to NaN
+func sameFloat(a, b float64) bool {
+	if math.IsNaN(a) || math.IsNaN(b) {
+		return math.IsNaN(a) && math.IsNaN(b)
+	}
+	return a == b
+}
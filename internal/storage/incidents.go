@@ -0,0 +1,327 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// incidentFileName is the data_dir file holding the incident log
+const incidentFileName = "events.jsonl"
+
+// IncidentKind classifies an incident
+type IncidentKind string
+
+// Incident kinds
+const (
+	IncidentDown          IncidentKind = "down"           // Target unreachable
+	IncidentDegraded      IncidentKind = "degraded"       // Target reachable, but lossy or slow
+	IncidentPathChanged   IncidentKind = "path_changed"   // Lasting latency step, usually a routing change
+	IncidentConfigChanged IncidentKind = "config_changed" // Target added, removed, changed or renamed
+	IncidentDaemonRestart IncidentKind = "daemon_restart" // Collector started
+	IncidentMaintenance   IncidentKind = "maintenance"    // Target silenced by a maintenance window or silence
+	IncidentUnreachable   IncidentKind = "unreachable"    // Target failing while the target it depends on is down
+)
+
+// Incident is a state a target or the daemon was in, from Start until End.
+// Instant events (path and config changes, restarts) end when they start.
+type Incident struct {
+	ID           int64        `json:"id"`
+	Target       string       `json:"target,omitempty"` // Empty for daemon-wide incidents
+	Kind         IncidentKind `json:"kind"`
+	Cause        string       `json:"cause,omitempty"` // What detected it, e.g. alert or anomaly
+	Message      string       `json:"message"`
+	Start        time.Time    `json:"start"`
+	End          time.Time    `json:"end,omitzero"`  // Zero while ongoing
+	DurationSecs float64      `json:"duration_secs"` // Up to now while ongoing
+	Ongoing      bool         `json:"ongoing"`
+
+	// Config lists each target's endpoint at a daemon restart, so the next
+	// restart can tell what changed
+	Config map[string]string `json:"config,omitempty"`
+}
+
+// IncidentQuery selects incidents
+type IncidentQuery struct {
+	Target  string       // Only this target ("" = all, including daemon-wide)
+	Kind    IncidentKind // Only this kind ("" = all)
+	From    time.Time    // Only incidents overlapping From..To (zero = unbounded)
+	To      time.Time
+	Ongoing bool // Only ongoing incidents
+	Limit   int  // At most this many, newest first (0 = all)
+}
+
+// IncidentLog records incidents in memory and, given a data directory, in an
+// append-only JSON lines file next to the RRD files. Each line is the full
+// incident after a change; the last line of an id wins.
+type IncidentLog struct {
+	path   string // Empty for a memory-only log
+	maxAge time.Duration
+
+	incidents []Incident // Oldest first
+	nextID    int64
+	lines     int // Lines in the file, compacted when far above len(incidents)
+	mu        sync.Mutex
+}
+
+// OpenIncidentLog loads the incident log of a data directory, dropping
+// incidents that ended more than maxAge ago (0 = keep all). With an empty
+// dataDir the log is kept in memory only.
+func OpenIncidentLog(dataDir string, maxAge time.Duration) (*IncidentLog, error) {
+	l := &IncidentLog{maxAge: maxAge, nextID: 1}
+	if dataDir == "" {
+		return l, nil
+	}
+	if err := os.MkdirAll(dataDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %w", err)
+	}
+	l.path = filepath.Join(dataDir, incidentFileName)
+
+	f, err := os.Open(l.path)
+	if os.IsNotExist(err) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open incident log: %w", err)
+	}
+	defer f.Close()
+
+	byID := make(map[int64]Incident)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		var inc Incident
+		if err := json.Unmarshal(scanner.Bytes(), &inc); err != nil || inc.ID <= 0 {
+			// A line cut short by a crash is skipped, not fatal
+			continue
+		}
+		byID[inc.ID] = inc
+		l.nextID = max(l.nextID, inc.ID+1)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read incident log: %w", err)
+	}
+
+	for _, inc := range byID {
+		l.incidents = append(l.incidents, inc)
+	}
+	sort.Slice(l.incidents, func(i, j int) bool { return l.incidents[i].ID < l.incidents[j].ID })
+
+	if err := l.compactLocked(time.Now()); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// Add records a new incident and returns it with its id. An incident with an
+// End equal to its Start is an instant event.
+func (l *IncidentLog) Add(inc Incident) (Incident, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	inc.ID = l.nextID
+	l.nextID++
+	inc.Ongoing = inc.End.IsZero()
+	if !inc.Ongoing {
+		inc.DurationSecs = inc.End.Sub(inc.Start).Seconds()
+	}
+	l.incidents = append(l.incidents, inc)
+	return inc, l.appendLocked(inc)
+}
+
+// End closes the ongoing incident of a target with the given cause at end,
+// optionally appending to its message. It returns the closed incident, or
+// nil if none was ongoing.
+func (l *IncidentLog) End(target, cause string, end time.Time, note string) (*Incident, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for i := len(l.incidents) - 1; i >= 0; i-- {
+		inc := &l.incidents[i]
+		if inc.Ongoing && inc.Target == target && inc.Cause == cause {
+			l.endLocked(inc, end, note)
+			closed := *inc
+			return &closed, l.appendLocked(closed)
+		}
+	}
+	return nil, nil
+}
+
+// EndAll closes every ongoing incident at end, e.g. those left open by a
+// previous run, and returns how many were closed
+func (l *IncidentLog) EndAll(end time.Time, note string) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	closed := 0
+	for i := range l.incidents {
+		inc := &l.incidents[i]
+		if !inc.Ongoing {
+			continue
+		}
+		l.endLocked(inc, end, note)
+		if err := l.appendLocked(*inc); err != nil {
+			return closed, err
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// endLocked closes an incident. Must be called with mu held
+func (l *IncidentLog) endLocked(inc *Incident, end time.Time, note string) {
+	if end.Before(inc.Start) {
+		end = inc.Start
+	}
+	inc.End = end
+	inc.Ongoing = false
+	inc.DurationSecs = end.Sub(inc.Start).Seconds()
+	if note != "" {
+		inc.Message += " (" + note + ")"
+	}
+}
+
+// Query returns the incidents matching q, newest first
+func (l *IncidentLog) Query(q IncidentQuery) []Incident {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	result := make([]Incident, 0)
+	for i := len(l.incidents) - 1; i >= 0; i-- {
+		inc := l.incidents[i]
+		if q.Target != "" && inc.Target != q.Target {
+			continue
+		}
+		if q.Kind != "" && inc.Kind != q.Kind {
+			continue
+		}
+		if q.Ongoing && !inc.Ongoing {
+			continue
+		}
+		if !q.To.IsZero() && inc.Start.After(q.To) {
+			continue
+		}
+		if !q.From.IsZero() && !inc.Ongoing && inc.End.Before(q.From) {
+			continue
+		}
+		if inc.Ongoing {
+			inc.DurationSecs = now.Sub(inc.Start).Seconds()
+		}
+		result = append(result, inc)
+		if q.Limit > 0 && len(result) == q.Limit {
+			break
+		}
+	}
+	return result
+}
+
+// Last returns the newest incident of a kind, or nil
+func (l *IncidentLog) Last(kind IncidentKind) *Incident {
+	if found := l.Query(IncidentQuery{Kind: kind, Limit: 1}); len(found) > 0 {
+		return &found[0]
+	}
+	return nil
+}
+
+// Rename moves a target's incidents to its new name
+func (l *IncidentLog) Rename(oldName, newName string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	renamed := false
+	for i := range l.incidents {
+		if l.incidents[i].Target == oldName {
+			l.incidents[i].Target = newName
+			renamed = true
+		}
+	}
+	if !renamed {
+		return nil
+	}
+	return l.rewriteLocked()
+}
+
+// appendLocked writes an incident to the file, compacting it when it has
+// grown well beyond the live incidents. Must be called with mu held
+func (l *IncidentLog) appendLocked(inc Incident) error {
+	if l.path == "" {
+		return nil
+	}
+	if l.lines > 2*len(l.incidents)+1000 {
+		return l.compactLocked(time.Now())
+	}
+
+	line, err := json.Marshal(inc)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open incident log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write incident log: %w", err)
+	}
+	l.lines++
+	return nil
+}
+
+// compactLocked drops incidents that ended more than maxAge before now and
+// rewrites the file with one line per incident. Must be called with mu held
+func (l *IncidentLog) compactLocked(now time.Time) error {
+	if l.maxAge > 0 {
+		cutoff := now.Add(-l.maxAge)
+		kept := l.incidents[:0]
+		for _, inc := range l.incidents {
+			if inc.Ongoing || !inc.End.Before(cutoff) {
+				kept = append(kept, inc)
+			}
+		}
+		l.incidents = kept
+	}
+	return l.rewriteLocked()
+}
+
+// rewriteLocked replaces the file with the current incidents. Must be called
+// with mu held
+func (l *IncidentLog) rewriteLocked() error {
+	if l.path == "" {
+		return nil
+	}
+
+	tmp := l.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return fmt.Errorf("failed to rewrite incident log: %w", err)
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, inc := range l.incidents {
+		if err := enc.Encode(inc); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return fmt.Errorf("failed to rewrite incident log: %w", err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return fmt.Errorf("failed to rewrite incident log: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to rewrite incident log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to rewrite incident log: %w", err)
+	}
+	l.lines = len(l.incidents)
+	return nil
+}
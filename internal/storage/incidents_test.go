@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIncidentLog(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now().Truncate(time.Second)
+
+	l, err := OpenIncidentLog(dir, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("OpenIncidentLog() error = %v", err)
+	}
+
+	// An old incident beyond maxAge, an ended outage, an ongoing one and an instant event
+	add := func(inc Incident) Incident {
+		t.Helper()
+		inc, err := l.Add(inc)
+		if err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+		return inc
+	}
+	add(Incident{Target: "gw", Kind: IncidentDown, Cause: "alert", Start: now.Add(-40 * 24 * time.Hour), End: now.Add(-39 * 24 * time.Hour)})
+	add(Incident{Target: "gw", Kind: IncidentDown, Cause: "alert", Start: now.Add(-2 * time.Hour)})
+	add(Incident{Target: "dns", Kind: IncidentDegraded, Cause: "anomaly", Start: now.Add(-30 * time.Minute)})
+	shift := add(Incident{Target: "gw", Kind: IncidentPathChanged, Start: now.Add(-time.Hour), End: now.Add(-time.Hour)})
+	if shift.Ongoing || shift.DurationSecs != 0 {
+		t.Errorf("instant event = %+v, want ended with no duration", shift)
+	}
+
+	closed, err := l.End("gw", "alert", now.Add(-90*time.Minute), "")
+	if err != nil || closed == nil {
+		t.Fatalf("End() = %v, %v", closed, err)
+	}
+	if closed.DurationSecs != 1800 || closed.Ongoing {
+		t.Errorf("closed = %+v, want a 30m incident", closed)
+	}
+	if again, _ := l.End("gw", "alert", now, ""); again != nil {
+		t.Errorf("End() with nothing ongoing = %+v, want nil", again)
+	}
+
+	// Reopen: the last line of each id wins and the old incident is dropped
+	l, err = OpenIncidentLog(dir, 30*24*time.Hour)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   IncidentQuery
+		wantIDs []int64
+	}{
+		{"all, newest first", IncidentQuery{}, []int64{4, 3, 2}},
+		{"target", IncidentQuery{Target: "gw"}, []int64{4, 2}},
+		{"kind", IncidentQuery{Kind: IncidentDown}, []int64{2}},
+		{"ongoing", IncidentQuery{Ongoing: true}, []int64{3}},
+		{"overlapping range", IncidentQuery{From: now.Add(-100 * time.Minute), To: now.Add(-80 * time.Minute)}, []int64{2}},
+		{"limit", IncidentQuery{Limit: 1}, []int64{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := l.Query(tt.query)
+			if len(got) != len(tt.wantIDs) {
+				t.Fatalf("Query() returned %d incidents, want %d: %+v", len(got), len(tt.wantIDs), got)
+			}
+			for i, inc := range got {
+				if inc.ID != tt.wantIDs[i] {
+					t.Errorf("incident %d id = %d, want %d", i, inc.ID, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+
+	if n, err := l.EndAll(now, "closed at restart"); n != 1 || err != nil {
+		t.Errorf("EndAll() = %d, %v, want 1 closed", n, err)
+	}
+	if err := l.Rename("gw", "router"); err != nil {
+		t.Fatalf("Rename() error = %v", err)
+	}
+	next, _ := l.Add(Incident{Kind: IncidentDaemonRestart, Start: now, End: now})
+	if next.ID != 5 {
+		t.Errorf("next id = %d, want 5", next.ID)
+	}
+
+	l, err = OpenIncidentLog(dir, 0)
+	if err != nil {
+		t.Fatalf("reopen error = %v", err)
+	}
+	if got := l.Query(IncidentQuery{Target: "router"}); len(got) != 2 {
+		t.Errorf("renamed incidents = %+v, want 2", got)
+	}
+	if got := l.Query(IncidentQuery{Ongoing: true}); len(got) != 0 {
+		t.Errorf("ongoing after EndAll = %+v, want none", got)
+	}
+	if last := l.Last(IncidentDaemonRestart); last == nil || last.ID != 5 {
+		t.Errorf("Last(daemon_restart) = %+v, want id 5", last)
+	}
+}
+
+func TestIncidentLogSkipsTornLines(t *testing.T) {
+	dir := t.TempDir()
+	content := `{"id":1,"target":"gw","kind":"down","message":"unreachable","start":"2024-01-15T10:00:00Z","ongoing":true}
+{"id":1,"target":"gw","kind":"down","message":"unreachable","start":"2024-01-15T10:00:00Z","end":"2024-01-15T10:05:00Z","duration_secs":300}
+{"id":2,"target":"gw","ki`
+	if err := os.WriteFile(filepath.Join(dir, incidentFileName), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := OpenIncidentLog(dir, 0)
+	if err != nil {
+		t.Fatalf("OpenIncidentLog() error = %v", err)
+	}
+	got := l.Query(IncidentQuery{})
+	if len(got) != 1 || got[0].Ongoing || got[0].DurationSecs != 300 {
+		t.Errorf("Query() = %+v, want the ended incident only", got)
+	}
+	if inc, _ := l.Add(Incident{Kind: IncidentDaemonRestart}); inc.ID != 2 {
+		t.Errorf("next id = %d, want 2", inc.ID)
+	}
+}
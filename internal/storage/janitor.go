@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// archiveDirName is the data_dir subdirectory holding archived orphan files
+const archiveDirName = "archive"
+
+// FileUsage describes the disk usage of one target's data file
+type FileUsage struct {
+	ID        string    `json:"id"`
+	Target    string    `json:"target,omitempty"` // Configured target name (empty for orphans)
+	Orphan    bool      `json:"orphan"`           // Not linked to any configured target
+	SizeBytes int64     `json:"size_bytes"`
+	ModTime   time.Time `json:"modified"`
+}
+
+// Usage summarizes the data files in the data directory
+type Usage struct {
+	TotalBytes int64       `json:"total_bytes"`
+	FileCount  int         `json:"file_count"`
+	Files      []FileUsage `json:"files"`
+}
+
+// Usage returns the size of every RRD file in the data directory, sorted by id.
+// Target and Orphan are left for the caller to fill in.
+func (s *RRDStorage) Usage() (*Usage, error) {
+	files, err := filepath.Glob(filepath.Join(s.dataDir, "*.rrd"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	usage := &Usage{Files: make([]FileUsage, 0, len(files))}
+	for _, filename := range files {
+		info, err := os.Stat(filename)
+		if err != nil {
+			continue // Removed since the glob
+		}
+		usage.Files = append(usage.Files, FileUsage{
+			ID:        strings.TrimSuffix(filepath.Base(filename), ".rrd"),
+			SizeBytes: info.Size(),
+			ModTime:   info.ModTime(),
+		})
+		usage.TotalBytes += info.Size()
+	}
+	usage.FileCount = len(usage.Files)
+	return usage, nil
+}
+
+// Remove deletes a target's data file, or moves it to data_dir/archive when
+// archive is true. It returns the archive path, if any.
+func (s *RRDStorage) Remove(targetID string, archive bool) (string, error) {
+	filename := s.getFilename(targetID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, migrating := s.migrating[filename]; migrating {
+		return "", fmt.Errorf("history for %s is being rebuilt, try again later", targetID)
+	}
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return "", fmt.Errorf("no history for target id %s", targetID)
+	}
+
+	delete(s.updaters, targetID)
+
+	if !archive {
+		if err := os.Remove(filename); err != nil {
+			return "", fmt.Errorf("failed to delete RRD file: %w", err)
+		}
+		return "", nil
+	}
+
+	dir := filepath.Join(s.dataDir, archiveDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create archive directory: %w", err)
+	}
+	base := strings.TrimSuffix(filepath.Base(filename), ".rrd")
+	dest := filepath.Join(dir, base+"-"+time.Now().Format("20060102-150405")+".rrd")
+	if err := os.Rename(filename, dest); err != nil {
+		return "", fmt.Errorf("failed to archive RRD file: %w", err)
+	}
+	return dest, nil
+}
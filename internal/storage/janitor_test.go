@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageAndRemove(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewRRDStorage(dir, 10*time.Second, "10s:1d", 0.5, "average")
+	if err != nil {
+		t.Fatalf("NewRRDStorage() error = %v", err)
+	}
+
+	for name, size := range map[string]int{"gw.rrd": 100, "old_router.rrd": 50, "notes.txt": 10} {
+		if err := os.WriteFile(filepath.Join(dir, name), make([]byte, size), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	usage, err := s.Usage()
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+	if usage.FileCount != 2 || usage.TotalBytes != 150 {
+		t.Fatalf("Usage() = %d files / %d bytes, want 2 / 150", usage.FileCount, usage.TotalBytes)
+	}
+	if usage.Files[0].ID != "gw" || usage.Files[1].ID != "old_router" {
+		t.Errorf("Usage() ids = %q, %q, want gw, old_router", usage.Files[0].ID, usage.Files[1].ID)
+	}
+
+	dest, err := s.Remove("old_router", true)
+	if err != nil {
+		t.Fatalf("Remove(archive) error = %v", err)
+	}
+	if _, err := os.Stat(dest); err != nil {
+		t.Errorf("archived file missing: %v", err)
+	}
+	if filepath.Dir(dest) != filepath.Join(dir, archiveDirName) {
+		t.Errorf("archive path = %s, want in %s", dest, archiveDirName)
+	}
+
+	if _, err := s.Remove("gw", false); err != nil {
+		t.Fatalf("Remove(delete) error = %v", err)
+	}
+	if _, err := s.Remove("gw", false); err == nil {
+		t.Error("Remove() of missing file expected error")
+	}
+
+	if usage, _ := s.Usage(); usage.FileCount != 0 {
+		t.Errorf("Usage() after removal = %d files, want 0", usage.FileCount)
+	}
+}
@@ -23,13 +23,15 @@ type targetBuffer struct {
 	count           int  // Number of valid samples
 	full            bool // Whether buffer has wrapped
 	lastUpdate      time.Time
-	firstSuccessIdx int  // Index of first successful ping (-1 if none yet)
-	hasFirstSuccess bool // Whether we've had a successful ping
+	firstSuccessIdx int    // Index of first successful ping (-1 if none yet)
+	hasFirstSuccess bool   // Whether we've had a successful ping
+	floor           uint64 // Every sample numbered above this is buffered
 	mu              sync.RWMutex
 }
 
 // sample represents a single measurement
 type sample struct {
+	seq       uint64 // Position in the order results were recorded
 	timestamp time.Time
 	latencyMs float64 // -1 for packet loss
 }
@@ -45,8 +47,9 @@ func NewMemoryBuffer(bufferSize int) *MemoryBuffer {
 	}
 }
 
-// Write stores a latency value for a target
-func (m *MemoryBuffer) Write(targetName string, timestamp time.Time, latencyMs float64) {
+// Write stores a latency value for a target. seq numbers the sample; it must
+// increase with every write.
+func (m *MemoryBuffer) Write(targetName string, seq uint64, timestamp time.Time, latencyMs float64) {
 	m.mu.RLock()
 	tb, exists := m.targets[targetName]
 	m.mu.RUnlock()
@@ -58,6 +61,7 @@ func (m *MemoryBuffer) Write(targetName string, timestamp time.Time, latencyMs f
 			tb = &targetBuffer{
 				samples:         make([]sample, m.bufferSize),
 				firstSuccessIdx: -1, // No successful ping yet
+				floor:           seq - 1,
 			}
 			m.targets[targetName] = tb
 		}
@@ -68,7 +72,11 @@ func (m *MemoryBuffer) Write(targetName string, timestamp time.Time, latencyMs f
 	defer tb.mu.Unlock()
 
 	currentIdx := tb.head
+	if tb.count == m.bufferSize {
+		tb.floor = tb.samples[tb.head].seq // Overwritten below
+	}
 	tb.samples[tb.head] = sample{
+		seq:       seq,
 		timestamp: timestamp,
 		latencyMs: latencyMs,
 	}
@@ -151,6 +159,62 @@ func (m *MemoryBuffer) GetHistory(targetName string, count int) []float64 {
 	return result
 }
 
+// Samples returns the buffered samples of a target newer than since and
+// numbered above after, oldest first, as data points (NaN value and loss 1 for
+// packet loss). With count > 0 only the newest count samples are returned.
+func (m *MemoryBuffer) Samples(targetName string, since time.Time, after uint64, count int) []DataPoint {
+	m.mu.RLock()
+	tb, exists := m.targets[targetName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return []DataPoint{}
+	}
+
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+
+	start := tb.head - tb.count
+	if start < 0 {
+		start += m.bufferSize
+	}
+
+	points := make([]DataPoint, 0, tb.count)
+	for i := 0; i < tb.count; i++ {
+		s := tb.samples[(start+i)%m.bufferSize]
+		if !s.timestamp.After(since) || s.seq <= after {
+			continue
+		}
+		point := DataPoint{Timestamp: s.timestamp, Value: s.latencyMs, Loss: 0, Seq: s.seq}
+		if s.latencyMs < 0 {
+			point.Value = math.NaN()
+			point.Loss = 1
+		}
+		points = append(points, point)
+	}
+
+	if count > 0 && len(points) > count {
+		points = points[len(points)-count:]
+	}
+	return points
+}
+
+// Holds reports whether every sample of a target numbered above after is
+// still buffered
+func (m *MemoryBuffer) Holds(targetName string, after uint64) bool {
+	m.mu.RLock()
+	tb, exists := m.targets[targetName]
+	m.mu.RUnlock()
+
+	if !exists {
+		return false
+	}
+
+	tb.mu.RLock()
+	defer tb.mu.RUnlock()
+	return after >= tb.floor
+}
+
 // GetAllStats returns statistics for all targets
 func (m *MemoryBuffer) GetAllStats() map[string]*Stats {
 	m.mu.RLock()
@@ -165,6 +229,17 @@ func (m *MemoryBuffer) GetAllStats() map[string]*Stats {
 	return result
 }
 
+// Rename moves a target's buffered samples to a new name
+func (m *MemoryBuffer) Rename(oldName, newName string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if tb, exists := m.targets[oldName]; exists {
+		m.targets[newName] = tb
+		delete(m.targets, oldName)
+	}
+}
+
 // calculateStats computes statistics from a target buffer
 // Must be called with tb.mu held
 func calculateStats(targetName string, tb *targetBuffer, bufferSize int) *Stats {
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMemoryBufferSamples(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := NewMemoryBuffer(4)
+	for i, latency := range []float64{10, 11, -1, 13, 14, 15} {
+		m.Write("A", uint64(i+1), start.Add(time.Duration(i)*10*time.Second), latency)
+	}
+
+	tests := []struct {
+		name  string
+		since time.Time
+		after uint64
+		count int
+		want  []float64 // Values, NaN for loss
+	}{
+		{"all buffered", time.Time{}, 0, 0, []float64{math.NaN(), 13, 14, 15}},
+		{"newest two", time.Time{}, 0, 2, []float64{14, 15}},
+		{"since", start.Add(30 * time.Second), 0, 0, []float64{14, 15}},
+		{"since and count", start.Add(20 * time.Second), 0, 1, []float64{15}},
+		{"after", time.Time{}, 4, 0, []float64{14, 15}},
+		{"nothing newer", start.Add(time.Hour), 0, 0, []float64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := m.Samples("A", tt.since, tt.after, tt.count)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Samples() returned %d points, want %d", len(got), len(tt.want))
+			}
+			for i, p := range got {
+				if math.IsNaN(tt.want[i]) {
+					if !math.IsNaN(p.Value) || p.Loss != 1 {
+						t.Errorf("point %d = %v/%v, want loss", i, p.Value, p.Loss)
+					}
+				} else if p.Value != tt.want[i] || p.Loss != 0 {
+					t.Errorf("point %d = %v/%v, want %v", i, p.Value, p.Loss, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if got := m.Samples("missing", time.Time{}, 0, 0); len(got) != 0 {
+		t.Errorf("Samples() for unknown target = %v, want empty", got)
+	}
+}
+
+func TestMemoryBufferHolds(t *testing.T) {
+	m := NewMemoryBuffer(2)
+	for seq := uint64(5); seq <= 7; seq++ {
+		m.Write("A", seq, time.Now(), 10)
+	}
+
+	tests := []struct {
+		target string
+		after  uint64
+		want   bool
+	}{
+		{"A", 7, true},
+		{"A", 5, true},  // 6 and 7 are buffered
+		{"A", 4, false}, // 5 was overwritten
+		{"missing", 0, false},
+	}
+	for _, tt := range tests {
+		if got := m.Holds(tt.target, tt.after); got != tt.want {
+			t.Errorf("Holds(%q, %d) = %v, want %v", tt.target, tt.after, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ziutek/rrd"
+)
+
+// Migration states
+const (
+	MigrationIdle    = "idle"    // No migration has been needed
+	MigrationRunning = "running" // Files are being rebuilt
+	MigrationDone    = "done"    // All outdated files were processed
+)
+
+// backupDirName is the data_dir subdirectory holding pre-migration copies
+const backupDirName = "backup"
+
+// Migrator is implemented by storage backends that can upgrade existing data
+// files to the configured schema
+type Migrator interface {
+	// Migrate rebuilds outdated files until done or ctx is cancelled
+	Migrate(ctx context.Context)
+
+	// MigrationStatus returns the progress of the current or last migration
+	MigrationStatus() MigrationStatus
+}
+
+// MigrationStatus reports schema migration progress
+type MigrationStatus struct {
+	State      string     `json:"state"`
+	Total      int        `json:"total_files"`
+	Migrated   int        `json:"migrated_files"`
+	Failed     int        `json:"failed_files"`
+	Current    string     `json:"current,omitempty"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	BackupDir  string     `json:"backup_dir,omitempty"`
+	Errors     []string   `json:"errors,omitempty"`
+}
+
+// pendingWrite is an update received while its file was being migrated
+type pendingWrite struct {
+	timestamp time.Time
+	latency   interface{}
+	loss      interface{}
+}
+
+// MigrationStatus returns the progress of the current or last migration
+func (s *RRDStorage) MigrationStatus() MigrationStatus {
+	s.migrationMu.RLock()
+	defer s.migrationMu.RUnlock()
+
+	status := s.migration
+	status.Errors = append([]string(nil), s.migration.Errors...)
+	return status
+}
+
+// Migrate finds RRD files whose step or archives differ from the configuration
+// and rebuilds them, resampling the old data into the new archives. The
+// original files are moved to data_dir/backup. Writes arriving while a file is
+// rebuilt are buffered and replayed afterwards.
+func (s *RRDStorage) Migrate(ctx context.Context) {
+	files, err := s.outdatedFiles()
+	if err != nil {
+		log.Printf("[Storage] Failed to check RRD schemas: %v", err)
+		return
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	backupDir := filepath.Join(s.dataDir, backupDirName, time.Now().Format("20060102-150405"))
+	started := time.Now()
+	s.updateMigration(func(m *MigrationStatus) {
+		*m = MigrationStatus{
+			State:     MigrationRunning,
+			Total:     len(files),
+			StartedAt: &started,
+			BackupDir: backupDir,
+		}
+	})
+	log.Printf("[Storage] Migrating %d RRD file(s) to the current schema (backups in %s)", len(files), backupDir)
+
+	for _, filename := range files {
+		if ctx.Err() != nil {
+			break
+		}
+
+		name := filepath.Base(filename)
+		s.updateMigration(func(m *MigrationStatus) { m.Current = name })
+
+		if err := s.migrateFile(filename, backupDir); err != nil {
+			log.Printf("[Storage] Failed to migrate %s: %v", name, err)
+			s.updateMigration(func(m *MigrationStatus) {
+				m.Failed++
+				m.Errors = append(m.Errors, fmt.Sprintf("%s: %v", name, err))
+			})
+			continue
+		}
+		s.updateMigration(func(m *MigrationStatus) { m.Migrated++ })
+	}
+
+	finished := time.Now()
+	s.updateMigration(func(m *MigrationStatus) {
+		m.State = MigrationDone
+		m.Current = ""
+		m.FinishedAt = &finished
+	})
+	status := s.MigrationStatus()
+	log.Printf("[Storage] Migration finished: %d migrated, %d failed in %s",
+		status.Migrated, status.Failed, finished.Sub(started).Round(time.Second))
+}
+
+// updateMigration applies fn to the migration status under its lock
+func (s *RRDStorage) updateMigration(fn func(m *MigrationStatus)) {
+	s.migrationMu.Lock()
+	fn(&s.migration)
+	s.migrationMu.Unlock()
+}
+
+// beginRebuild buffers writes to an RRD file while it is rebuilt by a
+// migration or import. It returns false if the file is already being rebuilt.
+func (s *RRDStorage) beginRebuild(filename string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, migrating := s.migrating[filename]; migrating {
+		return false
+	}
+	s.migrating[filename] = nil
+	return true
+}
+
+// endRebuild stops buffering writes to an RRD file and replays the buffered
+// ones into it; into the old file if the rebuild failed. Replaying under the
+// lock keeps newer writes from overtaking buffered ones.
+func (s *RRDStorage) endRebuild(filename string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replay(filename, s.migrating[filename])
+	delete(s.migrating, filename)
+}
+
+// outdatedFiles returns the RRD files in the data directory that do not match
+// the configured schema
+func (s *RRDStorage) outdatedFiles() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(s.dataDir, "*.rrd"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+
+	var outdated []string
+	for _, filename := range files {
+		info, err := rrd.Info(filename)
+		if err != nil {
+			log.Printf("[Storage] Skipping unreadable RRD file %s: %v", filepath.Base(filename), err)
+			continue
+		}
+		if !schemaMatches(info, uint(s.step.Seconds()), s.aggregation, s.rras) {
+			outdated = append(outdated, filename)
+		}
+	}
+	return outdated, nil
+}
+
+// schemaMatches reports whether rrd.Info output describes a file with the
+// given step, consolidation function and archives
+func schemaMatches(info map[string]interface{}, step uint, cf string, rras []rraConfig) bool {
+	if infoUint(info["step"]) != step {
+		return false
+	}
+
+	cfs := infoList(info["rra.cf"])
+	rows := infoList(info["rra.rows"])
+	pdpPerRow := infoList(info["rra.pdp_per_row"])
+	if len(cfs) != len(rras) || len(rows) != len(rras) || len(pdpPerRow) != len(rras) {
+		return false
+	}
+
+	for i, rra := range rras {
+		if fileCF, _ := cfs[i].(string); !strings.EqualFold(fileCF, cf) {
+			return false
+		}
+		if infoUint(rows[i]) != uint(rra.rows) || infoUint(pdpPerRow[i]) != uint(rra.steps) {
+			return false
+		}
+	}
+	return true
+}
+
+// migrateFile rebuilds a single RRD file with the configured schema
+func (s *RRDStorage) migrateFile(filename, backupDir string) error {
+	if !s.beginRebuild(filename) {
+		return fmt.Errorf("%s is already being rebuilt", filepath.Base(filename))
+	}
+	defer s.endRebuild(filename)
+
+	archive, err := exportFile(filename)
+	if err != nil {
+		return fmt.Errorf("failed to read history: %w", err)
+	}
+
+	tmp := filename + ".tmp"
+	if err := s.buildTimeline(tmp, mergeTimeline(archive)); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backup := filepath.Join(backupDir, filepath.Base(filename))
+	if err := os.Rename(filename, backup); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to back up RRD file: %w", err)
+	}
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		os.Rename(backup, filename) // Restore the original
+		return fmt.Errorf("failed to replace RRD file: %w", err)
+	}
+	return nil
+}
+
+// replay writes updates that were buffered during a migration.
+// Must be called with s.mu held
+func (s *RRDStorage) replay(filename string, pending []pendingWrite) {
+	if len(pending) == 0 {
+		return
+	}
+
+	u := rrd.NewUpdater(filename)
+	for _, w := range pending {
+		if err := u.Update(w.timestamp, w.latency, w.loss); err != nil {
+			log.Printf("[Storage] Failed to replay update for %s: %v", filepath.Base(filename), err)
+		}
+	}
+}
@@ -5,11 +5,11 @@ import (
 	"math"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/wellsgz/pulse/internal/paths"
 	"github.com/ziutek/rrd"
 )
 
@@ -24,8 +24,12 @@ type RRDStorage struct {
 	// RRA configurations: steps, rows
 	rras []rraConfig
 
-	updaters map[string]*rrd.Updater
-	mu       sync.RWMutex
+	updaters  map[string]*rrd.Updater
+	migrating map[string][]pendingWrite // RRD file -> writes received while it is rebuilt
+	mu        sync.RWMutex
+
+	migration   MigrationStatus
+	migrationMu sync.RWMutex
 }
 
 // rraConfig defines an RRA (Round Robin Archive) configuration
@@ -61,12 +65,14 @@ func NewRRDStorage(dataDir string, step time.Duration, retentionStr string, xff
 		aggregation: aggUpper,
 		rras:        rras,
 		updaters:    make(map[string]*rrd.Updater),
+		migrating:   make(map[string][]pendingWrite),
+		migration:   MigrationStatus{State: MigrationIdle},
 	}, nil
 }
 
 // Write stores a latency value and loss indicator for a target
-func (s *RRDStorage) Write(targetName string, timestamp time.Time, latencyMs float64, isLoss bool) error {
-	filename := s.getFilename(targetName)
+func (s *RRDStorage) Write(targetID string, timestamp time.Time, latencyMs float64, isLoss bool) error {
+	filename := s.getFilename(targetID)
 
 	// Create RRD file if it doesn't exist
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
@@ -75,15 +81,6 @@ func (s *RRDStorage) Write(targetName string, timestamp time.Time, latencyMs flo
 		}
 	}
 
-	// Get or create updater
-	s.mu.Lock()
-	u, exists := s.updaters[targetName]
-	if !exists {
-		u = rrd.NewUpdater(filename)
-		s.updaters[targetName] = u
-	}
-	s.mu.Unlock()
-
 	// Prepare values: latency and loss
 	var latencyVal, lossVal interface{}
 
@@ -95,13 +92,28 @@ func (s *RRDStorage) Write(targetName string, timestamp time.Time, latencyMs flo
 		lossVal = 0.0 // 0 = success
 	}
 
+	// Get or create updater
+	s.mu.Lock()
+	if pending, migrating := s.migrating[filename]; migrating {
+		// The file is being rebuilt; the update is replayed once it is in place
+		s.migrating[filename] = append(pending, pendingWrite{timestamp, latencyVal, lossVal})
+		s.mu.Unlock()
+		return nil
+	}
+	u, exists := s.updaters[targetID]
+	if !exists {
+		u = rrd.NewUpdater(filename)
+		s.updaters[targetID] = u
+	}
+	s.mu.Unlock()
+
 	// Update RRD with both values
 	return u.Update(timestamp, latencyVal, lossVal)
 }
 
 // Fetch retrieves data points for a target within a time range
-func (s *RRDStorage) Fetch(targetName string, from, to time.Time) ([]DataPoint, error) {
-	filename := s.getFilename(targetName)
+func (s *RRDStorage) Fetch(targetID string, from, to time.Time) ([]DataPoint, error) {
+	filename := s.getFilename(targetID)
 
 	// Check if file exists
 	if _, err := os.Stat(filename); os.IsNotExist(err) {
@@ -179,10 +191,15 @@ func (s *RRDStorage) Close() error {
 
 // createRRD creates a new RRD file with latency and loss data sources
 func (s *RRDStorage) createRRD(filename string) error {
+	return s.createRRDAt(filename, time.Now().Add(-s.step))
+}
+
+// createRRDAt creates a new RRD file that accepts updates after start
+func (s *RRDStorage) createRRDAt(filename string, start time.Time) error {
 	stepSecs := uint(s.step.Seconds())
 	heartbeatSecs := int(s.heartbeat.Seconds())
 
-	c := rrd.NewCreator(filename, time.Now().Add(-s.step), stepSecs)
+	c := rrd.NewCreator(filename, start, stepSecs)
 
 	// Add RRAs (archives) with configured aggregation method
 	for _, rra := range s.rras {
@@ -198,31 +215,247 @@ func (s *RRDStorage) createRRD(filename string) error {
 	return c.Create(false) // Don't overwrite if exists
 }
 
-// unsafeFilenameChars matches characters that are unsafe for filenames on various filesystems
-var unsafeFilenameChars = regexp.MustCompile(`[<>:"/\\|?*\x00-\x1f]`)
-
-// getFilename returns the RRD file path for a target
-func (s *RRDStorage) getFilename(targetName string) string {
-	// Sanitize target name for filesystem
-	// Replace spaces with underscores
-	safe := strings.ReplaceAll(targetName, " ", "_")
-	// Remove or replace all filesystem-unsafe characters (Windows + Unix)
-	safe = unsafeFilenameChars.ReplaceAllString(safe, "_")
-	// Convert to lowercase for consistency
-	safe = strings.ToLower(safe)
-	// Collapse multiple underscores
-	safe = regexp.MustCompile(`_+`).ReplaceAllString(safe, "_")
-	// Trim leading/trailing underscores
-	safe = strings.Trim(safe, "_")
-	// Truncate to reasonable length (200 chars max)
-	if len(safe) > 200 {
-		safe = safe[:200]
-	}
-	// Ensure non-empty filename
-	if safe == "" {
-		safe = "unnamed"
-	}
-	return filepath.Join(s.dataDir, safe+".rrd")
+// Export returns the complete history of a target from all RRAs
+func (s *RRDStorage) Export(targetID string) (*Archive, error) {
+	filename := s.getFilename(targetID)
+	if _, err := os.Stat(filename); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%w for target id %s", ErrNoHistory, targetID)
+	}
+
+	a, err := exportFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	a.Target = targetID
+	return a, nil
+}
+
+// Import writes an archive into a target's RRD file. The file is rebuilt with
+// the configured step and retention; coarse archive rows are resampled so that
+// every period keeps the finest resolution available.
+func (s *RRDStorage) Import(targetID string, archive *Archive, mode ImportMode) error {
+	if err := archive.Validate(); err != nil {
+		return fmt.Errorf("invalid archive: %w", err)
+	}
+
+	// Probe results arriving until the rebuilt file is in place are replayed
+	// into it
+	filename := s.getFilename(targetID)
+	if !s.beginRebuild(filename) {
+		return fmt.Errorf("history for %s is being rebuilt, try again later", targetID)
+	}
+	defer s.endRebuild(filename)
+
+	_, statErr := os.Stat(filename)
+	exists := statErr == nil
+
+	var sources []*Archive
+	switch mode {
+	case ImportCreate:
+		if exists {
+			return ErrTargetExists
+		}
+	case ImportMerge:
+		if exists {
+			existing, err := exportFile(filename)
+			if err != nil {
+				return fmt.Errorf("failed to read existing history: %w", err)
+			}
+			sources = append(sources, existing)
+		}
+	case ImportReplace:
+	default:
+		return fmt.Errorf("unknown import mode: %s", mode)
+	}
+	sources = append(sources, archive)
+
+	return s.writeTimeline(targetID, filename, mergeTimeline(sources...))
+}
+
+// writeTimeline rebuilds an RRD file from a consolidated timeline. The new file
+// is written next to the original and renamed into place once complete.
+func (s *RRDStorage) writeTimeline(targetID, filename string, points []timelinePoint) error {
+	tmp := filename + ".tmp"
+	if err := s.buildTimeline(tmp, points); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Rename(tmp, filename); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to replace RRD file: %w", err)
+	}
+	delete(s.updaters, targetID)
+	return nil
+}
+
+// buildTimeline creates a new RRD file with the current schema and fills it
+// from a consolidated timeline. The file is removed again on failure.
+func (s *RRDStorage) buildTimeline(filename string, points []timelinePoint) error {
+	os.Remove(filename)
+
+	start := time.Now().Add(-s.step)
+	if len(points) > 0 {
+		start = points[0].Timestamp.Add(-time.Second)
+	}
+	if err := s.createRRDAt(filename, start); err != nil {
+		return fmt.Errorf("failed to create RRD file: %w", err)
+	}
+
+	if err := s.fillRRD(filename, start, points); err != nil {
+		os.Remove(filename)
+		return err
+	}
+	return nil
+}
+
+// fillRRD replays a timeline into a freshly created RRD file. Each point is
+// written as a series of updates no further apart than the heartbeat so that
+// librrd treats the whole period as known.
+func (s *RRDStorage) fillRRD(filename string, start time.Time, points []timelinePoint) error {
+	const batchSize = 500
+
+	u := rrd.NewUpdater(filename)
+	spacing := s.heartbeat - s.step
+	if spacing < s.step {
+		spacing = s.step
+	}
+
+	pending := 0
+	cache := func(ts time.Time, latency, loss float64) error {
+		u.Cache(ts, latency, loss)
+		pending++
+		if pending >= batchSize {
+			pending = 0
+			return u.Update()
+		}
+		return nil
+	}
+
+	last := start.Truncate(time.Second)
+	for _, p := range points {
+		ts := p.Timestamp.Truncate(time.Second)
+		end := ts.Add(p.Resolution)
+		if !end.After(last) {
+			continue
+		}
+
+		// Mark gaps as unknown so the next period starts from a clean boundary
+		if ts.After(last) {
+			if err := cache(ts, math.NaN(), math.NaN()); err != nil {
+				return fmt.Errorf("failed to write RRD data: %w", err)
+			}
+			last = ts
+		}
+
+		step := spacing
+		if p.Resolution < step {
+			step = p.Resolution
+		}
+		for t := last.Add(step); ; t = t.Add(step) {
+			if t.After(end) {
+				t = end
+			}
+			if !t.After(last) {
+				break
+			}
+			if err := cache(t, p.Latency, p.Loss); err != nil {
+				return fmt.Errorf("failed to write RRD data: %w", err)
+			}
+			last = t
+		}
+	}
+
+	if err := u.Update(); err != nil {
+		return fmt.Errorf("failed to write RRD data: %w", err)
+	}
+	return nil
+}
+
+// exportFile reads every RRA of an RRD file into an Archive
+func exportFile(filename string) (*Archive, error) {
+	info, err := rrd.Info(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read RRD info: %w", err)
+	}
+
+	step := infoUint(info["step"])
+	lastUpdate := infoUint(info["last_update"])
+	cfs := infoList(info["rra.cf"])
+	rows := infoList(info["rra.rows"])
+	pdpPerRow := infoList(info["rra.pdp_per_row"])
+	if step == 0 || len(cfs) == 0 || len(rows) != len(cfs) || len(pdpPerRow) != len(cfs) {
+		return nil, fmt.Errorf("unexpected RRD layout in %s", filepath.Base(filename))
+	}
+
+	a := &Archive{
+		Version:    ArchiveVersion,
+		StepSecs:   int64(step),
+		ExportedAt: time.Now(),
+	}
+
+	for i := range cfs {
+		cf, _ := cfs[i].(string)
+		rowCount := infoUint(rows[i])
+		resolution := step * infoUint(pdpPerRow[i])
+		if resolution == 0 || rowCount == 0 {
+			continue
+		}
+
+		end := lastUpdate - lastUpdate%resolution
+		begin := end - rowCount*resolution
+
+		fetchRes, err := rrd.Fetch(filename, cf,
+			time.Unix(int64(begin), 0), time.Unix(int64(end), 0),
+			time.Duration(resolution)*time.Second)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch RRA %d: %w", i, err)
+		}
+
+		rra := ArchiveRRA{
+			CF:             cf,
+			ResolutionSecs: int64(fetchRes.Step / time.Second),
+			Rows:           int(rowCount),
+			Points:         make([]ArchivePoint, 0, fetchRes.RowCnt),
+		}
+		if len(fetchRes.DsNames) >= 2 {
+			for row := 0; row < fetchRes.RowCnt; row++ {
+				ts := fetchRes.Start.Add(time.Duration(row) * fetchRes.Step)
+				rra.Points = append(rra.Points, NewArchivePoint(ts, fetchRes.ValueAt(0, row), fetchRes.ValueAt(1, row)))
+			}
+		}
+		fetchRes.FreeValues()
+
+		a.RRAs = append(a.RRAs, rra)
+	}
+
+	return a, nil
+}
+
+// infoUint converts a numeric rrd.Info value to uint
+func infoUint(v interface{}) uint {
+	switch n := v.(type) {
+	case uint:
+		return n
+	case int:
+		return uint(n)
+	case float64:
+		return uint(n)
+	}
+	return 0
+}
+
+// infoList returns an indexed rrd.Info value (e.g. rra[0].cf) as a slice
+func infoList(v interface{}) []interface{} {
+	list, _ := v.([]interface{})
+	return list
+}
+
+// getFilename returns the RRD file path for a storage key (target id)
+func (s *RRDStorage) getFilename(key string) string {
+	return filepath.Join(s.dataDir, paths.SanitizeFilename(key)+".rrd")
 }
 
 // parseRRAs parses a retention string like "10s:1d,1m:7d,1h:90d" into RRA configurations
@@ -1,6 +1,9 @@
 package storage
 
 import (
+	"os"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -176,3 +179,94 @@ func TestGetFilename(t *testing.T) {
 		})
 	}
 }
+
+func TestSchemaMatches(t *testing.T) {
+	rras := []rraConfig{{steps: 1, rows: 8640}, {steps: 6, rows: 10080}}
+	info := func(step uint, cf string, rows, pdp []interface{}) map[string]interface{} {
+		cfs := make([]interface{}, len(rows))
+		for i := range cfs {
+			cfs[i] = cf
+		}
+		return map[string]interface{}{
+			"step":            step,
+			"rra.cf":          cfs,
+			"rra.rows":        rows,
+			"rra.pdp_per_row": pdp,
+		}
+	}
+
+	tests := []struct {
+		name string
+		info map[string]interface{}
+		want bool
+	}{
+		{"match", info(10, "AVERAGE", []interface{}{uint(8640), uint(10080)}, []interface{}{uint(1), uint(6)}), true},
+		{"step changed", info(20, "AVERAGE", []interface{}{uint(8640), uint(10080)}, []interface{}{uint(1), uint(6)}), false},
+		{"aggregation changed", info(10, "MAX", []interface{}{uint(8640), uint(10080)}, []interface{}{uint(1), uint(6)}), false},
+		{"rows changed", info(10, "AVERAGE", []interface{}{uint(8640), uint(20160)}, []interface{}{uint(1), uint(6)}), false},
+		{"archive removed", info(10, "AVERAGE", []interface{}{uint(8640)}, []interface{}{uint(1)}), false},
+		{"empty info", map[string]interface{}{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := schemaMatches(tt.info, 10, "AVERAGE", rras); got != tt.want {
+				t.Errorf("schemaMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWritesDuringRebuild(t *testing.T) {
+	dir := t.TempDir()
+	s, err := NewRRDStorage(dir, 10*time.Second, "10s:1d", 0.5, "average")
+	if err != nil {
+		t.Fatalf("NewRRDStorage() error = %v", err)
+	}
+	filename := s.getFilename("gw")
+	if err := os.WriteFile(filename, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if !s.beginRebuild(filename) {
+		t.Fatal("beginRebuild() = false, want true")
+	}
+
+	// Probe results keep arriving while the file is rebuilt
+	base := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.Write("gw", base.Add(time.Duration(i)*10*time.Second), 8, false); err != nil {
+				t.Errorf("Write() during rebuild error = %v", err)
+			}
+		}(i)
+	}
+
+	// A concurrent import must not rebuild the same file
+	archive := &Archive{Version: ArchiveVersion, RRAs: []ArchiveRRA{rra(base, 300, [2]float64{1, 0})}}
+	if err := s.Import("gw", archive, ImportReplace); err == nil || !strings.Contains(err.Error(), "being rebuilt") {
+		t.Errorf("Import() during rebuild error = %v, want being rebuilt", err)
+	}
+	wg.Wait()
+
+	s.mu.RLock()
+	pending := len(s.migrating[filename])
+	s.mu.RUnlock()
+	if pending != 20 {
+		t.Errorf("%d writes buffered, want 20", pending)
+	}
+
+	s.endRebuild(filename)
+	s.mu.RLock()
+	_, rebuilding := s.migrating[filename]
+	s.mu.RUnlock()
+	if rebuilding {
+		t.Error("writes still buffered after endRebuild()")
+	}
+	if !s.beginRebuild(filename) {
+		t.Error("beginRebuild() after endRebuild() = false, want true")
+	}
+}
@@ -7,8 +7,9 @@ import (
 // DataPoint represents a single data point in time series
 type DataPoint struct {
 	Timestamp time.Time `json:"timestamp"`
-	Value     float64   `json:"value"` // Latency in ms, NaN for packet loss or no data
-	Loss      float64   `json:"loss"`  // 0=success, 1=failure, NaN=no data (for aggregated: 0.0-1.0 loss ratio)
+	Value     float64   `json:"value"`         // Latency in ms, NaN for packet loss or no data
+	Loss      float64   `json:"loss"`          // 0=success, 1=failure, NaN=no data (for aggregated: 0.0-1.0 loss ratio)
+	Seq       uint64    `json:"seq,omitempty"` // Result sequence number, for buffered samples only
 }
 
 // Stats represents statistics for a target
@@ -26,16 +27,29 @@ type Stats struct {
 	LastUpdate  time.Time `json:"last_update"`
 }
 
-// Storage defines the interface for persistent time-series storage
+// Storage defines the interface for persistent time-series storage.
+// Targets are identified by their stable id (config.Target.StorageKey), not their display name.
 type Storage interface {
 	// Write stores a latency value and loss indicator for a target at the given timestamp
 	// latencyMs: latency in milliseconds (NaN for packet loss)
 	// isLoss: true if the probe failed (packet loss)
-	Write(targetName string, timestamp time.Time, latencyMs float64, isLoss bool) error
+	Write(targetID string, timestamp time.Time, latencyMs float64, isLoss bool) error
 
 	// Fetch retrieves data points for a target within a time range
 	// Returns DataPoints with both Latency and Loss fields populated
-	Fetch(targetName string, from, to time.Time) ([]DataPoint, error)
+	Fetch(targetID string, from, to time.Time) ([]DataPoint, error)
+
+	// Export returns the complete multi-resolution history of a target
+	Export(targetID string) (*Archive, error)
+
+	// Import writes an archive into a target's history
+	Import(targetID string, archive *Archive, mode ImportMode) error
+
+	// Usage lists the stored data files and their sizes
+	Usage() (*Usage, error)
+
+	// Remove deletes a target's history, or archives it when archive is true
+	Remove(targetID string, archive bool) (string, error)
 
 	// Close releases storage resources
 	Close() error
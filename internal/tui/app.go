@@ -5,16 +5,19 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/config"
 	"github.com/wellsgz/pulse/internal/ipc"
 )
 
 // Init initializes the model and returns initial commands
 func (m Model) Init() tea.Cmd {
 	if m.IsIPCMode() {
-		return tea.Batch(
-			// Wait for first IPC probe result
-			waitForIPCResult(m.ipcResults),
-		)
+		// Wait for the first IPC probe result from each daemon
+		cmds := make([]tea.Cmd, len(m.sources))
+		for i, src := range m.sources {
+			cmds[i] = waitForIPCResult(src.Name, src.Client.Results())
+		}
+		return tea.Batch(cmds...)
 	}
 	return tea.Batch(
 		// Wait for first probe result
@@ -29,20 +32,7 @@ func (m Model) Init() tea.Cmd {
 
 // Run starts the TUI application in standalone mode
 func Run(coll *collector.Collector, apiAddr string) error {
-	model := NewModel(coll, apiAddr)
-
-	p := tea.NewProgram(
-		model,
-		tea.WithAltScreen(),       // Use alternate screen buffer
-		tea.WithMouseCellMotion(), // Enable mouse support
-	)
-
-	_, err := p.Run()
-	if err != nil {
-		return fmt.Errorf("error running TUI: %w", err)
-	}
-
-	return nil
+	return runProgram(NewModel(coll, apiAddr))
 }
 
 // RunWithIPC starts the TUI application connected to a daemon via IPC
@@ -60,13 +50,45 @@ func RunWithIPC(client *ipc.Client, apiAddr string) error {
 
 	model := NewModelWithIPC(client, targets, apiAddr)
 
+	return runProgram(model)
+}
+
+// RunWithSources starts the TUI application connected to several daemons.
+// Targets are listed with a source column and can be compared across sources.
+func RunWithSources(sources []Source, apiAddr string) error {
+	if len(sources) == 1 {
+		sources[0].Name = "" // No source column for a single daemon
+	}
+
+	targets := make(map[string][]config.Target, len(sources))
+	for _, src := range sources {
+		label := src.Name
+		if label == "" {
+			label = "daemon"
+		}
+
+		srcTargets, err := src.Client.GetTargets()
+		if err != nil {
+			return fmt.Errorf("failed to get targets from %s: %w", label, err)
+		}
+		if err := src.Client.Subscribe(); err != nil {
+			return fmt.Errorf("failed to subscribe to probe results from %s: %w", label, err)
+		}
+		targets[src.Name] = srcTargets
+	}
+
+	return runProgram(NewModelWithSources(sources, targets, apiAddr))
+}
+
+// runProgram runs the Bubble Tea program for a model
+func runProgram(model Model) error {
 	p := tea.NewProgram(
 		model,
 		tea.WithAltScreen(),       // Use alternate screen buffer
 		tea.WithMouseCellMotion(), // Enable mouse support
 	)
 
-	_, err = p.Run()
+	_, err := p.Run()
 	if err != nil {
 		return fmt.Errorf("error running TUI: %w", err)
 	}
@@ -0,0 +1,214 @@
+package tui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/tui/components"
+)
+
+// slowRatio is how much slower than the median of the other sources a source
+// must be before it is reported as the odd one out
+const slowRatio = 1.5
+
+// sameEndpoint reports whether two targets measure the same endpoint.
+// Composite targets match when they combine the same members the same way.
+func sameEndpoint(a, b config.Target) bool {
+	if a.IsComposite() || b.IsComposite() {
+		return a.IsComposite() && b.IsComposite() && a.Endpoint() == b.Endpoint()
+	}
+	return strings.EqualFold(a.Host, b.Host) && a.Port == b.Port && strings.EqualFold(a.Probe, b.Probe)
+}
+
+// compareTargets returns the indices of all targets measuring the same
+// endpoint as the compared one, in source order
+func (m Model) compareTargets() []int {
+	var idx []int
+	for i := range m.targets {
+		if sameEndpoint(m.targets[i].Config, m.compareTarget) {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// openCompare switches to the compare view for the selected target and
+// fetches the last hour of history from every source
+func (m Model) openCompare() (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+
+	m.compareTarget = target.Config
+	m.currentView = CompareView
+
+	var cmds []tea.Cmd
+	for _, i := range m.compareTargets() {
+		m.targets[i].LoadingHistory = true
+		cmds = append(cmds, m.fetchHistoricalDataCmd(m.targets[i].Source, m.targets[i].Config.Name, TimeRange1Hour))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleCompareViewKeys handles keys in compare view
+func (m Model) handleCompareViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "backspace":
+		m.currentView = ListView
+
+	case "r":
+		m.refreshAllStats()
+		return m.openCompare()
+	}
+
+	return m, nil
+}
+
+// compareVerdict summarizes whether an endpoint is slow from one source or
+// from all of them. avgs maps source names to average latency.
+func compareVerdict(avgs map[string]float64) string {
+	if len(avgs) < 2 {
+		return "Connect to more daemons to compare vantage points"
+	}
+
+	names := make([]string, 0, len(avgs))
+	for name := range avgs {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return avgs[names[i]] > avgs[names[j]] })
+
+	slowest := names[0]
+	others := make([]float64, 0, len(names)-1)
+	for _, name := range names[1:] {
+		others = append(others, avgs[name])
+	}
+	sort.Float64s(others)
+	median := percentile(others, 50)
+
+	if median > 0 && avgs[slowest] >= median*slowRatio {
+		return fmt.Sprintf("Slow only from %s (%.1f× the other sources)", slowest, avgs[slowest]/median)
+	}
+	return fmt.Sprintf("Similar from all %d sources", len(avgs))
+}
+
+// renderCompareView renders one endpoint as measured from every source
+func (m Model) renderCompareView() string {
+	var b strings.Builder
+
+	headerText := fmt.Sprintf(" Compare: %s - %s ", m.compareTarget.Endpoint(), strings.ToUpper(m.compareTarget.Probe))
+	header := TitleStyle.Render(headerText)
+	backHint := lipgloss.NewStyle().Foreground(ColorMuted).Render("[Esc] back")
+
+	spacing := m.width - lipgloss.Width(header) - lipgloss.Width(backHint) - 2
+	if spacing < 1 {
+		spacing = 1
+	}
+
+	b.WriteString(lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		header,
+		strings.Repeat(" ", spacing),
+		backHint,
+	))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(m.renderError())
+		b.WriteString("\n\n")
+	}
+
+	columns := components.CompareColumns(m.width)
+	table := components.NewTable(columns)
+
+	rows := []string{table.RenderHeader(), table.RenderSeparator()}
+	avgs := make(map[string]float64)
+	for _, i := range m.compareTargets() {
+		target := m.targets[i]
+
+		var lastMs, avgMs, lossPct float64
+		if target.Stats != nil {
+			lastMs = target.Stats.LastMs
+			avgMs = target.Stats.AvgMs
+			lossPct = target.Stats.LossPct
+		}
+
+		hourAvg, hourLoss := "-", "-"
+		if target.LoadingHistory {
+			hourAvg = "…"
+		} else if target.HistoricalStats != nil && target.HistoricalStats.Hour != nil {
+			hour := target.HistoricalStats.Hour
+			hourAvg = FormatLatency(hour.AvgMs)
+			hourLoss = FormatLoss(hour.LossPct)
+			avgs[sourceLabel(target.Source)] = hour.AvgMs
+		}
+
+		row := []string{
+			truncate(sourceLabel(target.Source), columns[0].Width),
+			truncate(target.Config.Name, columns[1].Width),
+			FormatLatency(lastMs),
+			FormatLatency(avgMs),
+			FormatLoss(lossPct),
+			hourAvg,
+			hourLoss,
+			components.Sparkline(target.History, columns[7].Width),
+		}
+		rows = append(rows, table.RenderRow(row, false))
+	}
+	b.WriteString(strings.Join(rows, "\n"))
+	b.WriteString("\n\n")
+
+	verdictStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary)
+	b.WriteString("  ")
+	b.WriteString(verdictStyle.Render(compareVerdict(avgs)))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderCompareHelp())
+
+	return b.String()
+}
+
+// renderCompareHelp renders the help footer for compare view
+func (m Model) renderCompareHelp() string {
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"Esc", "back"},
+		{"r", "refresh"},
+		{"q", "quit"},
+	}
+
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts,
+			HelpKeyStyle.Render(k.key)+
+				HelpStyle.Render(" "+k.desc))
+	}
+
+	return HelpStyle.Render(strings.Join(parts, "  "))
+}
+
+// sourceLabel returns the display name of a source
+func sourceLabel(source string) string {
+	if source == "" {
+		return "local"
+	}
+	return source
+}
+
+// truncate shortens s to width runes, marking the cut with an ellipsis
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 0 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
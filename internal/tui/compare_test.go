@@ -0,0 +1,35 @@
+package tui
+
+import "testing"
+
+func TestCompareVerdict(t *testing.T) {
+	tests := []struct {
+		name string
+		avgs map[string]float64
+		want string
+	}{
+		{
+			name: "single source",
+			avgs: map[string]float64{"sg": 180},
+			want: "Connect to more daemons to compare vantage points",
+		},
+		{
+			name: "slow from one source",
+			avgs: map[string]float64{"sg": 240, "fra": 20, "iad": 30, "nrt": 40},
+			want: "Slow only from sg (8.0× the other sources)",
+		},
+		{
+			name: "slow from everywhere",
+			avgs: map[string]float64{"sg": 210, "fra": 190, "iad": 200},
+			want: "Similar from all 3 sources",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := compareVerdict(tt.avgs); got != tt.want {
+				t.Errorf("compareVerdict() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -14,6 +14,37 @@ type GraphPoint struct {
 	Value     float64 // Latency in ms, -1 for packet loss
 }
 
+// GraphMarker flags a period, or an instant when End equals Start, below the
+// graph, e.g. an outage or a routing change
+type GraphMarker struct {
+	Start  time.Time
+	End    time.Time
+	Symbol rune
+	Color  lipgloss.Color
+}
+
+// GraphSeries is one line of an overlay graph
+type GraphSeries struct {
+	Points []GraphPoint
+	Color  lipgloss.Color // Line and loss marker color (default cyan line, red loss)
+}
+
+// lineStyle returns the style of the series' line
+func (s GraphSeries) lineStyle() lipgloss.Style {
+	if s.Color == "" {
+		return graphLineStyle
+	}
+	return lipgloss.NewStyle().Foreground(s.Color)
+}
+
+// lossStyle returns the style of the series' packet loss markers
+func (s GraphSeries) lossStyle() lipgloss.Style {
+	if s.Color == "" {
+		return graphLossStyle
+	}
+	return lipgloss.NewStyle().Foreground(s.Color)
+}
+
 // GraphConfig configures graph rendering
 type GraphConfig struct {
 	Width      int     // Total width including Y-axis labels
@@ -23,6 +54,9 @@ type GraphConfig struct {
 	MinY       float64 // Minimum Y value (auto if both 0)
 	MaxY       float64 // Maximum Y value (auto if both 0)
 	YAxisWidth int     // Width of Y-axis label area
+
+	Markers []GraphMarker // Drawn in a row below the graph (later ones on top)
+	Cursor  time.Time     // Crosshair drawn as a vertical line (zero for none)
 }
 
 // DefaultGraphConfig returns sensible defaults
@@ -40,9 +74,10 @@ func DefaultGraphConfig() GraphConfig {
 
 // Graph colors
 var (
-	graphAxisStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
-	graphLineStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#06B6D4"))
-	graphLossStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	graphAxisStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#6B7280"))
+	graphLineStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#06B6D4"))
+	graphLossStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#EF4444"))
+	graphCursorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#F9FAFB")).Bold(true)
 )
 
 // Graph renders an ASCII line graph from data points
@@ -63,7 +98,17 @@ func Graph(points []GraphPoint, config GraphConfig) string {
 
 // GraphWithRange renders with explicit time range
 func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig) string {
-	if len(points) == 0 {
+	return OverlayGraph([]GraphSeries{{Points: points}}, from, to, config)
+}
+
+// OverlayGraph renders several series on one time axis and Y scale. Where
+// lines cross, later series are drawn on top.
+func OverlayGraph(series []GraphSeries, from, to time.Time, config GraphConfig) string {
+	var all []GraphPoint
+	for _, s := range series {
+		all = append(all, s.Points...)
+	}
+	if len(all) == 0 {
 		return renderEmptyGraph(config)
 	}
 
@@ -77,64 +122,44 @@ func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig)
 	}
 
 	// Calculate Y-axis range
-	minY, maxY, ticks := calculateYRange(points, config.MinY, config.MaxY, config.Height)
+	minY, maxY, ticks := calculateYRange(all, config.MinY, config.MaxY, config.Height)
 
-	// Create canvas (2x vertical resolution using half-blocks)
-	canvasHeight := config.Height * 2 // Each row has upper and lower half
-	canvas := make([][]rune, config.Height)
-	for i := range canvas {
-		canvas[i] = make([]rune, graphWidth)
-		for j := range canvas[i] {
-			canvas[i][j] = ' '
-		}
+	// Canvas (2x vertical resolution using half-blocks), with the series
+	// owning each cell
+	canvas := newCanvas(config.Height, graphWidth)
+	owners := make([][]int, config.Height)
+	for i := range owners {
+		owners[i] = make([]int, graphWidth)
 	}
 
-	// Track packet loss positions
-	lossPositions := make([]bool, graphWidth)
+	// Series with packet loss at each position, -1 for none
+	lossPositions := make([]int, graphWidth)
+	for i := range lossPositions {
+		lossPositions[i] = -1
+	}
 
-	// Plot points
 	timeRange := to.Sub(from)
 	if timeRange == 0 {
 		timeRange = time.Second // Avoid division by zero
 	}
 
-	prevX, prevY := -1, -1
-	for _, point := range points {
-		// Calculate X position
-		xRatio := float64(point.Timestamp.Sub(from)) / float64(timeRange)
-		x := int(xRatio * float64(graphWidth-1))
-		if x < 0 {
-			x = 0
-		}
-		if x >= graphWidth {
-			x = graphWidth - 1
-		}
-
-		if point.Value < 0 || math.IsNaN(point.Value) {
-			// Packet loss
-			lossPositions[x] = true
-			prevX, prevY = -1, -1
-			continue
-		}
-
-		// Calculate Y position (0 = top, canvasHeight-1 = bottom)
-		yRatio := (point.Value - minY) / (maxY - minY)
-		if yRatio < 0 {
-			yRatio = 0
-		}
-		if yRatio > 1 {
-			yRatio = 1
-		}
-		y := canvasHeight - 1 - int(yRatio*float64(canvasHeight-1))
-
-		// Draw point and connect to previous
-		if prevX >= 0 && prevY >= 0 {
-			drawLine(canvas, prevX, prevY, x, y, canvasHeight)
-		} else {
-			drawPoint(canvas, x, y, canvasHeight)
+	for i, s := range series {
+		layer := newCanvas(config.Height, graphWidth)
+		plotSeries(layer, s.Points, from, timeRange, minY, maxY, lossPositions, i)
+		for row := range layer {
+			for col, ch := range layer[row] {
+				if ch != ' ' {
+					canvas[row][col] = mergeBlocks(canvas[row][col], ch)
+					owners[row][col] = i
+				}
+			}
 		}
+	}
 
-		prevX, prevY = x, y
+	// Crosshair column, if it lies within the graph
+	cursorX := -1
+	if !config.Cursor.IsZero() && !config.Cursor.Before(from) && !config.Cursor.After(to) {
+		cursorX = int(float64(config.Cursor.Sub(from)) / float64(timeRange) * float64(graphWidth-1))
 	}
 
 	// Render canvas to string
@@ -150,10 +175,15 @@ func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig)
 		// Graph row with colors
 		for col := 0; col < graphWidth; col++ {
 			ch := canvas[row][col]
-			if ch == ' ' {
+			switch {
+			case col == cursorX && ch == ' ':
+				result.WriteString(graphCursorStyle.Render("┊"))
+			case col == cursorX:
+				result.WriteString(graphCursorStyle.Render(string(ch)))
+			case ch == ' ':
 				result.WriteRune(' ')
-			} else {
-				result.WriteString(graphLineStyle.Render(string(ch)))
+			default:
+				result.WriteString(series[owners[row][col]].lineStyle().Render(string(ch)))
 			}
 		}
 		result.WriteString("\n")
@@ -165,8 +195,8 @@ func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig)
 			result.WriteString(strings.Repeat(" ", config.YAxisWidth))
 		}
 		for col := 0; col < graphWidth; col++ {
-			if lossPositions[col] {
-				result.WriteString(graphLossStyle.Render("×"))
+			if i := lossPositions[col]; i >= 0 {
+				result.WriteString(series[i].lossStyle().Render("×"))
 			} else {
 				result.WriteRune(' ')
 			}
@@ -174,6 +204,15 @@ func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig)
 		result.WriteString("\n")
 	}
 
+	// Draw event markers below the loss row
+	if row := renderMarkers(config.Markers, from, to, graphWidth); row != "" {
+		if config.ShowYAxis {
+			result.WriteString(strings.Repeat(" ", config.YAxisWidth))
+		}
+		result.WriteString(row)
+		result.WriteString("\n")
+	}
+
 	// X-axis with time labels
 	if config.ShowXAxis {
 		result.WriteString(renderXAxis(from, to, graphWidth, config.YAxisWidth, config.ShowYAxis))
@@ -182,6 +221,79 @@ func GraphWithRange(points []GraphPoint, from, to time.Time, config GraphConfig)
 	return result.String()
 }
 
+// newCanvas returns an empty canvas of height rows and width columns
+func newCanvas(height, width int) [][]rune {
+	canvas := make([][]rune, height)
+	for i := range canvas {
+		canvas[i] = make([]rune, width)
+		for j := range canvas[i] {
+			canvas[i][j] = ' '
+		}
+	}
+	return canvas
+}
+
+// plotSeries draws the points of one series on the canvas and records its
+// packet loss positions as series
+func plotSeries(canvas [][]rune, points []GraphPoint, from time.Time, timeRange time.Duration, minY, maxY float64, lossPositions []int, series int) {
+	graphWidth := len(lossPositions)
+	canvasHeight := len(canvas) * 2 // Each row has upper and lower half
+
+	prevX, prevY := -1, -1
+	for _, point := range points {
+		// Calculate X position
+		xRatio := float64(point.Timestamp.Sub(from)) / float64(timeRange)
+		x := int(xRatio * float64(graphWidth-1))
+		if x < 0 {
+			x = 0
+		}
+		if x >= graphWidth {
+			x = graphWidth - 1
+		}
+
+		if point.Value < 0 || math.IsNaN(point.Value) {
+			// Packet loss
+			lossPositions[x] = series
+			prevX, prevY = -1, -1
+			continue
+		}
+
+		// Calculate Y position (0 = top, canvasHeight-1 = bottom)
+		yRatio := (point.Value - minY) / (maxY - minY)
+		if yRatio < 0 {
+			yRatio = 0
+		}
+		if yRatio > 1 {
+			yRatio = 1
+		}
+		y := canvasHeight - 1 - int(yRatio*float64(canvasHeight-1))
+
+		// Draw point and connect to previous
+		if prevX >= 0 && prevY >= 0 {
+			drawLine(canvas, prevX, prevY, x, y, canvasHeight)
+		} else {
+			drawPoint(canvas, x, y, canvasHeight)
+		}
+
+		prevX, prevY = x, y
+	}
+}
+
+// mergeBlocks combines the half-blocks of two series drawn in one cell
+func mergeBlocks(a, b rune) rune {
+	upper := a == '▀' || a == '█' || b == '▀' || b == '█'
+	lower := a == '▄' || a == '█' || b == '▄' || b == '█'
+	switch {
+	case upper && lower:
+		return '█'
+	case upper:
+		return '▀'
+	case lower:
+		return '▄'
+	}
+	return ' '
+}
+
 // calculateYRange determines min/max Y with nice tick marks
 func calculateYRange(points []GraphPoint, forcedMin, forcedMax float64, numTicks int) (min, max float64, ticks []float64) {
 	// Find data range (excluding packet loss)
@@ -489,10 +601,53 @@ func renderEmptyGraph(config GraphConfig) string {
 	return result.String()
 }
 
+// renderMarkers renders the markers overlapping from..to as one row of
+// graphWidth columns, or "" if none are in range
+func renderMarkers(markers []GraphMarker, from, to time.Time, graphWidth int) string {
+	timeRange := to.Sub(from)
+	if timeRange <= 0 {
+		return ""
+	}
+	column := func(t time.Time) int {
+		x := int(float64(t.Sub(from)) / float64(timeRange) * float64(graphWidth-1))
+		return max(0, min(x, graphWidth-1))
+	}
+
+	cells := make([]*GraphMarker, graphWidth)
+	found := false
+	for i := range markers {
+		m := &markers[i]
+		end := m.End
+		if end.IsZero() || end.Before(m.Start) {
+			end = m.Start
+		}
+		if m.Start.After(to) || end.Before(from) {
+			continue
+		}
+		for x := column(m.Start); x <= column(end); x++ {
+			cells[x] = m
+		}
+		found = true
+	}
+	if !found {
+		return ""
+	}
+
+	var row strings.Builder
+	for _, m := range cells {
+		if m == nil {
+			row.WriteRune(' ')
+			continue
+		}
+		row.WriteString(lipgloss.NewStyle().Foreground(m.Color).Render(string(m.Symbol)))
+	}
+	return row.String()
+}
+
 // hasLoss checks if there are any packet loss positions
-func hasLoss(positions []bool) bool {
+func hasLoss(positions []int) bool {
 	for _, p := range positions {
-		if p {
+		if p >= 0 {
 			return true
 		}
 	}
@@ -0,0 +1,61 @@
+package components
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ansiCodes matches the color codes of styled output
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func TestOverlayGraph(t *testing.T) {
+	from := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return from.Add(time.Duration(m) * time.Minute) }
+	line := func(first, last int, value float64) []GraphPoint {
+		var points []GraphPoint
+		for m := first; m <= last; m++ {
+			points = append(points, GraphPoint{Timestamp: at(m), Value: value})
+		}
+		return points
+	}
+
+	// A spans the whole axis; B starts halfway and loses a packet at 12:07;
+	// C covers the first two minutes, in the upper half of A's cells
+	low := line(0, 10, 10)
+	high := line(5, 10, 90)
+	high[2].Value = -1
+	short := line(0, 2, 20)
+
+	series := []GraphSeries{
+		{Points: low, Color: "#06B6D4"},
+		{Points: high, Color: "#F59E0B"},
+		{Points: short, Color: "#EC4899"},
+	}
+	// Two columns per minute
+	config := GraphConfig{Width: 21, Height: 4, ShowXAxis: true, MinY: 0, MaxY: 100}
+	got := strings.Split(ansiCodes.ReplaceAllString(OverlayGraph(series, from, at(10), config), ""), "\n")
+
+	want := []string{
+		"          ▄▄▄   ▄▄▄▄▄", // B, broken around the loss
+		"                     ",
+		"                     ",
+		"█████▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄▄", // C merged into A
+		"              ×      ", // B's loss
+		"─────────────────────",
+		"12:00:00     12:10:00",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("OverlayGraph() = %d lines, want %d:\n%s", len(got), len(want), strings.Join(got, "\n"))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %q, want %q", i, got[i], want[i])
+		}
+	}
+
+	if got := OverlayGraph([]GraphSeries{{}, {}}, from, at(10), config); got != renderEmptyGraph(config) {
+		t.Errorf("OverlayGraph() of empty series = %q, want the empty graph", got)
+	}
+}
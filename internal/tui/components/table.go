@@ -151,3 +151,67 @@ func AdaptiveColumns(width int) []Column {
 		{Title: "Sparkline", Width: sparklineWidth, Align: lipgloss.Left},
 	}
 }
+
+// sourceColumnWidth is the width of the daemon column in multi-daemon mode
+const sourceColumnWidth = 10
+
+// SourceColumns returns the target list columns with a daemon column after
+// the target name
+func SourceColumns(width int) []Column {
+	base := AdaptiveColumns(width - sourceColumnWidth - 2) // 2 for padding
+	columns := make([]Column, 0, len(base)+1)
+	columns = append(columns, base[0])
+	columns = append(columns, Column{Title: "Source", Width: sourceColumnWidth, Align: lipgloss.Left})
+	return append(columns, base[1:]...)
+}
+
+// EventColumns returns the columns of the events pane, with a daemon column
+// when there are several
+func EventColumns(width int, withSource bool) []Column {
+	fixed := []Column{
+		{Title: "Started", Width: 15, Align: lipgloss.Left},
+		{Title: "Target", Width: 16, Align: lipgloss.Left},
+	}
+	if withSource {
+		fixed = append(fixed, Column{Title: "Source", Width: sourceColumnWidth, Align: lipgloss.Left})
+	}
+	fixed = append(fixed,
+		Column{Title: "Event", Width: 14, Align: lipgloss.Left},
+		Column{Title: "Duration", Width: 10, Align: lipgloss.Right},
+	)
+
+	used := 0
+	for _, col := range fixed {
+		used += col.Width + 2 // +2 for padding
+	}
+	messageWidth := width - used - 2
+	if messageWidth < 10 {
+		messageWidth = 10
+	}
+
+	return append(fixed, Column{Title: "Message", Width: messageWidth, Align: lipgloss.Left})
+}
+
+// CompareColumns returns the columns for comparing one endpoint across daemons
+func CompareColumns(width int) []Column {
+	fixed := []Column{
+		{Title: "Source", Width: sourceColumnWidth, Align: lipgloss.Left},
+		{Title: "Target", Width: 16, Align: lipgloss.Left},
+		{Title: "Last", Width: 8, Align: lipgloss.Right},
+		{Title: "Avg", Width: 8, Align: lipgloss.Right},
+		{Title: "Loss", Width: 6, Align: lipgloss.Right},
+		{Title: "1h Avg", Width: 8, Align: lipgloss.Right},
+		{Title: "1h Loss", Width: 7, Align: lipgloss.Right},
+	}
+
+	used := 0
+	for _, col := range fixed {
+		used += col.Width + 2 // +2 for padding
+	}
+	sparklineWidth := width - used - 2
+	if sparklineWidth < 10 {
+		sparklineWidth = 10
+	}
+
+	return append(fixed, Column{Title: "Sparkline", Width: sparklineWidth, Align: lipgloss.Left})
+}
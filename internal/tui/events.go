@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/ipc"
+	"github.com/wellsgz/pulse/internal/storage"
+	"github.com/wellsgz/pulse/internal/tui/components"
+)
+
+// eventsLimit is how many incidents are fetched from each source
+const eventsLimit = 200
+
+// SourcedIncident is an incident and the daemon that recorded it
+type SourcedIncident struct {
+	Source string
+	storage.Incident
+}
+
+// EventsMsg carries incidents fetched from a source. With a Target they are
+// that target's, for its graph; otherwise they fill the events pane.
+type EventsMsg struct {
+	Source string
+	Target string
+	Events []storage.Incident
+	Err    error
+}
+
+// openEvents switches to the events pane and fetches the incident log of
+// every source
+func (m Model) openEvents() (tea.Model, tea.Cmd) {
+	if m.currentView != EventsView {
+		m.eventsBack = m.currentView
+	}
+	m.currentView = EventsView
+	m.events = nil
+	m.eventsIdx = 0
+
+	if !m.IsIPCMode() {
+		return m, m.fetchEventsCmd("", "")
+	}
+	var cmds []tea.Cmd
+	for _, src := range m.sources {
+		cmds = append(cmds, m.fetchEventsCmd(src.Name, ""))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleEventsViewKeys handles keys in the events pane
+func (m Model) handleEventsViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "backspace", "e":
+		m.currentView = m.eventsBack
+
+	case "up", "k":
+		if m.eventsIdx > 0 {
+			m.eventsIdx--
+		}
+
+	case "down", "j":
+		if m.eventsIdx < len(m.events)-1 {
+			m.eventsIdx++
+		}
+
+	case "home":
+		m.eventsIdx = 0
+
+	case "end":
+		m.eventsIdx = max(len(m.events)-1, 0)
+
+	case "r":
+		return m.openEvents()
+	}
+
+	return m, nil
+}
+
+// handleEvents stores fetched incidents in the events pane or on their target
+func (m Model) handleEvents(msg EventsMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.err = msg.Err
+		return m, nil
+	}
+
+	if msg.Target != "" {
+		if i := m.findTarget(msg.Source, msg.Target); i >= 0 {
+			m.targets[i].Incidents = msg.Events
+		}
+		return m, nil
+	}
+
+	// Replace this source's incidents and keep the pane newest first
+	events := m.events[:0:0]
+	for _, e := range m.events {
+		if e.Source != msg.Source {
+			events = append(events, e)
+		}
+	}
+	for _, inc := range msg.Events {
+		events = append(events, SourcedIncident{Source: msg.Source, Incident: inc})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Start.After(events[j].Start) })
+	m.events = events
+	m.eventsIdx = min(m.eventsIdx, max(len(m.events)-1, 0))
+	return m, nil
+}
+
+// fetchEventsCmd returns the command fetching a source's incidents, of one
+// target or of all. It is nil for daemons too old to keep an incident log.
+func (m Model) fetchEventsCmd(source, target string) tea.Cmd {
+	q := storage.IncidentQuery{Target: target, Limit: eventsLimit}
+	if target != "" {
+		// Only what the longest graph range can show
+		q.From = time.Now().Add(-TimeRange1Week.Duration())
+	}
+	return m.fetchIncidentsCmd(source, q)
+}
+
+// fetchIncidentsCmd returns the command fetching a source's incidents
+// matching q, or nil for daemons too old to keep an incident log
+func (m Model) fetchIncidentsCmd(source string, q storage.IncidentQuery) tea.Cmd {
+	if !m.IsIPCMode() {
+		return fetchEvents(m.collector, q.Target, q)
+	}
+	client := m.client(source)
+	if client == nil || !client.Supports(ipc.MsgTypeGetEvents) {
+		return nil
+	}
+	return fetchEventsIPC(client, source, q.Target, q)
+}
+
+// fetchEvents creates a command to fetch incidents from the collector
+func fetchEvents(coll *collector.Collector, target string, q storage.IncidentQuery) tea.Cmd {
+	return func() tea.Msg {
+		return EventsMsg{Target: target, Events: coll.Incidents(q)}
+	}
+}
+
+// fetchEventsIPC creates a command to fetch incidents via IPC
+func fetchEventsIPC(client *ipc.Client, source, target string, q storage.IncidentQuery) tea.Cmd {
+	return func() tea.Msg {
+		events, err := client.GetEvents(ipc.GetEventsRequest{
+			Target: q.Target,
+			From:   q.From,
+			Limit:  q.Limit,
+		})
+		return EventsMsg{Source: source, Target: target, Events: events, Err: err}
+	}
+}
+
+// incidentStyle returns the style of an incident kind
+func incidentStyle(kind storage.IncidentKind) lipgloss.Style {
+	switch kind {
+	case storage.IncidentDown:
+		return LossStyle
+	case storage.IncidentDegraded:
+		return LatencyWarnStyle
+	case storage.IncidentPathChanged:
+		return lipgloss.NewStyle().Foreground(ColorSecondary)
+	case storage.IncidentMaintenance:
+		return MaintenanceStyle
+	case storage.IncidentUnreachable:
+		return lipgloss.NewStyle().Foreground(ColorMuted).Bold(true)
+	default:
+		return lipgloss.NewStyle().Foreground(ColorMuted)
+	}
+}
+
+// incidentMarkers converts incidents to graph markers. Ongoing incidents
+// extend to now.
+func incidentMarkers(incidents []storage.Incident, now time.Time) []components.GraphMarker {
+	markers := make([]components.GraphMarker, 0, len(incidents))
+	// Oldest first, so newer incidents are drawn on top
+	for i := len(incidents) - 1; i >= 0; i-- {
+		inc := incidents[i]
+		marker := components.GraphMarker{Start: inc.Start, End: inc.End}
+		if inc.Ongoing {
+			marker.End = now
+		}
+		switch inc.Kind {
+		case storage.IncidentDown:
+			marker.Symbol, marker.Color = '▀', ColorDanger
+		case storage.IncidentDegraded:
+			marker.Symbol, marker.Color = '▀', ColorWarning
+		case storage.IncidentPathChanged:
+			marker.Symbol, marker.Color = '↕', ColorSecondary
+		case storage.IncidentMaintenance:
+			marker.Symbol, marker.Color = '‖', ColorPrimary
+		case storage.IncidentUnreachable:
+			marker.Symbol, marker.Color = '▀', ColorMuted
+		default:
+			marker.Symbol, marker.Color = '•', ColorMuted
+		}
+		markers = append(markers, marker)
+	}
+	return markers
+}
+
+// formatIncidentDuration formats how long an incident lasted
+func formatIncidentDuration(inc storage.Incident) string {
+	if !inc.Ongoing && inc.DurationSecs == 0 {
+		return "-"
+	}
+	d := (time.Duration(inc.DurationSecs) * time.Second).String()
+	if inc.Ongoing {
+		d += "+"
+	}
+	return d
+}
+
+// renderEventsView renders the incident log of every source
+func (m Model) renderEventsView() string {
+	var b strings.Builder
+
+	header := TitleStyle.Render(" Events ")
+	backHint := lipgloss.NewStyle().Foreground(ColorMuted).Render("[Esc] back")
+
+	spacing := m.width - lipgloss.Width(header) - lipgloss.Width(backHint) - 2
+	if spacing < 1 {
+		spacing = 1
+	}
+
+	b.WriteString(lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		header,
+		strings.Repeat(" ", spacing),
+		backHint,
+	))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(m.renderError())
+		b.WriteString("\n\n")
+	}
+
+	columns := components.EventColumns(m.width, m.MultiSource())
+	table := components.NewTable(columns)
+	messageWidth := columns[len(columns)-1].Width
+
+	rows := []string{table.RenderHeader(), table.RenderSeparator()}
+	if len(m.events) == 0 {
+		rows = append(rows, "  "+lipgloss.NewStyle().Foreground(ColorMuted).Italic(true).Render("No events recorded"))
+	}
+
+	// Keep the selected row within the rows that fit
+	visible := max(m.height-8, 1)
+	first := max(min(m.eventsIdx-visible/2, len(m.events)-visible), 0)
+	for i := first; i < len(m.events) && i < first+visible; i++ {
+		e := m.events[i]
+		target := e.Target
+		if target == "" {
+			target = "(daemon)"
+		}
+
+		row := []string{e.Start.Format("Jan 02 15:04:05"), truncate(target, columns[1].Width)}
+		if m.MultiSource() {
+			row = append(row, truncate(sourceLabel(e.Source), columns[2].Width))
+		}
+		row = append(row,
+			incidentStyle(e.Kind).Render(string(e.Kind)),
+			formatIncidentDuration(e.Incident),
+			truncate(e.Message, messageWidth),
+		)
+		rows = append(rows, table.RenderRow(row, i == m.eventsIdx))
+	}
+	b.WriteString(strings.Join(rows, "\n"))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderEventsHelp())
+
+	return b.String()
+}
+
+// renderEventsHelp renders the help footer for the events pane
+func (m Model) renderEventsHelp() string {
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"Esc", "back"},
+		{"↑/↓", "scroll"},
+		{"r", "refresh"},
+		{"q", "quit"},
+	}
+
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts,
+			HelpKeyStyle.Render(k.key)+
+				HelpStyle.Render(" "+k.desc))
+	}
+
+	return HelpStyle.Render(strings.Join(parts, "  "))
+}
+
+// renderMarkerLegend explains the graph's event markers
+func renderMarkerLegend() string {
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	return strings.Join([]string{
+		LossStyle.Render("▀") + muted.Render(" down"),
+		LatencyWarnStyle.Render("▀") + muted.Render(" degraded"),
+		muted.Render("▀ unreachable"),
+		lipgloss.NewStyle().Foreground(ColorSecondary).Render("↕") + muted.Render(" path change"),
+		MaintenanceStyle.Render("‖") + muted.Render(" maintenance"),
+		muted.Render("• config/restart"),
+	}, "  ")
+}
@@ -0,0 +1,39 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestFormatIncidentDuration(t *testing.T) {
+	tests := []struct {
+		name string
+		inc  storage.Incident
+		want string
+	}{
+		{
+			name: "instant event",
+			inc:  storage.Incident{Kind: storage.IncidentPathChanged},
+			want: "-",
+		},
+		{
+			name: "ended outage",
+			inc:  storage.Incident{Kind: storage.IncidentDown, DurationSecs: 330},
+			want: "5m30s",
+		},
+		{
+			name: "ongoing",
+			inc:  storage.Incident{Kind: storage.IncidentDegraded, DurationSecs: 90.4, Ongoing: true},
+			want: "1m30s+",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatIncidentDuration(tt.inc); got != tt.want {
+				t.Errorf("formatIncidentDuration() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
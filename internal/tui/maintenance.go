@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/ipc"
+)
+
+// silenceDuration is how long the s key silences a target
+const silenceDuration = time.Hour
+
+// SilenceMsg reports a silence added or removed with the s key
+type SilenceMsg struct {
+	Source string
+	Target string
+	Err    error
+}
+
+// toggleSilence silences the selected target for an hour, or removes the
+// silence that currently covers it
+func (m Model) toggleSilence() (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+	source, name := target.Source, target.Config.Name
+
+	var client *ipc.Client
+	if m.IsIPCMode() {
+		client = m.client(source)
+		if client == nil || !client.Supports(ipc.MsgTypeAddSilence) {
+			m.err = fmt.Errorf("%s does not support silences", source)
+			return m, nil
+		}
+	}
+
+	if mt := target.Maintenance; mt != nil {
+		if mt.Silence == "" {
+			m.err = fmt.Errorf("%s is in maintenance window %s until %s", name, mt.Window, mt.End.Format("15:04"))
+			return m, nil
+		}
+		return m, removeSilence(m.collector, client, source, name, mt.Silence)
+	}
+
+	silence := collector.Silence{
+		Target:  target.Config.BaseName(),
+		End:     time.Now().Add(silenceDuration),
+		Comment: "Silenced from the TUI",
+	}
+	return m, addSilence(m.collector, client, source, name, silence)
+}
+
+// addSilence creates a command adding a silence through the collector, or
+// via IPC when client is set
+func addSilence(coll *collector.Collector, client *ipc.Client, source, target string, s collector.Silence) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if client != nil {
+			_, err = client.AddSilence(ipc.AddSilenceRequest{
+				Target:  s.Target,
+				End:     s.End,
+				Comment: s.Comment,
+			})
+		} else {
+			_, err = coll.AddSilence(s)
+		}
+		return SilenceMsg{Source: source, Target: target, Err: err}
+	}
+}
+
+// removeSilence creates a command removing a silence through the collector,
+// or via IPC when client is set
+func removeSilence(coll *collector.Collector, client *ipc.Client, source, target, id string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if client != nil {
+			err = client.RemoveSilence(id)
+		} else {
+			err = coll.RemoveSilence(id)
+		}
+		return SilenceMsg{Source: source, Target: target, Err: err}
+	}
+}
+
+// handleSilence refreshes the target whose silence changed
+func (m Model) handleSilence(msg SilenceMsg) (tea.Model, tea.Cmd) {
+	if msg.Err != nil {
+		m.err = msg.Err
+		return m, nil
+	}
+	m.err = nil
+	if !m.IsIPCMode() {
+		m.refreshAllStats()
+		return m, nil
+	}
+	if client := m.client(msg.Source); client != nil {
+		return m, fetchStatsIPC(client, msg.Source, msg.Target)
+	}
+	return m, nil
+}
+
+// renderMaintenance describes a target's maintenance period
+func renderMaintenance(mt *collector.Maintenance) string {
+	what := "silence " + mt.Silence
+	if mt.Window != "" {
+		what = "window " + mt.Window
+	}
+	if mt.Comment != "" {
+		what += ": " + mt.Comment
+	}
+	until := lipgloss.NewStyle().Foreground(ColorMuted).Render("until " + mt.End.Format("Jan 02 15:04") + ", alerts suppressed")
+	return MaintenanceStyle.Render("‖ Maintenance ("+what+")") + " " + until
+}
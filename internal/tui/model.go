@@ -18,6 +18,9 @@ type View int
 const (
 	ListView View = iota
 	DetailView
+	CompareView
+	EventsView
+	OverlayView
 )
 
 // TimeRange represents a historical data range
@@ -28,6 +31,7 @@ const (
 	TimeRange1Hour
 	TimeRange1Day
 	TimeRange1Week
+	TimeRangeCustom // A window panned, zoomed or jumped to
 )
 
 // String returns a display name for the time range
@@ -41,6 +45,8 @@ func (tr TimeRange) String() string {
 		return "1d"
 	case TimeRange1Week:
 		return "1w"
+	case TimeRangeCustom:
+		return "Custom"
 	default:
 		return "Unknown"
 	}
@@ -60,6 +66,11 @@ func (tr TimeRange) Duration() time.Duration {
 	}
 }
 
+// Window returns the period the time range covers, ending at now
+func (tr TimeRange) Window(now time.Time) TimeWindow {
+	return TimeWindow{From: now.Add(-tr.Duration()), To: now}
+}
+
 // Next returns the next time range in the cycle
 func (tr TimeRange) Next() TimeRange {
 	switch tr {
@@ -104,11 +115,25 @@ type Model struct {
 	// Data
 	targets []TargetState
 
-	// Dependencies - either collector (standalone) or ipcClient (daemon mode)
+	// Dependencies - either collector (standalone) or one or more daemons (IPC mode)
 	collector   *collector.Collector
-	ipcClient   *ipc.Client
+	sources     []Source
 	resultsChan <-chan probe.ProbeResult
-	ipcResults  <-chan ipc.ProbeResultData
+
+	// Target being compared across sources
+	compareTarget config.Target
+
+	// Events pane: incidents of every source, newest first
+	events     []SourcedIncident
+	eventsIdx  int
+	eventsBack View // View to return to
+
+	// Time range of the overlay of marked targets
+	overlayRange TimeRange
+
+	// Time entered with the g key in the detail view
+	jumping   bool
+	jumpInput string
 
 	// UI state
 	width  int
@@ -124,15 +149,27 @@ type Model struct {
 
 // TargetState holds state for a single target
 type TargetState struct {
+	Source  string // Daemon the target is measured from (empty for a single daemon)
 	Config  config.Target
 	Stats   *storage.Stats
-	History []float64 // Last N latencies for sparkline
+	History []float64          // Last N latencies for sparkline
+	Last    *probe.ProbeResult // Most recent probe result, with burst details
+	Anomaly *collector.Anomaly // Active deviation from the baseline
+
+	Maintenance *collector.Maintenance // Alerts suppressed until its end
+	Dependency  *collector.Dependency  // State of the target it depends on
+	Incidents   []storage.Incident     // Recent incidents, newest first, marked on the graph
+	Marked      bool                   // Shown in the overlay view
 
 	// Historical data (Phase 5)
 	TimeRange       TimeRange           // Current selected time range
 	HistoricalData  []storage.DataPoint // Fetched historical data
 	HistoricalStats *HistoricalStats    // Stats for all time periods
 	LoadingHistory  bool                // True while fetching
+
+	Window      TimeWindow   // Period shown with TimeRangeCustom
+	WindowStats *PeriodStats // Stats of the custom window
+	Cursor      time.Time    // Crosshair position in the graph, zero when hidden
 }
 
 // NewModel creates a new Model with the given collector
@@ -146,38 +183,73 @@ func NewModel(coll *collector.Collector, apiAddr string) Model {
 	}
 
 	return Model{
-		currentView: ListView,
-		selectedIdx: 0,
-		targets:     targets,
-		collector:   coll,
-		resultsChan: coll.Subscribe(),
-		apiAddr:     apiAddr,
+		currentView:  ListView,
+		selectedIdx:  0,
+		targets:      targets,
+		collector:    coll,
+		resultsChan:  coll.Subscribe(),
+		overlayRange: TimeRange1Hour,
+		apiAddr:      apiAddr,
 	}
 }
 
 // NewModelWithIPC creates a new Model connected to a daemon via IPC
 func NewModelWithIPC(client *ipc.Client, targetConfigs []config.Target, apiAddr string) Model {
-	targets := make([]TargetState, len(targetConfigs))
-	for i, t := range targetConfigs {
-		targets[i] = TargetState{
-			Config:  t,
-			History: make([]float64, 0, 100),
+	return NewModelWithSources([]Source{{Client: client}}, map[string][]config.Target{"": targetConfigs}, apiAddr)
+}
+
+// NewModelWithSources creates a new Model connected to several daemons via IPC.
+// targetConfigs holds each source's targets, keyed by source name.
+func NewModelWithSources(sources []Source, targetConfigs map[string][]config.Target, apiAddr string) Model {
+	var targets []TargetState
+	for _, src := range sources {
+		for _, t := range targetConfigs[src.Name] {
+			targets = append(targets, TargetState{
+				Source:  src.Name,
+				Config:  t,
+				History: make([]float64, 0, 100),
+			})
 		}
 	}
 
 	return Model{
-		currentView: ListView,
-		selectedIdx: 0,
-		targets:     targets,
-		ipcClient:   client,
-		ipcResults:  client.Results(),
-		apiAddr:     apiAddr,
+		currentView:  ListView,
+		selectedIdx:  0,
+		targets:      targets,
+		sources:      sources,
+		overlayRange: TimeRange1Hour,
+		apiAddr:      apiAddr,
 	}
 }
 
 // IsIPCMode returns true if the model is connected via IPC
 func (m Model) IsIPCMode() bool {
-	return m.ipcClient != nil
+	return len(m.sources) > 0
+}
+
+// MultiSource returns true if targets come from more than one daemon
+func (m Model) MultiSource() bool {
+	return len(m.sources) > 1
+}
+
+// client returns the IPC client for a source
+func (m Model) client(source string) *ipc.Client {
+	for _, src := range m.sources {
+		if src.Name == source {
+			return src.Client
+		}
+	}
+	return nil
+}
+
+// findTarget returns the index of a target by source and name, or -1
+func (m Model) findTarget(source, name string) int {
+	for i := range m.targets {
+		if m.targets[i].Source == source && m.targets[i].Config.Name == name {
+			return i
+		}
+	}
+	return -1
 }
 
 // SelectedTarget returns the currently selected target
@@ -197,9 +269,13 @@ func (m *Model) updateTargetStats(result probe.ProbeResult) {
 			if len(m.targets[i].History) > 100 {
 				m.targets[i].History = m.targets[i].History[1:]
 			}
+			m.targets[i].Last = &result
 
 			// Update stats from collector
 			m.targets[i].Stats = m.collector.GetStats(result.Target)
+			m.targets[i].Anomaly = m.collector.Anomaly(result.Target)
+			m.targets[i].Maintenance = m.collector.Maintenance(result.Target)
+			m.targets[i].Dependency = m.collector.Dependency(result.Target)
 			break
 		}
 	}
@@ -208,63 +284,34 @@ func (m *Model) updateTargetStats(result probe.ProbeResult) {
 // refreshAllStats refreshes stats for all targets
 func (m *Model) refreshAllStats() {
 	if m.IsIPCMode() {
-		// In IPC mode, stats are updated via IPCProbeResultMsg
-		// We could fetch stats here but it would block the UI
+		// In IPC mode, stats are fetched from the daemon after each IPCProbeResultMsg
 		return
 	}
 	for i := range m.targets {
 		m.targets[i].Stats = m.collector.GetStats(m.targets[i].Config.Name)
+		m.targets[i].Anomaly = m.collector.Anomaly(m.targets[i].Config.Name)
+		m.targets[i].Maintenance = m.collector.Maintenance(m.targets[i].Config.Name)
+		m.targets[i].Dependency = m.collector.Dependency(m.targets[i].Config.Name)
 		m.targets[i].History = m.collector.GetHistory(m.targets[i].Config.Name, 100)
 	}
 }
 
-// updateTargetStatsFromIPC updates stats for a target from an IPC probe result
-func (m *Model) updateTargetStatsFromIPC(result ipc.ProbeResultData) {
-	for i := range m.targets {
-		if m.targets[i].Config.Name == result.Target {
-			// Update history
-			m.targets[i].History = append(m.targets[i].History, result.LatencyMs)
-			if len(m.targets[i].History) > 100 {
-				m.targets[i].History = m.targets[i].History[1:]
-			}
+// updateTargetStatsFromIPC records an IPC probe result and reports whether
+// the target is known. Aggregate stats are fetched from the daemon afterwards
+// so they match standalone mode.
+func (m *Model) updateTargetStatsFromIPC(source string, data ipc.ProbeResultData) bool {
+	i := m.findTarget(source, data.Target)
+	if i < 0 {
+		return false
+	}
 
-			// Update basic stats from result
-			if m.targets[i].Stats == nil {
-				m.targets[i].Stats = &storage.Stats{
-					Target: result.Target,
-				}
-			}
-			m.targets[i].Stats.LastMs = result.LatencyMs
-			m.targets[i].Stats.SampleCount++
-
-			// Recalculate stats from history
-			if len(m.targets[i].History) > 0 {
-				m.targets[i].Stats.MinMs = m.targets[i].History[0]
-				m.targets[i].Stats.MaxMs = m.targets[i].History[0]
-				var sum float64
-				var lossCount int
-				for _, v := range m.targets[i].History {
-					if v < 0 {
-						lossCount++
-						continue
-					}
-					sum += v
-					if v < m.targets[i].Stats.MinMs || m.targets[i].Stats.MinMs < 0 {
-						m.targets[i].Stats.MinMs = v
-					}
-					if v > m.targets[i].Stats.MaxMs {
-						m.targets[i].Stats.MaxMs = v
-					}
-				}
-				validCount := len(m.targets[i].History) - lossCount
-				if validCount > 0 {
-					m.targets[i].Stats.AvgMs = sum / float64(validCount)
-				}
-				m.targets[i].Stats.LossPct = float64(lossCount) / float64(len(m.targets[i].History)) * 100
-			}
-			break
-		}
+	result := data.ProbeResult()
+	m.targets[i].History = append(m.targets[i].History, result.LatencyMs)
+	if len(m.targets[i].History) > 100 {
+		m.targets[i].History = m.targets[i].History[1:]
 	}
+	m.targets[i].Last = &result
+	return true
 }
 
 // calculatePeriodStats calculates summary statistics from data points.
@@ -0,0 +1,366 @@
+package tui
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+// Bounds of a custom graph window when zooming
+const (
+	minWindow = 10 * time.Minute
+	maxWindow = 366 * 24 * time.Hour
+)
+
+// TimeWindow is a period of history shown in the detail graph
+type TimeWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// Duration returns the length of the window
+func (w TimeWindow) Duration() time.Duration {
+	return w.To.Sub(w.From)
+}
+
+// Equal reports whether two windows cover the same period
+func (w TimeWindow) Equal(o TimeWindow) bool {
+	return w.From.Equal(o.From) && w.To.Equal(o.To)
+}
+
+// Contains reports whether t lies within the window
+func (w TimeWindow) Contains(t time.Time) bool {
+	return !t.Before(w.From) && !t.After(w.To)
+}
+
+// String returns a display label for the window
+func (w TimeWindow) String() string {
+	layout := "Jan 02 15:04"
+	if w.Duration() < time.Hour {
+		layout = "Jan 02 15:04:05"
+	}
+	to := w.To.Format(layout)
+	if sameDay(w.From, w.To) {
+		to = w.To.Format(strings.TrimPrefix(layout, "Jan 02 "))
+	}
+	return w.From.Format(layout) + " – " + to
+}
+
+// Pan shifts the window by a fraction of its length, backwards for negative
+// fractions, without moving it past now
+func (w TimeWindow) Pan(frac float64, now time.Time) TimeWindow {
+	shift := time.Duration(frac * float64(w.Duration()))
+	return TimeWindow{From: w.From.Add(shift), To: w.To.Add(shift)}.clamp(now)
+}
+
+// Zoom scales the window by factor around at, which keeps its relative
+// position. The length stays within minWindow and maxWindow.
+func (w TimeWindow) Zoom(factor float64, at, now time.Time) TimeWindow {
+	d := time.Duration(factor * float64(w.Duration()))
+	d = min(max(d, minWindow), maxWindow)
+	if !w.Contains(at) {
+		at = w.From.Add(w.Duration() / 2)
+	}
+	ratio := 0.5
+	if w.Duration() > 0 {
+		ratio = float64(at.Sub(w.From)) / float64(w.Duration())
+	}
+	from := at.Add(-time.Duration(ratio * float64(d)))
+	return TimeWindow{From: from, To: from.Add(d)}.clamp(now)
+}
+
+// clamp shifts the window back so it ends no later than now
+func (w TimeWindow) clamp(now time.Time) TimeWindow {
+	if w.To.After(now) {
+		shift := w.To.Sub(now)
+		w.From, w.To = w.From.Add(-shift), now
+	}
+	return w
+}
+
+// windowAround returns a window of length d centered on t, ending no later
+// than now
+func windowAround(t time.Time, d time.Duration, now time.Time) TimeWindow {
+	return TimeWindow{From: t.Add(-d / 2), To: t.Add(d / 2)}.clamp(now)
+}
+
+// sameDay reports whether a and b fall on the same calendar day
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// Time of day layouts accepted by parseJump, tried in order
+var clockLayouts = []string{"15:04", "15:04:05", "3pm", "3:04pm"}
+
+// parseJump parses the time entered with the g key: a date ("2024-01-02"),
+// "today", "yesterday" or a weekday ("tue"), a time of day ("15:04", "3am"),
+// or a day followed by a time. Days without a date are the most recent ones
+// whose time has passed. It returns the window to show, of length d around
+// the time or the whole day if no time was given, and the time to put the
+// cursor on (zero without a time).
+func parseJump(s string, d time.Duration, now time.Time) (TimeWindow, time.Time, error) {
+	fields := strings.Fields(strings.ToLower(s))
+	if len(fields) == 0 || len(fields) > 2 {
+		return TimeWindow{}, time.Time{}, fmt.Errorf("cannot parse %q: want a date, a time or both", s)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	day, dated := parseDay(fields[0], today)
+	if len(fields) == 2 && !dated {
+		return TimeWindow{}, time.Time{}, fmt.Errorf("cannot parse day %q", fields[0])
+	}
+	if len(fields) == 1 && dated {
+		// The whole day, cut at now
+		w := TimeWindow{From: day, To: day.AddDate(0, 0, 1)}
+		if w.To.After(now) {
+			w.To = now
+		}
+		if !w.From.Before(w.To) {
+			return TimeWindow{}, time.Time{}, fmt.Errorf("%s is in the future", s)
+		}
+		return w, time.Time{}, nil
+	}
+
+	clock := fields[len(fields)-1]
+	offset, ok := parseClock(clock)
+	if !ok {
+		return TimeWindow{}, time.Time{}, fmt.Errorf("cannot parse time %q", clock)
+	}
+	at := day.Add(offset)
+	if !dated {
+		// A time alone is the most recent one that has passed
+		at = today.Add(offset)
+		if at.After(now) {
+			at = at.AddDate(0, 0, -1)
+		}
+	} else if _, weekday := parseWeekday(fields[0]); weekday && at.After(now) {
+		// Today's weekday, but later than now: the week before
+		at = at.AddDate(0, 0, -7)
+	}
+	if at.After(now) {
+		return TimeWindow{}, time.Time{}, fmt.Errorf("%s is in the future", s)
+	}
+	return windowAround(at, d, now), at, nil
+}
+
+// parseDay parses the day part of a jump relative to today
+func parseDay(s string, today time.Time) (time.Time, bool) {
+	switch s {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	}
+	if wd, ok := parseWeekday(s); ok {
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		return today.AddDate(0, 0, -back), true
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, today.Location()); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+// parseWeekday parses a weekday name or its first three letters
+func parseWeekday(s string) (time.Weekday, bool) {
+	if len(s) < 3 {
+		return 0, false
+	}
+	for wd := time.Sunday; wd <= time.Saturday; wd++ {
+		if strings.HasPrefix(strings.ToLower(wd.String()), s) {
+			return wd, true
+		}
+	}
+	return 0, false
+}
+
+// parseClock parses a time of day into the offset from midnight
+func parseClock(s string) (time.Duration, bool) {
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// graphWindow returns the period shown in the detail graph of a target
+func (t *TargetState) graphWindow(now time.Time) TimeWindow {
+	switch {
+	case t.TimeRange == TimeRangeCustom:
+		return t.Window
+	case t.TimeRange != TimeRangeRealtime && len(t.HistoricalData) > 0:
+		return TimeWindow{From: t.HistoricalData[0].Timestamp, To: t.HistoricalData[len(t.HistoricalData)-1].Timestamp}
+	case t.TimeRange == TimeRangeRealtime:
+		// Navigating away from realtime starts from the last hour
+		return TimeRange1Hour.Window(now)
+	}
+	return t.TimeRange.Window(now)
+}
+
+// graphColumns returns the number of data columns in the detail graph
+func (m Model) graphColumns() int {
+	return max(m.graphWidth()-graphYAxisWidth, 10)
+}
+
+// panGraph shifts the selected target's graph by a fraction of its window
+func (m Model) panGraph(frac float64) (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+	now := time.Now()
+	return m.showWindow(target.graphWindow(now).Pan(frac, now))
+}
+
+// zoomGraph scales the selected target's graph window around the cursor, or
+// around its center without one
+func (m Model) zoomGraph(factor float64) (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+	now := time.Now()
+	return m.showWindow(target.graphWindow(now).Zoom(factor, target.Cursor, now))
+}
+
+// moveCursor moves the crosshair of the selected target's graph by a number
+// of graph columns, showing it in the middle of the graph first
+func (m Model) moveCursor(cols int) (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+	now := time.Now()
+	w := target.graphWindow(now)
+
+	var cmd tea.Cmd
+	if target.TimeRange == TimeRangeRealtime {
+		// The crosshair reads stored history, not the live buffer
+		var model tea.Model
+		model, cmd = m.showWindow(w)
+		m = model.(Model)
+		target = m.SelectedTarget()
+	}
+
+	step := w.Duration() / time.Duration(max(m.graphColumns()-1, 1))
+	if target.Cursor.IsZero() || !w.Contains(target.Cursor) {
+		target.Cursor = w.From.Add(w.Duration() / 2)
+	} else {
+		target.Cursor = target.Cursor.Add(time.Duration(cols) * step)
+	}
+	if target.Cursor.Before(w.From) {
+		target.Cursor = w.From
+	}
+	if target.Cursor.After(w.To) {
+		target.Cursor = w.To
+	}
+	return m, cmd
+}
+
+// showWindow switches the selected target's graph to a custom window and
+// fetches its history and incidents
+func (m Model) showWindow(w TimeWindow) (tea.Model, tea.Cmd) {
+	target := m.SelectedTarget()
+	if target == nil {
+		return m, nil
+	}
+	target.TimeRange = TimeRangeCustom
+	target.Window = w
+	target.LoadingHistory = true
+	if !target.Cursor.IsZero() && !w.Contains(target.Cursor) {
+		target.Cursor = time.Time{}
+	}
+
+	q := storage.IncidentQuery{Target: target.Config.Name, From: w.From, To: w.To, Limit: eventsLimit}
+	return m, tea.Batch(
+		m.fetchWindowCmd(target.Source, target.Config.Name, w),
+		m.fetchIncidentsCmd(target.Source, q),
+	)
+}
+
+// handleJumpKeys edits the time entered after g and jumps to it on Enter
+func (m Model) handleJumpKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		m.jumping = false
+
+	case tea.KeyEnter:
+		m.jumping = false
+		target := m.SelectedTarget()
+		if target == nil {
+			return m, nil
+		}
+		now := time.Now()
+		d := target.graphWindow(now).Duration()
+		w, at, err := parseJump(m.jumpInput, d, now)
+		if err != nil {
+			m.err = err
+			return m, nil
+		}
+		m.err = nil
+		target.Cursor = at
+		return m.showWindow(w)
+
+	case tea.KeyBackspace:
+		if runes := []rune(m.jumpInput); len(runes) > 0 {
+			m.jumpInput = string(runes[:len(runes)-1])
+		}
+
+	case tea.KeySpace:
+		m.jumpInput += " "
+
+	case tea.KeyRunes:
+		m.jumpInput += string(msg.Runes)
+	}
+	return m, nil
+}
+
+// pointAt returns the data point nearest to t
+func pointAt(data []storage.DataPoint, t time.Time) (storage.DataPoint, bool) {
+	if len(data) == 0 {
+		return storage.DataPoint{}, false
+	}
+	i := sort.Search(len(data), func(i int) bool { return !data[i].Timestamp.Before(t) })
+	if i == len(data) || (i > 0 && t.Sub(data[i-1].Timestamp) < data[i].Timestamp.Sub(t)) {
+		i--
+	}
+	return data[i], true
+}
+
+// renderCursor describes the data point under the graph's crosshair
+func renderCursor(target *TargetState) string {
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	label := CursorStyle.Render("┊ " + target.Cursor.Format("Mon Jan 02 15:04:05"))
+
+	dp, ok := pointAt(target.HistoricalData, target.Cursor)
+	if !ok || math.IsNaN(dp.Loss) {
+		// NaN loss means no data was collected
+		return label + "  " + muted.Render("no data")
+	}
+	median := FormatLatency(-1)
+	if !math.IsNaN(dp.Value) {
+		median = FormatLatency(dp.Value)
+	}
+	return label + "  " + muted.Render("median ") + strings.TrimSpace(median) +
+		"  " + muted.Render("loss ") + FormatLoss(dp.Loss*100) +
+		"  " + muted.Render("at "+dp.Timestamp.Format("15:04:05"))
+}
+
+// renderJumpPrompt renders the input line of the g key
+func (m Model) renderJumpPrompt() string {
+	examples := lipgloss.NewStyle().Foreground(ColorMuted).Render("  e.g. 2024-01-02 15:04, tue 3am, yesterday  [Enter] go  [Esc] cancel")
+	return HelpKeyStyle.Render("Go to: ") + m.jumpInput + "█" + examples
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestTimeWindowNavigation(t *testing.T) {
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return time.Date(2024, 1, 10, h, m, 0, 0, time.UTC) }
+	hour := TimeWindow{From: at(10, 0), To: at(11, 0)}
+
+	tests := []struct {
+		name string
+		got  TimeWindow
+		want TimeWindow
+	}{
+		{
+			name: "pan back",
+			got:  hour.Pan(-0.5, now),
+			want: TimeWindow{From: at(9, 30), To: at(10, 30)},
+		},
+		{
+			name: "pan forward stops at now",
+			got:  hour.Pan(2, now),
+			want: TimeWindow{From: at(11, 0), To: at(12, 0)},
+		},
+		{
+			name: "zoom in around the center",
+			got:  hour.Zoom(0.5, time.Time{}, now),
+			want: TimeWindow{From: at(10, 15), To: at(10, 45)},
+		},
+		{
+			name: "zoom in around the cursor",
+			got:  hour.Zoom(0.5, at(10, 40), now),
+			want: TimeWindow{From: at(10, 20), To: at(10, 50)},
+		},
+		{
+			name: "zoom in stops at the minimum",
+			got:  hour.Zoom(0.01, time.Time{}, now),
+			want: TimeWindow{From: at(10, 25), To: at(10, 35)},
+		},
+		{
+			name: "zoom out stops at now",
+			got:  hour.Zoom(4, time.Time{}, now),
+			want: TimeWindow{From: at(8, 0), To: at(12, 0)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.got.Equal(tt.want) {
+				t.Errorf("window = %s, want %s", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJump(t *testing.T) {
+	// A Wednesday
+	now := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	day := func(d, h, m int) time.Time { return time.Date(2024, 1, d, h, m, 0, 0, time.UTC) }
+
+	tests := []struct {
+		input      string
+		wantWindow TimeWindow
+		wantCursor time.Time
+		wantErr    bool
+	}{
+		{
+			input:      "2024-01-02 15:04",
+			wantWindow: TimeWindow{From: day(2, 14, 34), To: day(2, 15, 34)},
+			wantCursor: day(2, 15, 4),
+		},
+		{
+			input:      "2024-01-02",
+			wantWindow: TimeWindow{From: day(2, 0, 0), To: day(3, 0, 0)},
+		},
+		{
+			input:      "today",
+			wantWindow: TimeWindow{From: day(10, 0, 0), To: now},
+		},
+		{
+			input:      "tue 3am",
+			wantWindow: TimeWindow{From: day(9, 2, 30), To: day(9, 3, 30)},
+			wantCursor: day(9, 3, 0),
+		},
+		{
+			input:      "Wednesday 13:00",
+			wantWindow: TimeWindow{From: day(3, 12, 30), To: day(3, 13, 30)},
+			wantCursor: day(3, 13, 0),
+		},
+		{
+			input:      "yesterday 23:30",
+			wantWindow: TimeWindow{From: day(9, 23, 0), To: day(10, 0, 0)},
+			wantCursor: day(9, 23, 30),
+		},
+		{
+			input:      "11:45pm",
+			wantWindow: TimeWindow{From: day(9, 23, 15), To: day(10, 0, 15)},
+			wantCursor: day(9, 23, 45),
+		},
+		{
+			input:      "11:50",
+			wantWindow: TimeWindow{From: day(10, 11, 0), To: now},
+			wantCursor: day(10, 11, 50),
+		},
+		{input: "2024-02-01", wantErr: true},
+		{input: "today 18:00", wantErr: true},
+		{input: "someday 3am", wantErr: true},
+		{input: "tue 25:00", wantErr: true},
+		{input: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			w, cursor, err := parseJump(tt.input, time.Hour, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseJump(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !w.Equal(tt.wantWindow) || !cursor.Equal(tt.wantCursor) {
+				t.Errorf("parseJump(%q) = %s cursor %v, want %s cursor %v",
+					tt.input, w, cursor, tt.wantWindow, tt.wantCursor)
+			}
+		})
+	}
+}
+
+func TestPointAt(t *testing.T) {
+	start := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	data := []storage.DataPoint{
+		{Timestamp: start, Value: 10},
+		{Timestamp: start.Add(time.Minute), Value: 20},
+		{Timestamp: start.Add(2 * time.Minute), Value: 30},
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want float64
+	}{
+		{"before the first", start.Add(-time.Hour), 10},
+		{"nearer the earlier", start.Add(20 * time.Second), 10},
+		{"nearer the later", start.Add(40 * time.Second), 20},
+		{"exact", start.Add(2 * time.Minute), 30},
+		{"after the last", start.Add(time.Hour), 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dp, ok := pointAt(data, tt.at)
+			if !ok || dp.Value != tt.want {
+				t.Errorf("pointAt() = %v, %v, want %v", dp.Value, ok, tt.want)
+			}
+		})
+	}
+
+	if _, ok := pointAt(nil, start); ok {
+		t.Error("pointAt(nil) = ok, want none")
+	}
+}
@@ -0,0 +1,315 @@
+package tui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/tui/components"
+)
+
+// overlayColors tell the series of the overlay graph apart, in the order the
+// targets are marked in the list
+var overlayColors = []lipgloss.Color{
+	"#06B6D4", // Cyan
+	"#F59E0B", // Amber
+	"#EC4899", // Pink
+	"#10B981", // Green
+	"#8B5CF6", // Violet
+	"#F97316", // Orange
+	"#3B82F6", // Blue
+	"#EAB308", // Yellow
+}
+
+// overlayGraphHeight is the height of the overlay graph, taller than the
+// detail graph as it holds several lines
+const overlayGraphHeight = 12
+
+// markedTargets returns the indices of the targets marked for the overlay,
+// in list order
+func (m Model) markedTargets() []int {
+	var idx []int
+	for i := range m.targets {
+		if m.targets[i].Marked {
+			idx = append(idx, i)
+		}
+	}
+	return idx
+}
+
+// seriesColor returns the overlay color of a marked target
+func (m Model) seriesColor(idx int) lipgloss.Color {
+	n := 0
+	for i := 0; i < idx; i++ {
+		if m.targets[i].Marked {
+			n++
+		}
+	}
+	return overlayColors[n%len(overlayColors)]
+}
+
+// toggleMark marks the selected target for the overlay, or unmarks it
+func (m Model) toggleMark() (tea.Model, tea.Cmd) {
+	if target := m.SelectedTarget(); target != nil {
+		target.Marked = !target.Marked
+	}
+	return m, nil
+}
+
+// openOverlay switches to the overlay view of the marked targets and
+// fetches their history for the overlay's time range
+func (m Model) openOverlay() (tea.Model, tea.Cmd) {
+	if len(m.markedTargets()) < 2 {
+		m.err = errors.New("mark at least two targets with m to overlay them")
+		return m, nil
+	}
+	m.err = nil
+	m.currentView = OverlayView
+	return m.setOverlayRange(m.overlayRange)
+}
+
+// setOverlayRange shows every marked target over a time range. The marked
+// targets' graphs switch to it too, so their data and stats are shared
+// with the detail view.
+func (m Model) setOverlayRange(tr TimeRange) (tea.Model, tea.Cmd) {
+	m.overlayRange = tr
+
+	var cmds []tea.Cmd
+	for _, i := range m.markedTargets() {
+		target := &m.targets[i]
+		if target.TimeRange == TimeRangeCustom {
+			// Incidents were fetched for the custom window
+			cmds = append(cmds, m.fetchEventsCmd(target.Source, target.Config.Name))
+		}
+		target.TimeRange = tr
+		target.Cursor = time.Time{}
+		if tr == TimeRangeRealtime {
+			continue
+		}
+		target.LoadingHistory = true
+		cmds = append(cmds, m.fetchHistoricalDataCmd(target.Source, target.Config.Name, tr))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// handleOverlayViewKeys handles keys in the overlay view
+func (m Model) handleOverlayViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "backspace", "o":
+		m.currentView = ListView
+
+	case "0":
+		return m.setOverlayRange(TimeRangeRealtime)
+
+	case "1":
+		return m.setOverlayRange(TimeRange1Hour)
+
+	case "2":
+		return m.setOverlayRange(TimeRange1Day)
+
+	case "3":
+		return m.setOverlayRange(TimeRange1Week)
+
+	case "tab":
+		return m.setOverlayRange(m.overlayRange.Next())
+
+	case "r":
+		m.refreshAllStats()
+		return m.setOverlayRange(m.overlayRange)
+	}
+
+	return m, nil
+}
+
+// overlayStats returns a target's stats over the overlay's time range, or
+// nil if there are none yet
+func (m Model) overlayStats(target *TargetState) *PeriodStats {
+	if m.overlayRange == TimeRangeRealtime {
+		if target.Stats == nil {
+			return nil
+		}
+		s := target.Stats
+		return &PeriodStats{
+			MinMs:    s.MinMs,
+			MaxMs:    s.MaxMs,
+			AvgMs:    s.AvgMs,
+			MedianMs: s.MedianMs,
+			P95Ms:    s.P95Ms,
+			StdDevMs: s.StdDevMs,
+			LossPct:  s.LossPct,
+			Samples:  s.SampleCount,
+		}
+	}
+	if target.HistoricalStats == nil {
+		return nil
+	}
+	switch m.overlayRange {
+	case TimeRange1Hour:
+		return target.HistoricalStats.Hour
+	case TimeRange1Day:
+		return target.HistoricalStats.Day
+	case TimeRange1Week:
+		return target.HistoricalStats.Week
+	}
+	return nil
+}
+
+// overlaySeries returns a target's graph series over the overlay's time range
+func (m Model) overlaySeries(target *TargetState, color lipgloss.Color) components.GraphSeries {
+	if m.overlayRange == TimeRangeRealtime {
+		return components.GraphSeries{Points: components.GraphFromFloats(target.History, 10*time.Second), Color: color}
+	}
+	points := make([]components.GraphPoint, len(target.HistoricalData))
+	for i, dp := range target.HistoricalData {
+		points[i] = components.GraphPoint{Timestamp: dp.Timestamp, Value: dp.Value}
+	}
+	return components.GraphSeries{Points: points, Color: color}
+}
+
+// renderOverlayView renders the marked targets' series on one graph, with a
+// legend of their stats
+func (m Model) renderOverlayView() string {
+	var b strings.Builder
+
+	marked := m.markedTargets()
+	header := TitleStyle.Render(fmt.Sprintf(" Overlay: %d targets ", len(marked)))
+	backHint := lipgloss.NewStyle().Foreground(ColorMuted).Render("[Esc] back")
+
+	spacing := m.width - lipgloss.Width(header) - lipgloss.Width(backHint) - 2
+	if spacing < 1 {
+		spacing = 1
+	}
+
+	b.WriteString(lipgloss.JoinHorizontal(
+		lipgloss.Center,
+		header,
+		strings.Repeat(" ", spacing),
+		backHint,
+	))
+	b.WriteString("\n\n")
+
+	if m.err != nil {
+		b.WriteString(m.renderError())
+		b.WriteString("\n\n")
+	}
+
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary)
+	b.WriteString(sectionStyle.Render("Latency Graph"))
+	b.WriteString("  ")
+	b.WriteString(m.renderTimeRangeTabs(m.overlayRange))
+	b.WriteString("\n")
+
+	// One time axis spanning every series
+	var series []components.GraphSeries
+	var from, to time.Time
+	for _, i := range marked {
+		s := m.overlaySeries(&m.targets[i], m.seriesColor(i))
+		series = append(series, s)
+		if len(s.Points) == 0 {
+			continue
+		}
+		first, last := s.Points[0].Timestamp, s.Points[len(s.Points)-1].Timestamp
+		if from.IsZero() || first.Before(from) {
+			from = first
+		}
+		if last.After(to) {
+			to = last
+		}
+	}
+
+	config := components.GraphConfig{
+		Width:      m.graphWidth(),
+		Height:     overlayGraphHeight,
+		ShowYAxis:  true,
+		ShowXAxis:  true,
+		YAxisWidth: graphYAxisWidth,
+	}
+	b.WriteString(components.OverlayGraph(series, from, to, config))
+	b.WriteString("\n\n")
+
+	b.WriteString(m.renderOverlayLegend(marked))
+	b.WriteString("\n")
+
+	b.WriteString(m.renderOverlayHelp())
+
+	return b.String()
+}
+
+// renderOverlayLegend renders a line per marked target: its color, name and
+// stats over the overlay's time range
+func (m Model) renderOverlayLegend(marked []int) string {
+	var b strings.Builder
+
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	nameStyle := lipgloss.NewStyle().Width(24)
+
+	label := "current run"
+	switch m.overlayRange {
+	case TimeRange1Hour:
+		label = "last hour"
+	case TimeRange1Day:
+		label = "last day"
+	case TimeRange1Week:
+		label = "last week"
+	}
+	b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary).Render("Legend"))
+	b.WriteString(" (" + label + ")\n")
+
+	for _, i := range marked {
+		target := &m.targets[i]
+		name := target.Config.Name
+		if target.Source != "" {
+			name += " @ " + target.Source
+		}
+
+		b.WriteString("  ")
+		b.WriteString(lipgloss.NewStyle().Foreground(m.seriesColor(i)).Render("━━"))
+		b.WriteString(" ")
+		b.WriteString(nameStyle.Render(truncate(name, 23)))
+
+		stats := m.overlayStats(target)
+		switch {
+		case target.LoadingHistory:
+			b.WriteString(muted.Render("loading…"))
+		case stats == nil:
+			b.WriteString(muted.Render("no data"))
+		default:
+			b.WriteString(muted.Render("median ") + FormatLatency(stats.MedianMs))
+			b.WriteString(muted.Render("  avg ") + FormatLatency(stats.AvgMs))
+			b.WriteString(muted.Render("  p95 ") + FormatLatency(stats.P95Ms))
+			b.WriteString(muted.Render("  loss ") + FormatLoss(stats.LossPct))
+		}
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
+// renderOverlayHelp renders the help footer for the overlay view
+func (m Model) renderOverlayHelp() string {
+	keys := []struct {
+		key  string
+		desc string
+	}{
+		{"Esc", "back"},
+		{"0-3", "range"},
+		{"Tab", "cycle"},
+		{"r", "refresh"},
+		{"q", "quit"},
+	}
+
+	var parts []string
+	for _, k := range keys {
+		parts = append(parts,
+			HelpKeyStyle.Render(k.key)+
+				HelpStyle.Render(" "+k.desc))
+	}
+
+	return HelpStyle.Render(strings.Join(parts, "  "))
+}
@@ -0,0 +1,87 @@
+package tui
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/config"
+	"github.com/wellsgz/pulse/internal/storage"
+)
+
+func TestSeriesColor(t *testing.T) {
+	m := Model{targets: make([]TargetState, len(overlayColors)+3)}
+	for _, i := range []int{1, 2, 4} {
+		m.targets[i].Marked = true
+	}
+	for i := len(overlayColors); i < len(m.targets); i++ {
+		m.targets[i].Marked = true
+	}
+
+	if got, want := m.markedTargets()[:3], []int{1, 2, 4}; !slices.Equal(got, want) {
+		t.Errorf("markedTargets() = %v, want %v", got, want)
+	}
+
+	tests := []struct {
+		idx  int
+		want lipgloss.Color
+	}{
+		{1, overlayColors[0]},
+		{2, overlayColors[1]},
+		{4, overlayColors[2]},
+		// The colors repeat after the last one
+		{len(m.targets) - 1, overlayColors[(len(m.targets)-len(overlayColors)+2)%len(overlayColors)]},
+	}
+	for _, tt := range tests {
+		if got := m.seriesColor(tt.idx); got != tt.want {
+			t.Errorf("seriesColor(%d) = %s, want %s", tt.idx, got, tt.want)
+		}
+	}
+}
+
+func TestRenderOverlayView(t *testing.T) {
+	start := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	points := func(first, last int) []storage.DataPoint {
+		var dps []storage.DataPoint
+		for m := first; m <= last; m++ {
+			dps = append(dps, storage.DataPoint{Timestamp: start.Add(time.Duration(m) * time.Minute), Value: 10})
+		}
+		return dps
+	}
+
+	m := Model{width: 60, currentView: OverlayView, overlayRange: TimeRange1Hour, targets: []TargetState{
+		{
+			Config:          config.Target{Name: "google"},
+			Marked:          true,
+			HistoricalData:  points(0, 30),
+			HistoricalStats: &HistoricalStats{Hour: &PeriodStats{MedianMs: 12, AvgMs: 13, P95Ms: 20, LossPct: 1.5}},
+		},
+		// Not marked, so its longer history does not stretch the axis
+		{Config: config.Target{Name: "quad9"}, HistoricalData: points(0, 50)},
+		{Config: config.Target{Name: "cloudflare"}, Source: "tokyo", Marked: true, HistoricalData: points(20, 45), LoadingHistory: true},
+		{Config: config.Target{Name: "opendns"}, Marked: true},
+	}}
+
+	// Compare the plain text, with spacing collapsed
+	var lines []string
+	for _, line := range strings.Split(regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(m.renderOverlayView(), ""), "\n") {
+		lines = append(lines, strings.Join(strings.Fields(line), " "))
+	}
+
+	want := []string{
+		// The time axis spans the marked series
+		"12:00:00 12:45:00",
+		"Legend (last hour)",
+		"━━ google median 12ms avg 13ms p95 20ms loss 1.5%",
+		"━━ cloudflare @ tokyo loading…",
+		"━━ opendns no data",
+	}
+	for _, w := range want {
+		if !slices.Contains(lines, w) {
+			t.Errorf("renderOverlayView() has no line %q:\n%s", w, strings.Join(lines, "\n"))
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+
+	"github.com/wellsgz/pulse/internal/ipc"
+)
+
+// Source is a daemon the TUI is attached to
+type Source struct {
+	Name   string // Shown in the source column (empty for a single daemon)
+	Client *ipc.Client
+}
+
+// SourceSpec describes a daemon to connect to
+type SourceSpec struct {
+	Name    string
+	Address string // Socket path or remote address (see ipc.Dial)
+}
+
+// ParseSourceSpec parses "name=address". Without a name, the host of a
+// remote address or the socket file name is used.
+func ParseSourceSpec(spec string) (SourceSpec, error) {
+	name, address, found := strings.Cut(spec, "=")
+	if !found {
+		name, address = "", spec
+	}
+	name = strings.TrimSpace(name)
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return SourceSpec{}, fmt.Errorf("daemon %q has no address", spec)
+	}
+
+	if name == "" {
+		name = defaultSourceName(address)
+	}
+	return SourceSpec{Name: name, Address: address}, nil
+}
+
+// defaultSourceName derives a short display name from a daemon address
+func defaultSourceName(address string) string {
+	trimmed := address
+	for _, scheme := range []string{"tls://", "unix://"} {
+		trimmed = strings.TrimPrefix(trimmed, scheme)
+	}
+	if host, _, err := net.SplitHostPort(trimmed); err == nil && !strings.ContainsRune(trimmed, '/') {
+		if host == "" {
+			return "localhost"
+		}
+		// Keep the first label of a hostname; IP addresses are shown in full
+		if net.ParseIP(host) == nil {
+			host, _, _ = strings.Cut(host, ".")
+		}
+		return host
+	}
+	return strings.TrimSuffix(filepath.Base(trimmed), ".sock")
+}
+
+// ConnectSources connects to every daemon in specs. Source names must be
+// unique. On error, already opened connections are closed.
+func ConnectSources(specs []string, opts ipc.RemoteOptions) ([]Source, error) {
+	var sources []Source
+	closeAll := func() {
+		for _, src := range sources {
+			src.Client.Close()
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, raw := range specs {
+		spec, err := ParseSourceSpec(raw)
+		if err != nil {
+			closeAll()
+			return nil, err
+		}
+		if seen[spec.Name] {
+			closeAll()
+			return nil, fmt.Errorf("duplicate daemon name %q (use name=address)", spec.Name)
+		}
+		seen[spec.Name] = true
+
+		client, err := ipc.Dial(spec.Address, opts)
+		if err != nil {
+			closeAll()
+			return nil, fmt.Errorf("%s: %w", spec.Name, err)
+		}
+		sources = append(sources, Source{Name: spec.Name, Client: client})
+	}
+	return sources, nil
+}
@@ -0,0 +1,31 @@
+package tui
+
+import "testing"
+
+func TestParseSourceSpec(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    SourceSpec
+		wantErr bool
+	}{
+		{spec: "sg=tls://probe-sg.example.com:7443", want: SourceSpec{Name: "sg", Address: "tls://probe-sg.example.com:7443"}},
+		{spec: "tls://probe-sg.example.com:7443", want: SourceSpec{Name: "probe-sg", Address: "tls://probe-sg.example.com:7443"}},
+		{spec: "10.0.0.5:7443", want: SourceSpec{Name: "10.0.0.5", Address: "10.0.0.5:7443"}},
+		{spec: ":7443", want: SourceSpec{Name: "localhost", Address: ":7443"}},
+		{spec: "/run/pulse/pulse.sock", want: SourceSpec{Name: "pulse", Address: "/run/pulse/pulse.sock"}},
+		{spec: "local=unix:///run/pulse/pulse.sock", want: SourceSpec{Name: "local", Address: "unix:///run/pulse/pulse.sock"}},
+		{spec: "sg=", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSourceSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSourceSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseSourceSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
@@ -65,6 +65,11 @@ var (
 	LatencyBadStyle  = lipgloss.NewStyle().Foreground(ColorDanger)
 	LossStyle        = lipgloss.NewStyle().Foreground(ColorDanger)
 	SuccessStyle     = lipgloss.NewStyle().Foreground(ColorSuccess)
+	AnomalyStyle     = lipgloss.NewStyle().Foreground(ColorWarning).Bold(true)
+	MaintenanceStyle = lipgloss.NewStyle().Foreground(ColorPrimary)
+
+	// Graph crosshair style
+	CursorStyle = lipgloss.NewStyle().Foreground(ColorText).Bold(true)
 
 	// Help style
 	HelpStyle = lipgloss.NewStyle().
@@ -155,7 +160,7 @@ func formatPercent(pct float64) string {
 	if pct == 0 {
 		return "0.0%"
 	}
-	return sprintf("%.1f%%", pct)
+	return sprintf("%.1f%", pct) // stringFormat does not unescape %%
 }
 
 // sprintf is a simple wrapper
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -16,7 +17,10 @@ type (
 	ProbeResultMsg probe.ProbeResult
 
 	// IPCProbeResultMsg is sent when a probe result is received via IPC
-	IPCProbeResultMsg ipc.ProbeResultData
+	IPCProbeResultMsg struct {
+		Source string
+		Result ipc.ProbeResultData
+	}
 
 	// TickMsg is sent periodically for refresh
 	TickMsg struct{}
@@ -26,8 +30,10 @@ type (
 
 	// HistoricalDataMsg carries fetched historical data
 	HistoricalDataMsg struct {
+		Source     string
 		TargetName string
 		TimeRange  TimeRange
+		Window     TimeWindow // Period fetched
 		Data       []storage.DataPoint
 		Stats      *PeriodStats
 		Err        error
@@ -35,9 +41,13 @@ type (
 
 	// IPCStatsMsg carries stats fetched via IPC
 	IPCStatsMsg struct {
-		TargetName string
-		Stats      *storage.Stats
-		Err        error
+		Source      string
+		TargetName  string
+		Stats       *storage.Stats
+		Anomaly     *collector.Anomaly
+		Maintenance *collector.Maintenance
+		Dependency  *collector.Dependency
+		Err         error
 	}
 )
 
@@ -58,16 +68,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, waitForResult(m.resultsChan)
 
 	case IPCProbeResultMsg:
-		m.updateTargetStatsFromIPC(ipc.ProbeResultData(msg))
-		return m, waitForIPCResult(m.ipcResults)
+		client := m.client(msg.Source)
+		next := waitForIPCResult(msg.Source, client.Results())
+		if !m.updateTargetStatsFromIPC(msg.Source, msg.Result) {
+			return m, next
+		}
+		return m, tea.Batch(next, fetchStatsIPC(client, msg.Source, msg.Result.Target))
 
 	case IPCStatsMsg:
 		if msg.Err == nil && msg.Stats != nil {
-			for i := range m.targets {
-				if m.targets[i].Config.Name == msg.TargetName {
-					m.targets[i].Stats = msg.Stats
-					break
-				}
+			if i := m.findTarget(msg.Source, msg.TargetName); i >= 0 {
+				m.targets[i].Stats = msg.Stats
+				m.targets[i].Anomaly = msg.Anomaly
+				m.targets[i].Maintenance = msg.Maintenance
+				m.targets[i].Dependency = msg.Dependency
 			}
 		}
 		return m, nil
@@ -82,6 +96,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case HistoricalDataMsg:
 		return m.handleHistoricalData(msg)
+
+	case EventsMsg:
+		return m.handleEvents(msg)
+
+	case SilenceMsg:
+		return m.handleSilence(msg)
 	}
 
 	return m, nil
@@ -90,7 +110,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 // handleHistoricalData processes fetched historical data
 func (m Model) handleHistoricalData(msg HistoricalDataMsg) (tea.Model, tea.Cmd) {
 	for i := range m.targets {
-		if m.targets[i].Config.Name == msg.TargetName {
+		if m.targets[i].Source == msg.Source && m.targets[i].Config.Name == msg.TargetName {
 			m.targets[i].LoadingHistory = false
 
 			if msg.Err != nil {
@@ -98,6 +118,15 @@ func (m Model) handleHistoricalData(msg HistoricalDataMsg) (tea.Model, tea.Cmd)
 				return m, nil
 			}
 
+			// A custom window only updates the target while it is shown
+			if msg.TimeRange == TimeRangeCustom {
+				if m.targets[i].TimeRange == TimeRangeCustom && msg.Window.Equal(m.targets[i].Window) {
+					m.targets[i].HistoricalData = msg.Data
+					m.targets[i].WindowStats = msg.Stats
+				}
+				break
+			}
+
 			// Store the data if it's for the current time range
 			if msg.TimeRange == m.targets[i].TimeRange {
 				m.targets[i].HistoricalData = msg.Data
@@ -121,9 +150,9 @@ func (m Model) handleHistoricalData(msg HistoricalDataMsg) (tea.Model, tea.Cmd)
 			if m.IsIPCMode() {
 				switch msg.TimeRange {
 				case TimeRange1Hour:
-					return m, m.fetchHistoricalDataCmd(msg.TargetName, TimeRange1Day)
+					return m, m.fetchHistoricalDataCmd(msg.Source, msg.TargetName, TimeRange1Day)
 				case TimeRange1Day:
-					return m, m.fetchHistoricalDataCmd(msg.TargetName, TimeRange1Week)
+					return m, m.fetchHistoricalDataCmd(msg.Source, msg.TargetName, TimeRange1Week)
 				}
 			}
 			break
@@ -139,6 +168,12 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m.handleListViewKeys(msg)
 	case DetailView:
 		return m.handleDetailViewKeys(msg)
+	case CompareView:
+		return m.handleCompareViewKeys(msg)
+	case EventsView:
+		return m.handleEventsViewKeys(msg)
+	case OverlayView:
+		return m.handleOverlayViewKeys(msg)
 	}
 	return m, nil
 }
@@ -164,9 +199,29 @@ func (m Model) handleListViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Fetch historical data for summary
 		target := m.SelectedTarget()
 		if target != nil {
-			return m, m.fetchAllHistorical(target.Config.Name)
+			return m, m.fetchAllHistorical(target.Source, target.Config.Name)
 		}
 
+	case "c":
+		// Compare the selected target across sources
+		return m.openCompare()
+
+	case "m":
+		// Mark the selected target for the overlay
+		return m.toggleMark()
+
+	case "o":
+		// Overlay the marked targets on one graph
+		return m.openOverlay()
+
+	case "e":
+		// Incident log of every source
+		return m.openEvents()
+
+	case "s":
+		// Silence the selected target for an hour, or lift its silence
+		return m.toggleSilence()
+
 	case "home":
 		m.selectedIdx = 0
 
@@ -183,11 +238,20 @@ func (m Model) handleListViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 
 // handleDetailViewKeys handles keys in detail view
 func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.jumping {
+		return m.handleJumpKeys(msg)
+	}
+
 	switch msg.String() {
 	case "q", "ctrl+c":
 		return m, tea.Quit
 
 	case "esc", "backspace":
+		// Hide the crosshair first, then go back
+		if target := m.SelectedTarget(); target != nil && !target.Cursor.IsZero() {
+			target.Cursor = time.Time{}
+			return m, nil
+		}
 		m.currentView = ListView
 
 	case "up", "k":
@@ -196,7 +260,7 @@ func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Fetch historical data for new target
 			target := m.SelectedTarget()
 			if target != nil {
-				return m, m.fetchAllHistorical(target.Config.Name)
+				return m, m.fetchAllHistorical(target.Source, target.Config.Name)
 			}
 		}
 
@@ -206,7 +270,7 @@ func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Fetch historical data for new target
 			target := m.SelectedTarget()
 			if target != nil {
-				return m, m.fetchAllHistorical(target.Config.Name)
+				return m, m.fetchAllHistorical(target.Source, target.Config.Name)
 			}
 		}
 
@@ -215,9 +279,18 @@ func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		m.refreshAllStats()
 		target := m.SelectedTarget()
 		if target != nil {
-			return m, m.fetchAllHistorical(target.Config.Name)
+			return m, m.fetchAllHistorical(target.Source, target.Config.Name)
 		}
 
+	case "c":
+		return m.openCompare()
+
+	case "e":
+		return m.openEvents()
+
+	case "s":
+		return m.toggleSilence()
+
 	case "0":
 		// Realtime view
 		return m.setTimeRange(TimeRangeRealtime)
@@ -240,6 +313,32 @@ func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if target != nil {
 			return m.setTimeRange(target.TimeRange.Next())
 		}
+
+	case "left", "h":
+		// Pan back by half the window
+		return m.panGraph(-0.5)
+
+	case "right", "l":
+		return m.panGraph(0.5)
+
+	case "+", "=":
+		// Zoom in around the crosshair
+		return m.zoomGraph(0.5)
+
+	case "-", "_":
+		return m.zoomGraph(2)
+
+	case "<", ",":
+		// Move the crosshair by a graph column
+		return m.moveCursor(-1)
+
+	case ">", ".":
+		return m.moveCursor(1)
+
+	case "g":
+		// Jump to a date or time
+		m.jumping = true
+		m.jumpInput = ""
 	}
 
 	return m, nil
@@ -248,37 +347,58 @@ func (m Model) handleDetailViewKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 // setTimeRange sets the time range for the selected target and fetches data
 func (m Model) setTimeRange(tr TimeRange) (tea.Model, tea.Cmd) {
 	if m.selectedIdx >= 0 && m.selectedIdx < len(m.targets) {
+		wasCustom := m.targets[m.selectedIdx].TimeRange == TimeRangeCustom
 		m.targets[m.selectedIdx].TimeRange = tr
+		m.targets[m.selectedIdx].Cursor = time.Time{}
 		m.targets[m.selectedIdx].LoadingHistory = true
 
+		target := m.targets[m.selectedIdx]
+		var cmds []tea.Cmd
+		if wasCustom {
+			// Incidents were fetched for the custom window
+			cmds = append(cmds, m.fetchEventsCmd(target.Source, target.Config.Name))
+		}
+
 		if tr == TimeRangeRealtime {
 			// No need to fetch for realtime
 			m.targets[m.selectedIdx].LoadingHistory = false
-			return m, nil
+			return m, tea.Batch(cmds...)
 		}
 
-		return m, m.fetchHistoricalDataCmd(m.targets[m.selectedIdx].Config.Name, tr)
+		cmds = append(cmds, m.fetchHistoricalDataCmd(target.Source, target.Config.Name, tr))
+		return m, tea.Batch(cmds...)
 	}
 	return m, nil
 }
 
-// fetchAllHistorical returns commands to fetch all historical periods for a target
-func (m Model) fetchAllHistorical(targetName string) tea.Cmd {
+// fetchAllHistorical returns commands to fetch all historical periods and
+// recent incidents for a target
+func (m Model) fetchAllHistorical(source, targetName string) tea.Cmd {
 	// Both direct mode and IPC mode can now fetch in parallel
 	// (IPC race condition fixed by holding lock during channel send)
 	return tea.Batch(
-		m.fetchHistoricalDataCmd(targetName, TimeRange1Hour),
-		m.fetchHistoricalDataCmd(targetName, TimeRange1Day),
-		m.fetchHistoricalDataCmd(targetName, TimeRange1Week),
+		m.fetchHistoricalDataCmd(source, targetName, TimeRange1Hour),
+		m.fetchHistoricalDataCmd(source, targetName, TimeRange1Day),
+		m.fetchHistoricalDataCmd(source, targetName, TimeRange1Week),
+		m.fetchEventsCmd(source, targetName),
 	)
 }
 
 // fetchHistoricalDataCmd returns the appropriate command based on mode (direct or IPC)
-func (m Model) fetchHistoricalDataCmd(targetName string, tr TimeRange) tea.Cmd {
+func (m Model) fetchHistoricalDataCmd(source, targetName string, tr TimeRange) tea.Cmd {
+	w := tr.Window(time.Now())
 	if m.IsIPCMode() {
-		return fetchHistoricalDataIPC(m.ipcClient, targetName, tr)
+		return fetchHistoricalDataIPC(m.client(source), source, targetName, tr, w)
 	}
-	return fetchHistoricalData(m.collector, targetName, tr)
+	return fetchHistoricalData(m.collector, targetName, tr, w)
+}
+
+// fetchWindowCmd returns the command fetching a custom window of history
+func (m Model) fetchWindowCmd(source, targetName string, w TimeWindow) tea.Cmd {
+	if m.IsIPCMode() {
+		return fetchHistoricalDataIPC(m.client(source), source, targetName, TimeRangeCustom, w)
+	}
+	return fetchHistoricalData(m.collector, targetName, TimeRangeCustom, w)
 }
 
 // waitForResult creates a command that waits for a probe result
@@ -292,17 +412,16 @@ func waitForResult(ch <-chan probe.ProbeResult) tea.Cmd {
 	}
 }
 
-// fetchHistoricalData creates a command to fetch historical data from storage
-func fetchHistoricalData(coll *collector.Collector, targetName string, tr TimeRange) tea.Cmd {
+// fetchHistoricalData creates a command to fetch the historical data of a
+// window from storage
+func fetchHistoricalData(coll *collector.Collector, targetName string, tr TimeRange, w TimeWindow) tea.Cmd {
 	return func() tea.Msg {
-		now := time.Now()
-		from := now.Add(-tr.Duration())
-
-		data, err := coll.FetchHistory(targetName, from, now)
+		data, err := coll.FetchHistory(targetName, w.From, w.To)
 		if err != nil {
 			return HistoricalDataMsg{
 				TargetName: targetName,
 				TimeRange:  tr,
+				Window:     w,
 				Err:        err,
 			}
 		}
@@ -313,34 +432,38 @@ func fetchHistoricalData(coll *collector.Collector, targetName string, tr TimeRa
 		return HistoricalDataMsg{
 			TargetName: targetName,
 			TimeRange:  tr,
+			Window:     w,
 			Data:       data,
 			Stats:      stats,
 		}
 	}
 }
 
-// waitForIPCResult creates a command that waits for a probe result from IPC
-func waitForIPCResult(ch <-chan ipc.ProbeResultData) tea.Cmd {
+// waitForIPCResult creates a command that waits for a probe result from a daemon
+func waitForIPCResult(source string, ch <-chan ipc.ProbeResultData) tea.Cmd {
 	return func() tea.Msg {
 		result, ok := <-ch
 		if !ok {
+			if source != "" {
+				return ErrMsg{Err: fmt.Errorf("lost connection to %s", source)}
+			}
 			return ErrMsg{Err: nil} // Channel closed
 		}
-		return IPCProbeResultMsg(result)
+		return IPCProbeResultMsg{Source: source, Result: result}
 	}
 }
 
-// fetchHistoricalDataIPC creates a command to fetch historical data via IPC
-func fetchHistoricalDataIPC(client *ipc.Client, targetName string, tr TimeRange) tea.Cmd {
+// fetchHistoricalDataIPC creates a command to fetch the historical data of a
+// window via IPC
+func fetchHistoricalDataIPC(client *ipc.Client, source, targetName string, tr TimeRange, w TimeWindow) tea.Cmd {
 	return func() tea.Msg {
-		now := time.Now()
-		from := now.Add(-tr.Duration())
-
-		data, err := client.GetHistory(targetName, from, now)
+		data, err := client.GetHistory(targetName, w.From, w.To)
 		if err != nil {
 			return HistoricalDataMsg{
+				Source:     source,
 				TargetName: targetName,
 				TimeRange:  tr,
+				Window:     w,
 				Err:        err,
 			}
 		}
@@ -349,8 +472,10 @@ func fetchHistoricalDataIPC(client *ipc.Client, targetName string, tr TimeRange)
 		stats := calculatePeriodStats(data, 10*time.Second)
 
 		return HistoricalDataMsg{
+			Source:     source,
 			TargetName: targetName,
 			TimeRange:  tr,
+			Window:     w,
 			Data:       data,
 			Stats:      stats,
 		}
@@ -358,13 +483,20 @@ func fetchHistoricalDataIPC(client *ipc.Client, targetName string, tr TimeRange)
 }
 
 // fetchStatsIPC creates a command to fetch stats via IPC
-func fetchStatsIPC(client *ipc.Client, targetName string) tea.Cmd {
+func fetchStatsIPC(client *ipc.Client, source, targetName string) tea.Cmd {
 	return func() tea.Msg {
-		stats, err := client.GetStats(targetName)
+		resp, err := client.GetTargetStats(targetName)
+		if err != nil {
+			return IPCStatsMsg{Source: source, TargetName: targetName, Err: err}
+		}
 		return IPCStatsMsg{
+			Source:     source,
 			TargetName: targetName,
-			Stats:      stats,
-			Err:        err,
+			Stats:      resp.Stats,
+			Anomaly:    resp.Anomaly,
+
+			Maintenance: resp.Maintenance,
+			Dependency:  resp.Dependency,
 		}
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/wellsgz/pulse/internal/collector"
+	"github.com/wellsgz/pulse/internal/probe"
 	"github.com/wellsgz/pulse/internal/storage"
 	"github.com/wellsgz/pulse/internal/tui/components"
 )
@@ -21,6 +23,12 @@ func (m Model) View() string {
 		return m.renderListView()
 	case DetailView:
 		return m.renderDetailView()
+	case CompareView:
+		return m.renderCompareView()
+	case EventsView:
+		return m.renderEventsView()
+	case OverlayView:
+		return m.renderOverlayView()
 	default:
 		return m.renderListView()
 	}
@@ -65,9 +73,17 @@ func (m Model) renderError() string {
 func (m Model) renderHeader() string {
 	title := TitleStyle.Render(" pulse ")
 	subtitle := SubtitleStyle.Render("Network Latency Monitor")
+	info := fmt.Sprintf("API: %s", m.apiAddr)
+	if m.MultiSource() {
+		names := make([]string, len(m.sources))
+		for i, src := range m.sources {
+			names[i] = src.Name
+		}
+		info = "Daemons: " + strings.Join(names, ", ")
+	}
 	apiInfo := lipgloss.NewStyle().
 		Foreground(ColorMuted).
-		Render(fmt.Sprintf("API: %s", m.apiAddr))
+		Render(info)
 
 	left := lipgloss.JoinHorizontal(lipgloss.Center, title, "  ", subtitle)
 
@@ -88,6 +104,9 @@ func (m Model) renderHeader() string {
 // renderTable renders the targets table
 func (m Model) renderTable() string {
 	columns := components.AdaptiveColumns(m.width)
+	if m.MultiSource() {
+		columns = components.SourceColumns(m.width)
+	}
 	table := components.NewTable(columns)
 
 	var rows []string
@@ -98,7 +117,7 @@ func (m Model) renderTable() string {
 
 	// Rows
 	for i, target := range m.targets {
-		row := m.renderTargetRow(target, columns[4].Width)
+		row := m.renderTargetRow(target, columns[len(columns)-1].Width)
 		rows = append(rows, table.RenderRow(row, i == m.selectedIdx))
 	}
 
@@ -107,10 +126,26 @@ func (m Model) renderTable() string {
 
 // renderTargetRow renders a single target row
 func (m Model) renderTargetRow(target TargetState, sparklineWidth int) []string {
-	// Name
-	name := target.Config.Name
-	if len(name) > 16 {
-		name = name[:15] + "…"
+	// Name, with flags for targets marked for the overlay in their series
+	// color, targets deviating from their baseline, targets whose alerts are
+	// suppressed and targets whose parent is down
+	var flags []string
+	if target.Marked {
+		color := m.seriesColor(m.findTarget(target.Source, target.Config.Name))
+		flags = append(flags, lipgloss.NewStyle().Foreground(color).Render("●"))
+	}
+	if target.Anomaly != nil {
+		flags = append(flags, AnomalyStyle.Render("◆"))
+	}
+	if target.Maintenance != nil {
+		flags = append(flags, MaintenanceStyle.Render("‖"))
+	}
+	if dep := target.Dependency; dep != nil && dep.DownParent != "" {
+		flags = append(flags, dependencyStyle(dep).Render("⇡"))
+	}
+	name := truncate(target.Config.Name, 16-2*len(flags))
+	for _, flag := range flags {
+		name += " " + flag
 	}
 
 	// Get stats
@@ -129,6 +164,9 @@ func (m Model) renderTargetRow(target TargetState, sparklineWidth int) []string
 	// Sparkline
 	sparkline := components.Sparkline(target.History, sparklineWidth)
 
+	if m.MultiSource() {
+		return []string{name, truncate(target.Source, 10), last, avg, loss, sparkline}
+	}
 	return []string{name, last, avg, loss, sparkline}
 }
 
@@ -144,8 +182,15 @@ func (m Model) renderDetailView() string {
 	// Header
 	headerText := fmt.Sprintf(" %s (%s) - %s ",
 		target.Config.Name,
-		target.Config.Host,
+		target.Config.Endpoint(),
 		strings.ToUpper(target.Config.Probe))
+	if target.Source != "" {
+		headerText = fmt.Sprintf(" %s @ %s (%s) - %s ",
+			target.Config.Name,
+			target.Source,
+			target.Config.Endpoint(),
+			strings.ToUpper(target.Config.Probe))
+	}
 	header := TitleStyle.Render(headerText)
 	backHint := lipgloss.NewStyle().Foreground(ColorMuted).Render("[Esc] back")
 
@@ -168,6 +213,24 @@ func (m Model) renderDetailView() string {
 		b.WriteString("\n\n")
 	}
 
+	// Anomaly (if any)
+	if target.Anomaly != nil {
+		b.WriteString(renderAnomaly(target.Anomaly))
+		b.WriteString("\n\n")
+	}
+
+	// Maintenance (if any)
+	if target.Maintenance != nil {
+		b.WriteString(renderMaintenance(target.Maintenance))
+		b.WriteString("\n\n")
+	}
+
+	// Dependency (if any)
+	if target.Dependency != nil {
+		b.WriteString(renderDependency(target.Dependency))
+		b.WriteString("\n\n")
+	}
+
 	// Stats section - show appropriate stats based on time range
 	b.WriteString(m.renderStatsSection(target))
 	b.WriteString("\n")
@@ -180,28 +243,80 @@ func (m Model) renderDetailView() string {
 	b.WriteString(m.renderHistoricalSummary(target))
 	b.WriteString("\n")
 
-	// Help
-	b.WriteString(m.renderDetailHelp())
+	// Help, or the time being entered after g
+	if m.jumping {
+		b.WriteString(m.renderJumpPrompt())
+	} else {
+		b.WriteString(m.renderDetailHelp())
+	}
 
 	return b.String()
 }
 
+// dependencyStyle colors the state of the target a target depends on:
+// unreachable targets are muted, as their parent's alert covers them
+func dependencyStyle(dep *collector.Dependency) lipgloss.Style {
+	if dep.Unreachable {
+		return lipgloss.NewStyle().Foreground(ColorMuted).Bold(true)
+	}
+	return AnomalyStyle
+}
+
+// renderDependency describes the state of the target a target depends on
+func renderDependency(dep *collector.Dependency) string {
+	muted := lipgloss.NewStyle().Foreground(ColorMuted)
+	switch {
+	case dep.Unreachable:
+		return dependencyStyle(dep).Render("⇡ Unreachable: "+dep.DownParent+" is down") + " " +
+			muted.Render("since "+dep.Since.Format("15:04:05")+", alerts attributed to "+dep.DownParent)
+	case dep.DownParent != "":
+		return dependencyStyle(dep).Render("⇡ "+dep.DownParent+" is down") + " " +
+			muted.Render("(depends on "+dep.DependsOn+")")
+	default:
+		return muted.Render("Depends on " + dep.DependsOn)
+	}
+}
+
+// renderAnomaly describes how a target deviates from its baseline
+func renderAnomaly(a *collector.Anomaly) string {
+	var what string
+	switch a.Kind {
+	case collector.AnomalyLoss:
+		what = fmt.Sprintf("loss %.1f%%, baseline %.1f%%", a.LossPct, a.Baseline.LossPct)
+	case collector.AnomalyLatencyLow:
+		what = fmt.Sprintf("latency %s, below baseline %s", formatMs(a.LatencyMs), formatMs(a.Baseline.MedianMs))
+	default:
+		what = fmt.Sprintf("latency %s, above baseline %s", formatMs(a.LatencyMs), formatMs(a.Baseline.MedianMs))
+	}
+	since := lipgloss.NewStyle().Foreground(ColorMuted).Render("since " + a.Since.Format("15:04:05"))
+	return AnomalyStyle.Render("◆ Anomaly: "+what) + " " + since
+}
+
+// graphYAxisWidth is the width of the detail graph's Y-axis labels
+const graphYAxisWidth = 8
+
+// graphWidth returns the width of the detail graph including its Y-axis
+func (m Model) graphWidth() int {
+	return min(m.width-4, 80)
+}
+
 // renderGraphSection renders the graph with time range tabs
 func (m Model) renderGraphSection(target *TargetState) string {
 	var b strings.Builder
 
-	// Section header with tabs
+	// Section header with tabs, and the window panned or zoomed to
 	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary)
 	b.WriteString(sectionStyle.Render("Latency Graph"))
 	b.WriteString("  ")
 	b.WriteString(m.renderTimeRangeTabs(target.TimeRange))
+	if target.TimeRange == TimeRangeCustom {
+		b.WriteString("  ")
+		b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorText).Render(target.Window.String()))
+	}
 	b.WriteString("\n")
 
 	// Calculate graph dimensions
-	graphWidth := m.width - 4
-	if graphWidth > 80 {
-		graphWidth = 80
-	}
+	graphWidth := m.graphWidth()
 	graphHeight := 8
 
 	// Loading indicator
@@ -225,7 +340,8 @@ func (m Model) renderGraphSection(target *TargetState) string {
 			to = graphPoints[len(graphPoints)-1].Timestamp
 		}
 	} else {
-		// Use historical data from storage
+		// Use historical data from storage, over the whole window when
+		// panned or zoomed to one
 		if len(target.HistoricalData) > 0 {
 			graphPoints = make([]components.GraphPoint, len(target.HistoricalData))
 			for i, dp := range target.HistoricalData {
@@ -234,8 +350,8 @@ func (m Model) renderGraphSection(target *TargetState) string {
 					Value:     dp.Value,
 				}
 			}
-			from = target.HistoricalData[0].Timestamp
-			to = target.HistoricalData[len(target.HistoricalData)-1].Timestamp
+			w := target.graphWindow(time.Now())
+			from, to = w.From, w.To
 		}
 	}
 
@@ -245,11 +361,23 @@ func (m Model) renderGraphSection(target *TargetState) string {
 		Height:     graphHeight,
 		ShowYAxis:  true,
 		ShowXAxis:  true,
-		YAxisWidth: 8,
+		YAxisWidth: graphYAxisWidth,
+		Markers:    incidentMarkers(target.Incidents, time.Now()),
+		Cursor:     target.Cursor,
 	}
 
 	if len(graphPoints) > 0 {
 		b.WriteString(components.GraphWithRange(graphPoints, from, to, config))
+		if len(target.Incidents) > 0 {
+			b.WriteString("\n")
+			b.WriteString(strings.Repeat(" ", config.YAxisWidth))
+			b.WriteString(renderMarkerLegend())
+		}
+		if !target.Cursor.IsZero() {
+			b.WriteString("\n")
+			b.WriteString(strings.Repeat(" ", config.YAxisWidth))
+			b.WriteString(renderCursor(target))
+		}
 	} else {
 		b.WriteString(components.Graph(nil, config))
 	}
@@ -349,14 +477,23 @@ func (m Model) formatPeriodStats(stats *PeriodStats) string {
 func (m Model) renderStatsSection(target *TargetState) string {
 	if target.TimeRange == TimeRangeRealtime {
 		// Show real-time stats from memory buffer
+		var out string
 		if target.Stats != nil {
-			return m.renderStats(target.Stats, "current run")
+			out = m.renderStats(target.Stats, "current run")
 		}
-		return ""
+		if target.Last != nil && target.Last.PingsSent > 0 {
+			out += m.renderLastBurst(target.Last)
+		}
+		return out
 	}
 
 	// Show historical stats for selected time range
-	if target.HistoricalStats == nil {
+	loading := target.HistoricalStats == nil
+	if target.TimeRange == TimeRangeCustom {
+		// Custom windows are fetched on their own
+		loading = target.LoadingHistory
+	}
+	if loading {
 		sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary)
 		return sectionStyle.Render("Statistics") + " (loading...)\n"
 	}
@@ -374,6 +511,9 @@ func (m Model) renderStatsSection(target *TargetState) string {
 	case TimeRange1Week:
 		periodStats = target.HistoricalStats.Week
 		label = "last week"
+	case TimeRangeCustom:
+		periodStats = target.WindowStats
+		label = target.Window.String()
 	}
 
 	if periodStats != nil {
@@ -428,6 +568,63 @@ func (m Model) renderStats(stats *storage.Stats, label string) string {
 	return b.String()
 }
 
+// renderLastBurst renders the burst statistics and RTTs of the latest probe
+func (m Model) renderLastBurst(result *probe.ProbeResult) string {
+	var b strings.Builder
+
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary)
+	labelStyle := lipgloss.NewStyle().Foreground(ColorMuted).Width(10)
+	valueStyle := lipgloss.NewStyle().Foreground(ColorText)
+
+	b.WriteString(sectionStyle.Render("Last Burst"))
+	b.WriteString(fmt.Sprintf(" (%d/%d received, %s)\n",
+		result.PingsRecv, result.PingsSent, result.Timestamp.Format("15:04:05")))
+
+	if result.PingsRecv > 0 {
+		// Row 1: Min, Max, Avg
+		b.WriteString("  ")
+		b.WriteString(labelStyle.Render("Min:"))
+		b.WriteString(FormatLatency(result.MinMs))
+		b.WriteString("  ")
+		b.WriteString(labelStyle.Render("Max:"))
+		b.WriteString(FormatLatency(result.MaxMs))
+		b.WriteString("  ")
+		b.WriteString(labelStyle.Render("Avg:"))
+		b.WriteString(FormatLatency(result.AvgMs))
+		b.WriteString("\n")
+	}
+
+	// Row 2: Jitter, Loss
+	b.WriteString("  ")
+	b.WriteString(labelStyle.Render("Jitter:"))
+	b.WriteString(valueStyle.Render(fmt.Sprintf("%.2fms", result.JitterMs)))
+	b.WriteString("  ")
+	b.WriteString(labelStyle.Render("Loss:"))
+	b.WriteString(FormatLoss(result.LossPct))
+	b.WriteString("\n")
+
+	if result.Error != "" {
+		b.WriteString("  ")
+		b.WriteString(labelStyle.Render("Error:"))
+		b.WriteString(valueStyle.Render(result.Error))
+		b.WriteString("\n")
+	}
+
+	// Row 3: individual RTTs, cut to the terminal width
+	if len(result.RTTsMs) > 0 {
+		rtts := make([]string, len(result.RTTsMs))
+		for i, rtt := range result.RTTsMs {
+			rtts[i] = fmt.Sprintf("%.1f", rtt)
+		}
+		b.WriteString("  ")
+		b.WriteString(labelStyle.Render("RTTs:"))
+		b.WriteString(valueStyle.Render(truncate(strings.Join(rtts, " "), m.width-14)))
+		b.WriteString("\n")
+	}
+
+	return b.String()
+}
+
 // renderPeriodStatsSection renders the statistics section from PeriodStats
 func (m Model) renderPeriodStatsSection(stats *PeriodStats, label string) string {
 	var b strings.Builder
@@ -480,6 +677,11 @@ func (m Model) renderHelp() string {
 	}{
 		{"↑/↓", "navigate"},
 		{"Enter", "details"},
+		{"c", "compare"},
+		{"m", "mark"},
+		{"o", "overlay"},
+		{"e", "events"},
+		{"s", "silence"},
 		{"r", "refresh"},
 		{"q", "quit"},
 	}
@@ -504,6 +706,13 @@ func (m Model) renderDetailHelp() string {
 		{"↑/↓", "targets"},
 		{"0-3", "range"},
 		{"Tab", "cycle"},
+		{"←/→", "pan"},
+		{"+/-", "zoom"},
+		{"</>", "cursor"},
+		{"g", "go to"},
+		{"c", "compare"},
+		{"e", "events"},
+		{"s", "silence"},
 		{"r", "refresh"},
 		{"q", "quit"},
 	}